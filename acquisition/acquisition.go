@@ -0,0 +1,300 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package acquisition implements the acquisition modes of a digital
+// oscilloscope: averaging over sweeps, peak detection and high resolution.
+package acquisition
+
+import (
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Device processes the samples of the underlying device according to
+// the selected acquisition mode.
+// Device implements both scope.Device interface (used by UI)
+// and scope.DataRecorder interface (used by underlying device).
+type Device struct {
+	scope.Device
+	rec        scope.DataRecorder
+	mode       *Mode
+	averages   *Count
+	decimation *Count
+}
+
+// New returns a Device in normal acquisition mode, passing the data of dev
+// unchanged.
+func New(dev scope.Device) *Device {
+	return &Device{
+		Device:     dev,
+		mode:       newModeParam(),
+		averages:   newCountParam(paramNameAverages, 16, 256),
+		decimation: newCountParam(paramNameDecimation, 16, 1024),
+	}
+}
+
+// Attach configures the device to pass the data to rec.
+func (d *Device) Attach(rec scope.DataRecorder) {
+	d.rec = rec
+	d.Device.Attach(d)
+}
+
+// TimeBase returns the timebase of the underlying recorder.
+func (d *Device) TimeBase() scope.Duration {
+	return d.rec.TimeBase()
+}
+
+// Reset initializes the recording. Changes of the acquisition params
+// take effect on the next Reset.
+// In peak detect and high resolution modes the interval passed to
+// the underlying recorder is longer than i, since every decimation bucket
+// produces two samples (minimum and maximum) or one sample (average)
+// respectively.
+func (d *Device) Reset(i scope.Duration, ch <-chan []scope.ChannelData) {
+	var tbCount int
+	if i > 0 {
+		tbCount = int(d.rec.TimeBase() / i)
+	}
+	var p processor
+	outInterval := i
+	switch *d.mode {
+	case ModeAverage:
+		p = newAverager(d.averages.n, tbCount)
+	case ModePeak:
+		outInterval = i * scope.Duration(d.decimation.n) / 2
+		p = newDecimator(d.decimation.n, tbCount, int(d.rec.TimeBase()/outInterval), true)
+	case ModeHighRes:
+		outInterval = i * scope.Duration(d.decimation.n)
+		p = newDecimator(d.decimation.n, tbCount, int(d.rec.TimeBase()/outInterval), false)
+	}
+	if p == nil {
+		d.rec.Reset(i, ch)
+		return
+	}
+	out := make(chan []scope.ChannelData, 2)
+	d.rec.Reset(outInterval, out)
+	go run(p, ch, out)
+}
+
+// Error passes the error down to the underlying recorder.
+func (d *Device) Error(err error) {
+	d.rec.Error(err)
+}
+
+// AcquisitionParams returns the acquisition params.
+func (d *Device) AcquisitionParams() []scope.Param {
+	return []scope.Param{
+		d.mode,
+		d.averages,
+		d.decimation,
+	}
+}
+
+// processor transforms the incoming chunks of data into outgoing chunks.
+type processor interface {
+	process([]scope.ChannelData) [][]scope.ChannelData
+}
+
+func run(p processor, in <-chan []scope.ChannelData, out chan<- []scope.ChannelData) {
+	for d := range in {
+		for _, c := range p.process(d) {
+			out <- c
+		}
+	}
+	close(out)
+}
+
+// averager averages the samples at the same position of consecutive sweeps.
+// The first n sweeps are averaged with equal weights, after that
+// each new sweep contributes 1/n to the average.
+// A sweep is tbCount samples, or each chunk if the timebase is 0.
+type averager struct {
+	n       int
+	tbCount int
+	count   int
+	pos     int
+	ids     []scope.ChanID
+	cur     map[scope.ChanID][]scope.Voltage
+	avg     map[scope.ChanID][]scope.Voltage
+}
+
+func newAverager(n, tbCount int) *averager {
+	return &averager{
+		n:       n,
+		tbCount: tbCount,
+		cur:     make(map[scope.ChanID][]scope.Voltage),
+		avg:     make(map[scope.ChanID][]scope.Voltage),
+	}
+}
+
+func (a *averager) process(data []scope.ChannelData) [][]scope.ChannelData {
+	if len(data) == 0 {
+		return nil
+	}
+	if len(a.ids) == 0 {
+		for _, c := range data {
+			a.ids = append(a.ids, c.ID)
+		}
+	}
+	var ret [][]scope.ChannelData
+	l := len(data[0].Samples)
+	tbCount := a.tbCount
+	if tbCount == 0 {
+		tbCount = l
+	}
+	for off := 0; off < l; {
+		k := tbCount - a.pos
+		if k > l-off {
+			k = l - off
+		}
+		for _, c := range data {
+			a.cur[c.ID] = append(a.cur[c.ID], c.Samples[off:off+k]...)
+		}
+		off += k
+		a.pos += k
+		if a.pos == tbCount {
+			ret = append(ret, a.sweep())
+			a.pos = 0
+		}
+	}
+	return ret
+}
+
+// sweep adds the current sweep to the average and returns the averaged data.
+func (a *averager) sweep() []scope.ChannelData {
+	if a.count < a.n {
+		a.count++
+	}
+	w := scope.Voltage(a.count)
+	ret := make([]scope.ChannelData, 0, len(a.ids))
+	for _, id := range a.ids {
+		cur := a.cur[id]
+		avg := a.avg[id]
+		if len(avg) != len(cur) {
+			avg = append([]scope.Voltage(nil), cur...)
+		} else {
+			for i, v := range cur {
+				avg[i] += (v - avg[i]) / w
+			}
+		}
+		a.avg[id] = avg
+		a.cur[id] = cur[:0]
+		ret = append(ret, scope.ChannelData{
+			ID:      id,
+			Samples: append([]scope.Voltage(nil), avg...),
+		})
+	}
+	return ret
+}
+
+// bucket accumulates the samples of a single decimation bucket.
+type bucket struct {
+	n        int
+	sum      scope.Voltage
+	min, max scope.Voltage
+	minFirst bool
+}
+
+func (b *bucket) add(v scope.Voltage) {
+	if b.n == 0 {
+		b.min, b.max, b.minFirst = v, v, true
+	}
+	switch {
+	case v < b.min:
+		b.min = v
+		b.minFirst = false
+	case v > b.max:
+		b.max = v
+		b.minFirst = true
+	}
+	b.sum += v
+	b.n++
+}
+
+// appendTo appends the minimum and maximum of the bucket in the order
+// they appeared, or the average of the bucket, to samples.
+func (b *bucket) appendTo(samples []scope.Voltage, peak bool) []scope.Voltage {
+	switch {
+	case !peak:
+		samples = append(samples, b.sum/scope.Voltage(b.n))
+	case b.minFirst:
+		samples = append(samples, b.min, b.max)
+	default:
+		samples = append(samples, b.max, b.min)
+	}
+	*b = bucket{}
+	return samples
+}
+
+// decimator reduces every size samples to a pair of minimum and maximum
+// (peak detect) or to their average (high resolution).
+// Buckets are aligned to the beginning of every sweep of tbCount samples,
+// a partial bucket at the end of the sweep is emitted as long as the sweep
+// does not exceed outCount samples expected by the recorder.
+type decimator struct {
+	size     int
+	tbCount  int
+	outCount int
+	peak     bool
+	pos      int
+	emitted  int
+	buckets  map[scope.ChanID]*bucket
+}
+
+func newDecimator(size, tbCount, outCount int, peak bool) *decimator {
+	return &decimator{
+		size:     size,
+		tbCount:  tbCount,
+		outCount: outCount,
+		peak:     peak,
+		buckets:  make(map[scope.ChanID]*bucket),
+	}
+}
+
+func (d *decimator) process(data []scope.ChannelData) [][]scope.ChannelData {
+	if len(data) == 0 {
+		return nil
+	}
+	var pos, emitted int
+	ret := make([]scope.ChannelData, len(data))
+	for i, c := range data {
+		b := d.buckets[c.ID]
+		if b == nil {
+			b = &bucket{}
+			d.buckets[c.ID] = b
+		}
+		pos, emitted = d.pos, d.emitted
+		ret[i].ID = c.ID
+		samples := make([]scope.Voltage, 0, 2*(len(c.Samples)/d.size+1))
+		for _, v := range c.Samples {
+			b.add(v)
+			pos++
+			sweepEnd := d.tbCount > 0 && pos == d.tbCount
+			if b.n < d.size && !sweepEnd {
+				continue
+			}
+			l := len(samples)
+			samples = b.appendTo(samples, d.peak)
+			emitted += len(samples) - l
+			if sweepEnd {
+				if emitted > d.outCount {
+					samples = samples[:len(samples)-(emitted-d.outCount)]
+				}
+				pos, emitted = 0, 0
+			}
+		}
+		ret[i].Samples = samples
+	}
+	d.pos, d.emitted = pos, emitted
+	return [][]scope.ChannelData{ret}
+}
@@ -0,0 +1,213 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package acquisition
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/testutil"
+)
+
+type fakeDev struct{}
+
+func (fakeDev) String() string            { return "fake" }
+func (fakeDev) Channels() []scope.ChanID  { return []scope.ChanID{"signal"} }
+func (fakeDev) Attach(scope.DataRecorder) {}
+func (fakeDev) Start()                    {}
+func (fakeDev) Stop()                     {}
+
+func TestAcquisition(t *testing.T) {
+	for _, tc := range []struct {
+		desc         string
+		tbLen        int
+		mode         string
+		averages     string
+		decimation   string
+		samples      [][]scope.Voltage
+		want         [][]scope.Voltage
+		wantInterval scope.Duration
+	}{
+		{
+			desc:  "normal mode passes the data",
+			tbLen: 4,
+			mode:  "normal",
+			samples: [][]scope.Voltage{
+				{1, 2, 3, 4},
+				{5, 6, 7, 8},
+			},
+			want: [][]scope.Voltage{
+				{1, 2, 3, 4},
+				{5, 6, 7, 8},
+			},
+			wantInterval: scope.Millisecond,
+		},
+		{
+			desc:     "average of two sweeps, then moving average",
+			tbLen:    4,
+			mode:     "average",
+			averages: "2",
+			samples: [][]scope.Voltage{
+				{1, 2, 3, 4},
+				{3, 4, 5, 6},
+				{1, 2, 3, 4},
+			},
+			want: [][]scope.Voltage{
+				{1, 2, 3, 4},
+				{2, 3, 4, 5},
+				{1.5, 2.5, 3.5, 4.5},
+			},
+			wantInterval: scope.Millisecond,
+		},
+		{
+			desc:     "average of sweeps split across chunks",
+			tbLen:    4,
+			mode:     "average",
+			averages: "4",
+			samples: [][]scope.Voltage{
+				{0, 0},
+				{0, 0, 4, 4},
+				{4, 4, 2},
+				{2, 2, 2},
+			},
+			want: [][]scope.Voltage{
+				{0, 0, 0, 0},
+				{2, 2, 2, 2},
+				{2, 2, 2, 2},
+			},
+			wantInterval: scope.Millisecond,
+		},
+		{
+			desc:       "peak detect keeps a single sample glitch",
+			tbLen:      8,
+			mode:       "peak",
+			decimation: "4",
+			samples: [][]scope.Voltage{
+				{0, 0, 5, 0, 0, -1, 0, 0},
+				{0, 1, 0, -3, 0, 0, 0, 0},
+			},
+			want: [][]scope.Voltage{
+				{0, 5, 0, -1},
+				{1, -3, 0, 0},
+			},
+			wantInterval: 2 * scope.Millisecond,
+		},
+		{
+			desc:       "high resolution averages the buckets",
+			tbLen:      8,
+			mode:       "hires",
+			decimation: "2",
+			samples: [][]scope.Voltage{
+				{0, 1, 1, 2, 2, 3, 3, 4},
+				{1, 1, 2, 2, 3, 3, 4, 4},
+			},
+			want: [][]scope.Voltage{
+				{0.5, 1.5, 2.5, 3.5},
+				{1, 2, 3, 4},
+			},
+			wantInterval: 2 * scope.Millisecond,
+		},
+		{
+			desc:       "high resolution with partial bucket at the end of the sweep",
+			tbLen:      5,
+			mode:       "hires",
+			decimation: "2",
+			samples: [][]scope.Voltage{
+				{1, 1, 2, 2, 9},
+				{3, 3, 4, 4, 9},
+			},
+			want: [][]scope.Voltage{
+				{1, 2},
+				{3, 4},
+			},
+			wantInterval: 2 * scope.Millisecond,
+		},
+		{
+			desc:       "peak detect with partial bucket at the end of the sweep",
+			tbLen:      6,
+			mode:       "peak",
+			decimation: "4",
+			samples: [][]scope.Voltage{
+				{1, 2, 3, 4, 5, 6},
+				{6, 5, 4, 3, 2, 1},
+			},
+			want: [][]scope.Voltage{
+				{1, 4, 5},
+				{6, 3, 2},
+			},
+			wantInterval: 2 * scope.Millisecond,
+		},
+	} {
+		buf := testutil.NewBufferRecorder(scope.Duration(tc.tbLen) * scope.Millisecond)
+		d := New(fakeDev{})
+		d.Attach(buf)
+		for _, p := range d.AcquisitionParams() {
+			var v string
+			switch p.Name() {
+			case paramNameMode:
+				v = tc.mode
+			case paramNameAverages:
+				v = tc.averages
+			case paramNameDecimation:
+				v = tc.decimation
+			}
+			if v == "" {
+				continue
+			}
+			if err := p.Set(v); err != nil {
+				t.Fatalf("%s: %s.Set(%q): %v", tc.desc, p.Name(), v, err)
+			}
+		}
+		ch := make(chan []scope.ChannelData, len(tc.samples))
+		for _, s := range tc.samples {
+			ch <- []scope.ChannelData{{ID: "signal", Samples: s}}
+		}
+		close(ch)
+		d.Reset(scope.Millisecond, ch)
+		got, err := buf.Wait()
+		if err != nil {
+			t.Fatalf("%s: recorder error: %v", tc.desc, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got sweeps %v, want %v", tc.desc, got, tc.want)
+		}
+		if buf.Interval() != tc.wantInterval {
+			t.Errorf("%s: got interval %v, want %v", tc.desc, buf.Interval(), tc.wantInterval)
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	d := New(fakeDev{})
+	for _, p := range d.AcquisitionParams() {
+		sp, ok := p.(scope.SelectParam)
+		if !ok {
+			t.Errorf("param %s is not a SelectParam", p.Name())
+			continue
+		}
+		for _, v := range sp.Values() {
+			if err := p.Set(v); err != nil {
+				t.Errorf("%s.Set(%q): %v", p.Name(), v, err)
+			}
+			if got := p.Value(); got != v {
+				t.Errorf("%s.Value() after Set(%q): got %q", p.Name(), v, got)
+			}
+		}
+		if err := p.Set("3"); err == nil {
+			t.Errorf("%s.Set(\"3\"): got nil error, want non-nil", p.Name())
+		}
+	}
+}
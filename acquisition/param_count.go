@@ -0,0 +1,67 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package acquisition
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	paramNameAverages   = "averages"
+	paramNameDecimation = "decimation"
+)
+
+// Count is a param selecting a power of two factor,
+// e.g. the number of averaged sweeps or the size of a decimation bucket.
+type Count struct {
+	name string
+	n    int
+	max  int
+}
+
+// Name returns the param name for UI.
+func (c *Count) Name() string { return c.name }
+
+// Value returns the current factor.
+func (c *Count) Value() string { return strconv.Itoa(c.n) }
+
+// Values returns all available factors.
+func (c *Count) Values() []string {
+	var ret []string
+	for n := 2; n <= c.max; n *= 2 {
+		ret = append(ret, strconv.Itoa(n))
+	}
+	return ret
+}
+
+// Set configures the factor.
+func (c *Count) Set(v string) error {
+	for _, s := range c.Values() {
+		if s == v {
+			c.n, _ = strconv.Atoi(v)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s %q, must be one of %v", c.name, v, c.Values())
+}
+
+func newCountParam(name string, n, max int) *Count {
+	return &Count{
+		name: name,
+		n:    n,
+		max:  max,
+	}
+}
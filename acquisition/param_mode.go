@@ -0,0 +1,79 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package acquisition
+
+import "fmt"
+
+const (
+	paramNameMode = "acquisition"
+)
+
+// Mode is the param selecting how the samples are acquired.
+type Mode int
+
+const (
+	// ModeNormal passes the samples unchanged.
+	ModeNormal = Mode(iota)
+	// ModeAverage averages the samples over consecutive sweeps.
+	ModeAverage
+	// ModePeak keeps the minimum and maximum of every decimation bucket,
+	// so that narrow glitches are not lost on slow timebases.
+	ModePeak
+	// ModeHighRes averages the samples of every decimation bucket,
+	// trading sample rate for vertical resolution.
+	ModeHighRes
+)
+
+// Name returns the param name for UI.
+func (Mode) Name() string { return paramNameMode }
+
+// Value returns the name of the current mode.
+func (m Mode) Value() string {
+	switch m {
+	case ModeAverage:
+		return "average"
+	case ModePeak:
+		return "peak"
+	case ModeHighRes:
+		return "hires"
+	}
+	return "normal"
+}
+
+// Values returns the names of all acquisition modes.
+func (Mode) Values() []string {
+	return []string{"normal", "average", "peak", "hires"}
+}
+
+// Set configures the acquisition mode.
+func (m *Mode) Set(v string) error {
+	switch v {
+	case "normal":
+		*m = ModeNormal
+	case "average":
+		*m = ModeAverage
+	case "peak":
+		*m = ModePeak
+	case "hires":
+		*m = ModeHighRes
+	default:
+		return fmt.Errorf("unknown acquisition mode %q, must be one of %v", v, m.Values())
+	}
+	return nil
+}
+
+func newModeParam() *Mode {
+	return new(Mode)
+}
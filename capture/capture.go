@@ -0,0 +1,64 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package capture saves the sweeps acquired from a device to CSV files
+// or compact binary capture files and reads them back, together with
+// the metadata describing the acquisition.
+package capture
+
+import (
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Param is the value of a device param at the time of the capture.
+type Param struct {
+	Name  string
+	Value string
+}
+
+// Metadata describes the captured sweeps.
+type Metadata struct {
+	// Device is the description of the device, as returned by its String.
+	Device string
+	// Interval is the sampling interval.
+	Interval scope.Duration
+	// TimeBase is the length of a sweep, 0 if unknown.
+	TimeBase scope.Duration
+	// TriggerPos is the index of the sample at which each sweep was
+	// triggered, -1 if the sweeps were not triggered.
+	TriggerPos int
+	// Params are the values of the device params, e.g. the trigger settings.
+	Params []Param
+}
+
+// ParamValues returns the current values of the params.
+func ParamValues(params []scope.Param) []Param {
+	ret := make([]Param, len(params))
+	for i, p := range params {
+		ret[i] = Param{Name: p.Name(), Value: p.Value()}
+	}
+	return ret
+}
+
+// Play resets rec to the sampling interval and passes it the sweeps,
+// one chunk per sweep, as a device would. Play returns once all the
+// sweeps were read by rec.
+func Play(rec scope.DataRecorder, interval scope.Duration, sweeps [][]scope.ChannelData) {
+	ch := make(chan []scope.ChannelData)
+	rec.Reset(interval, ch)
+	for _, s := range sweeps {
+		ch <- s
+	}
+	close(ch)
+}
@@ -0,0 +1,268 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package capture
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// The CSV files start with the metadata in comment lines:
+//
+//	# goscope capture
+//	# device: dummy device
+//	# interval: 0.001 s
+//	# timebase: 0.01 s
+//	# trigger position: 0
+//	# param mode: none
+//
+// followed by the header naming the columns with their units
+// and a row for every sample:
+//
+//	time (s),sin (V),square (V)
+//	0,0.5,1
+//	0.001,0.53,1
+//
+// The time is relative to the beginning of the sweep, a new sweep starts
+// when the time goes back to 0. The values are written with as many
+// digits as needed to read back the same values.
+
+const (
+	csvMagic    = "goscope capture"
+	csvComment  = "# "
+	csvTimeCol  = "time (s)"
+	csvUnitSfx  = " (V)"
+	csvParamKey = "param "
+)
+
+// CSVWriter writes the sweeps as CSV, or TSV if Comma is set to '\t'.
+type CSVWriter struct {
+	// Comma is the field delimiter, ',' by default.
+	// It must be set before the first WriteSweep.
+	Comma rune
+	w     *bufio.Writer
+	cw    *csv.Writer
+	md    Metadata
+	chans []scope.ChanID
+}
+
+// NewCSVWriter returns a CSVWriter writing the sweeps described by md to w.
+// The metadata and the header are written with the first sweep.
+func NewCSVWriter(w io.Writer, md Metadata) *CSVWriter {
+	return &CSVWriter{Comma: ',', w: bufio.NewWriter(w), md: md}
+}
+
+func seconds(d scope.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(scope.Second), 'g', -1, 64)
+}
+
+func (c *CSVWriter) writeHeader(data []scope.ChannelData) error {
+	lines := []string{
+		csvMagic,
+		"device: " + c.md.Device,
+		fmt.Sprintf("interval: %s s", seconds(c.md.Interval)),
+		fmt.Sprintf("timebase: %s s", seconds(c.md.TimeBase)),
+		fmt.Sprintf("trigger position: %d", c.md.TriggerPos),
+	}
+	for _, p := range c.md.Params {
+		lines = append(lines, fmt.Sprintf("%s%s: %s", csvParamKey, p.Name, p.Value))
+	}
+	for _, l := range lines {
+		if _, err := c.w.WriteString(csvComment + l + "\n"); err != nil {
+			return err
+		}
+	}
+	c.cw = csv.NewWriter(c.w)
+	c.cw.Comma = c.Comma
+	header := []string{csvTimeCol}
+	for _, d := range data {
+		c.chans = append(c.chans, d.ID)
+		header = append(header, string(d.ID)+csvUnitSfx)
+	}
+	return c.cw.Write(header)
+}
+
+// WriteSweep writes a sweep. All sweeps must have the same channels,
+// in the same order.
+func (c *CSVWriter) WriteSweep(data []scope.ChannelData) error {
+	if c.cw == nil {
+		if err := c.writeHeader(data); err != nil {
+			return err
+		}
+	}
+	if len(data) != len(c.chans) {
+		return fmt.Errorf("sweep has %d channels, want %d", len(data), len(c.chans))
+	}
+	n := -1
+	for i, d := range data {
+		if d.ID != c.chans[i] {
+			return fmt.Errorf("sweep channel %d is %s, want %s", i, d.ID, c.chans[i])
+		}
+		if n >= 0 && len(d.Samples) != n {
+			return fmt.Errorf("channel %s has %d samples, want %d", d.ID, len(d.Samples), n)
+		}
+		n = len(d.Samples)
+	}
+	row := make([]string, len(data)+1)
+	for i := 0; i < n; i++ {
+		row[0] = seconds(scope.Duration(i) * c.md.Interval)
+		for j, d := range data {
+			row[j+1] = strconv.FormatFloat(float64(d.Samples[i]), 'g', -1, 64)
+		}
+		if err := c.cw.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (c *CSVWriter) Flush() error {
+	if c.cw != nil {
+		c.cw.Flush()
+		if err := c.cw.Error(); err != nil {
+			return err
+		}
+	}
+	return c.w.Flush()
+}
+
+// parseSeconds parses the duration written by seconds, followed by " s".
+func parseSeconds(s string) (scope.Duration, error) {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(s, " s"), 64)
+	if err != nil {
+		return 0, err
+	}
+	return scope.Duration(math.Round(f * float64(scope.Second))), nil
+}
+
+// readMetadata reads the metadata from the comment lines at the beginning
+// of r, leaving r at the first line following them.
+func readMetadata(r *bufio.Reader) (Metadata, error) {
+	var md Metadata
+	first := true
+	for {
+		b, err := r.Peek(1)
+		if err != nil || b[0] != '#' {
+			if first {
+				return md, fmt.Errorf("not a goscope capture, missing the %q header", csvMagic)
+			}
+			return md, nil
+		}
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return md, err
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		if first {
+			if line != csvMagic {
+				return md, fmt.Errorf("not a goscope capture, got header %q, want %q", line, csvMagic)
+			}
+			first = false
+			continue
+		}
+		parts := strings.SplitN(line, ": ", 2)
+		if len(parts) != 2 {
+			// allow a trailing colon of an empty value.
+			parts = append(strings.SplitN(strings.TrimSuffix(line, ":"), ": ", 2), "")
+		}
+		key, value := parts[0], parts[1]
+		switch {
+		case key == "device":
+			md.Device = value
+		case key == "interval":
+			md.Interval, err = parseSeconds(value)
+		case key == "timebase":
+			md.TimeBase, err = parseSeconds(value)
+		case key == "trigger position":
+			md.TriggerPos, err = strconv.Atoi(value)
+		case strings.HasPrefix(key, csvParamKey):
+			md.Params = append(md.Params, Param{Name: strings.TrimPrefix(key, csvParamKey), Value: value})
+		}
+		// unknown keys are ignored, for compatibility with later versions.
+		if err != nil {
+			return md, fmt.Errorf("invalid %s %q: %v", key, value, err)
+		}
+	}
+}
+
+// ReadCSV reads the sweeps written by CSVWriter, as CSV or TSV.
+func ReadCSV(r io.Reader) (Metadata, [][]scope.ChannelData, error) {
+	br := bufio.NewReader(r)
+	md, err := readMetadata(br)
+	if err != nil {
+		return md, nil, err
+	}
+	header, err := br.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return md, nil, err
+	}
+	cr := csv.NewReader(io.MultiReader(strings.NewReader(header), br))
+	if strings.Contains(header, "\t") {
+		cr.Comma = '\t'
+	}
+	cols, err := cr.Read()
+	if err != nil {
+		return md, nil, fmt.Errorf("reading the header: %v", err)
+	}
+	if len(cols) < 2 || cols[0] != csvTimeCol {
+		return md, nil, fmt.Errorf("invalid header %q, want %q and the channels", cols, csvTimeCol)
+	}
+	var ids []scope.ChanID
+	for _, c := range cols[1:] {
+		if !strings.HasSuffix(c, csvUnitSfx) {
+			return md, nil, fmt.Errorf("invalid channel column %q, want \"ID%s\"", c, csvUnitSfx)
+		}
+		ids = append(ids, scope.ChanID(strings.TrimSuffix(c, csvUnitSfx)))
+	}
+
+	var sweeps [][]scope.ChannelData
+	var cur []scope.ChannelData
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return md, nil, err
+		}
+		t, err := strconv.ParseFloat(row[0], 64)
+		if err != nil {
+			return md, nil, fmt.Errorf("row %d: invalid time %q: %v", line, row[0], err)
+		}
+		if t == 0 || cur == nil {
+			cur = make([]scope.ChannelData, len(ids))
+			for i, id := range ids {
+				cur[i].ID = id
+			}
+			sweeps = append(sweeps, cur)
+		}
+		for i, v := range row[1:] {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return md, nil, fmt.Errorf("row %d: invalid sample of %s %q: %v", line, ids[i], v, err)
+			}
+			cur[i].Samples = append(cur[i].Samples, scope.Voltage(f))
+		}
+	}
+	return md, sweeps, nil
+}
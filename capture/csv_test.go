@@ -0,0 +1,143 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package capture
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/testutil"
+)
+
+var testMetadata = Metadata{
+	Device:     "dummy device",
+	Interval:   scope.Millisecond,
+	TimeBase:   3 * scope.Millisecond,
+	TriggerPos: 1,
+	Params: []Param{
+		{Name: "mode", Value: "normal"},
+		{Name: "level", Value: "0.5000"},
+		{Name: "empty", Value: ""},
+	},
+}
+
+var testSweeps = [][]scope.ChannelData{
+	{
+		{ID: "sin", Samples: []scope.Voltage{0, 0.5, -1.25}},
+		{ID: "square", Samples: []scope.Voltage{1, 1, -1}},
+	},
+	{
+		{ID: "sin", Samples: []scope.Voltage{0.1, 1e-7, 3}},
+		{ID: "square", Samples: []scope.Voltage{-1, -1, 1}},
+	},
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		desc   string
+		comma  rune
+		header string
+	}{
+		{"csv", ',', "time (s),sin (V),square (V)\n"},
+		{"tsv", '\t', "time (s)\tsin (V)\tsquare (V)\n"},
+	} {
+		var buf bytes.Buffer
+		w := NewCSVWriter(&buf, testMetadata)
+		w.Comma = tc.comma
+		for _, s := range testSweeps {
+			if err := w.WriteSweep(s); err != nil {
+				t.Fatalf("%s: WriteSweep: %v", tc.desc, err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("%s: Flush: %v", tc.desc, err)
+		}
+		out := buf.String()
+		for _, want := range []string{"# goscope capture\n", "# interval: 0.001 s\n", "# param level: 0.5000\n", tc.header} {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: output %q does not contain %q", tc.desc, out, want)
+			}
+		}
+
+		md, sweeps, err := ReadCSV(&buf)
+		if err != nil {
+			t.Fatalf("%s: ReadCSV: %v", tc.desc, err)
+		}
+		if !reflect.DeepEqual(md, testMetadata) {
+			t.Errorf("%s: ReadCSV metadata: got %+v, want %+v", tc.desc, md, testMetadata)
+		}
+		if !reflect.DeepEqual(sweeps, testSweeps) {
+			t.Errorf("%s: ReadCSV sweeps: got %v, want %v", tc.desc, sweeps, testSweeps)
+		}
+	}
+}
+
+func TestCSVWriterErrors(t *testing.T) {
+	for _, tc := range []struct {
+		desc  string
+		sweep []scope.ChannelData
+	}{
+		{"fewer channels", testSweeps[0][:1]},
+		{"other channel", []scope.ChannelData{testSweeps[0][0], {ID: "cos", Samples: []scope.Voltage{1, 2, 3}}}},
+		{"different lengths", []scope.ChannelData{testSweeps[0][0], {ID: "square", Samples: []scope.Voltage{1}}}},
+	} {
+		w := NewCSVWriter(&bytes.Buffer{}, testMetadata)
+		if err := w.WriteSweep(testSweeps[0]); err != nil {
+			t.Fatalf("%s: WriteSweep: %v", tc.desc, err)
+		}
+		if err := w.WriteSweep(tc.sweep); err == nil {
+			t.Errorf("%s: WriteSweep(%v) did not return an error", tc.desc, tc.sweep)
+		}
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		in   string
+	}{
+		{"no header", "time (s),sin (V)\n0,1\n"},
+		{"other header", "# some file\ntime (s),sin (V)\n0,1\n"},
+		{"invalid interval", "# goscope capture\n# interval: 1ms\ntime (s),sin (V)\n0,1\n"},
+		{"no time column", "# goscope capture\nsin (V)\n1\n"},
+		{"no unit", "# goscope capture\ntime (s),sin\n0,1\n"},
+		{"invalid time", "# goscope capture\ntime (s),sin (V)\nx,1\n"},
+		{"invalid sample", "# goscope capture\ntime (s),sin (V)\n0,x\n"},
+		{"missing column", "# goscope capture\ntime (s),sin (V)\n0\n"},
+	} {
+		if _, _, err := ReadCSV(strings.NewReader(tc.in)); err == nil {
+			t.Errorf("%s: ReadCSV(%q) did not return an error", tc.desc, tc.in)
+		}
+	}
+}
+
+func TestPlay(t *testing.T) {
+	rec := testutil.NewBufferRecorder(3 * scope.Millisecond)
+	Play(rec, testMetadata.Interval, testSweeps)
+	got, err := rec.Wait()
+	if err != nil {
+		t.Fatalf("BufferRecorder: %v", err)
+	}
+	want := [][]scope.Voltage{testSweeps[0][0].Samples, testSweeps[1][0].Samples}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recorded sweeps: got %v, want %v", got, want)
+	}
+	if rec.Interval() != testMetadata.Interval {
+		t.Errorf("recorded interval: got %s, want %s", rec.Interval(), testMetadata.Interval)
+	}
+}
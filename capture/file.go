@@ -0,0 +1,561 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// The capture file stores the sweeps in a compact binary form.
+// All numbers are little endian. The file consists of:
+//
+//	header:  fileMagic, uint32 version, uint32 length, metadata of length bytes
+//	sweeps:  sweepMagic, uint32 samples per channel, samples of each channel
+//	index:   indexMagic, uint64 count, uint64 offset of each sweep
+//	trailer: uint64 offset of the index, endMagic
+//
+// The metadata holds the device description, the interval, timebase
+// and trigger position, the params and the channels with the encoding
+// of their samples. Strings are stored as a uvarint length followed
+// by the bytes.
+//
+// The sweeps are written as they come, the index and the trailer
+// are written by Close. A file that wasn't closed, e.g. a long recording
+// that was interrupted, can still be read: the sweeps are then found
+// by skipping from one to the next.
+
+const (
+	fileMagic   = "goscope\x00"
+	endMagic    = "gscpend\x00"
+	sweepMagic  = "swp\x00"
+	indexMagic  = "idx\x00"
+	fileVersion = 1
+)
+
+// Encoding is the encoding of the samples of a channel in a capture file.
+type Encoding uint8
+
+const (
+	// Float64 stores the samples exactly, in 8 bytes each.
+	Float64 Encoding = iota
+	// Float32 stores the samples in 4 bytes each, with the precision of a float32.
+	Float32
+	// Code8 stores the original 8-bit ADC codes of the samples, together
+	// with the table translating the codes to voltages.
+	Code8
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case Float64:
+		return "float64"
+	case Float32:
+		return "float32"
+	case Code8:
+		return "code8"
+	}
+	return fmt.Sprintf("Encoding(%d)", e)
+}
+
+func (e Encoding) size() int {
+	switch e {
+	case Float32:
+		return 4
+	case Code8:
+		return 1
+	}
+	return 8
+}
+
+// ChannelInfo describes a channel stored in a capture file.
+type ChannelInfo struct {
+	ID       scope.ChanID
+	Encoding Encoding
+	// Table translates the ADC codes to voltages, used with the Code8 encoding.
+	Table *[256]scope.Voltage
+}
+
+func (c ChannelInfo) check() error {
+	switch c.Encoding {
+	case Float64, Float32:
+		return nil
+	case Code8:
+		if c.Table == nil {
+			return fmt.Errorf("channel %s: %s encoding requires the translation table", c.ID, c.Encoding)
+		}
+		return nil
+	}
+	return fmt.Errorf("channel %s: unknown encoding %s", c.ID, c.Encoding)
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
+
+// Writer writes sweeps to a capture file.
+type Writer struct {
+	w       *bufio.Writer
+	cw      *countingWriter
+	chans   []ChannelInfo
+	codes   []map[scope.Voltage]byte
+	offsets []int64
+	buf     []byte
+}
+
+// NewWriter writes the header of a capture file with metadata md
+// and channels chans to w and returns a Writer of the sweeps.
+// The caller must call Close to write the index of the sweeps.
+func NewWriter(w io.Writer, md Metadata, chans []ChannelInfo) (*Writer, error) {
+	if len(chans) == 0 {
+		return nil, errors.New("no channels")
+	}
+	var hdr bytes.Buffer
+	putString(&hdr, md.Device)
+	putUint(&hdr, uint64(md.Interval))
+	putUint(&hdr, uint64(md.TimeBase))
+	putInt(&hdr, int64(md.TriggerPos))
+	putUint(&hdr, uint64(len(md.Params)))
+	for _, p := range md.Params {
+		putString(&hdr, p.Name)
+		putString(&hdr, p.Value)
+	}
+	putUint(&hdr, uint64(len(chans)))
+	codes := make([]map[scope.Voltage]byte, len(chans))
+	for i, c := range chans {
+		if err := c.check(); err != nil {
+			return nil, err
+		}
+		putString(&hdr, string(c.ID))
+		hdr.WriteByte(byte(c.Encoding))
+		if c.Encoding != Code8 {
+			continue
+		}
+		codes[i] = make(map[scope.Voltage]byte)
+		for code := 255; code >= 0; code-- {
+			v := c.Table[code]
+			// the lowest code wins if the table has repeated values.
+			codes[i][v] = byte(code)
+		}
+		for _, v := range c.Table {
+			binary.Write(&hdr, binary.LittleEndian, float64(v))
+		}
+	}
+
+	cw := &countingWriter{w: w}
+	ret := &Writer{
+		w:     bufio.NewWriter(cw),
+		cw:    cw,
+		chans: chans,
+		codes: codes,
+	}
+	ret.w.WriteString(fileMagic)
+	ret.putUint32(fileVersion)
+	ret.putUint32(uint32(hdr.Len()))
+	ret.w.Write(hdr.Bytes())
+	return ret, ret.err()
+}
+
+func putUint(b *bytes.Buffer, v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	b.Write(buf[:binary.PutUvarint(buf[:], v)])
+}
+
+func putInt(b *bytes.Buffer, v int64) {
+	var buf [binary.MaxVarintLen64]byte
+	b.Write(buf[:binary.PutVarint(buf[:], v)])
+}
+
+func putString(b *bytes.Buffer, s string) {
+	putUint(b, uint64(len(s)))
+	b.WriteString(s)
+}
+
+func (w *Writer) putUint32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	w.w.Write(b[:])
+}
+
+func (w *Writer) putUint64(v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	w.w.Write(b[:])
+}
+
+// offset returns the offset in the file of the next byte to write.
+func (w *Writer) offset() int64 {
+	return w.cw.n + int64(w.w.Buffered())
+}
+
+// err returns the first error writing to the underlying writer.
+// bufio.Writer keeps returning it from all the following writes.
+func (w *Writer) err() error {
+	_, err := w.w.Write(nil)
+	return err
+}
+
+// WriteSweep writes a sweep. The sweep must have the channels of the file,
+// in the same order, with the same number of samples each. The samples
+// of the Code8 channels must be the values of the translation table.
+func (w *Writer) WriteSweep(data []scope.ChannelData) error {
+	if len(data) != len(w.chans) {
+		return fmt.Errorf("sweep has %d channels, want %d", len(data), len(w.chans))
+	}
+	n := len(data[0].Samples)
+	for i, d := range data {
+		if d.ID != w.chans[i].ID {
+			return fmt.Errorf("sweep channel %d is %s, want %s", i, d.ID, w.chans[i].ID)
+		}
+		if len(d.Samples) != n {
+			return fmt.Errorf("channel %s has %d samples, want %d", d.ID, len(d.Samples), n)
+		}
+	}
+	// all the channels are encoded before writing, so that a sample
+	// missing in a translation table doesn't leave a partial sweep.
+	size := 0
+	for _, c := range w.chans {
+		size += n * c.Encoding.size()
+	}
+	if cap(w.buf) < size {
+		w.buf = make([]byte, size)
+	}
+	buf := w.buf[:size]
+	pos := 0
+	for i, d := range data {
+		for _, v := range d.Samples {
+			switch w.chans[i].Encoding {
+			case Float64:
+				binary.LittleEndian.PutUint64(buf[pos:], math.Float64bits(float64(v)))
+			case Float32:
+				binary.LittleEndian.PutUint32(buf[pos:], math.Float32bits(float32(v)))
+			case Code8:
+				c, ok := w.codes[i][v]
+				if !ok {
+					return fmt.Errorf("channel %s: sample %v is not in the translation table", d.ID, v)
+				}
+				buf[pos] = c
+			}
+			pos += w.chans[i].Encoding.size()
+		}
+	}
+	off := w.offset()
+	w.w.WriteString(sweepMagic)
+	w.putUint32(uint32(n))
+	w.w.Write(buf)
+	if err := w.err(); err != nil {
+		return err
+	}
+	w.offsets = append(w.offsets, off)
+	return nil
+}
+
+// Flush writes the buffered sweeps to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Close writes the index of the sweeps and flushes the file.
+// It does not close the underlying writer.
+func (w *Writer) Close() error {
+	off := w.offset()
+	w.w.WriteString(indexMagic)
+	w.putUint64(uint64(len(w.offsets)))
+	for _, o := range w.offsets {
+		w.putUint64(uint64(o))
+	}
+	w.putUint64(uint64(off))
+	w.w.WriteString(endMagic)
+	return w.w.Flush()
+}
+
+// Reader reads the sweeps of a capture file in any order.
+type Reader struct {
+	r       io.ReaderAt
+	md      Metadata
+	chans   []ChannelInfo
+	offsets []int64
+	size    int64
+	// sampleSize is the size of a single sample of all the channels.
+	sampleSize int64
+}
+
+// NewReader reads the metadata and the index of the capture file
+// of the given size from r.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	var pre [16]byte
+	if _, err := r.ReadAt(pre[:], 0); err != nil {
+		return nil, fmt.Errorf("reading the header: %v", err)
+	}
+	if string(pre[:8]) != fileMagic {
+		return nil, errors.New("not a goscope capture file")
+	}
+	if v := binary.LittleEndian.Uint32(pre[8:]); v != fileVersion {
+		return nil, fmt.Errorf("unsupported capture file version %d, want %d", v, fileVersion)
+	}
+	hdrLen := int64(binary.LittleEndian.Uint32(pre[12:]))
+	if 16+hdrLen > size {
+		return nil, fmt.Errorf("header of %d bytes exceeds the file size %d", hdrLen, size)
+	}
+	hdr := make([]byte, hdrLen)
+	if _, err := r.ReadAt(hdr, 16); err != nil {
+		return nil, fmt.Errorf("reading the header: %v", err)
+	}
+	ret := &Reader{r: r, size: size}
+	if err := ret.parseHeader(bytes.NewReader(hdr)); err != nil {
+		return nil, fmt.Errorf("invalid header: %v", err)
+	}
+	if ret.readIndex(size) {
+		return ret, nil
+	}
+	if err := ret.scan(16+hdrLen, size); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (r *Reader) parseHeader(b *bytes.Reader) error {
+	readString := func() (string, error) {
+		n, err := binary.ReadUvarint(b)
+		if err != nil {
+			return "", err
+		}
+		if n > uint64(b.Len()) {
+			return "", io.ErrUnexpectedEOF
+		}
+		s := make([]byte, n)
+		_, err = io.ReadFull(b, s)
+		return string(s), err
+	}
+	var err error
+	if r.md.Device, err = readString(); err != nil {
+		return err
+	}
+	interval, err := binary.ReadUvarint(b)
+	if err != nil {
+		return err
+	}
+	timeBase, err := binary.ReadUvarint(b)
+	if err != nil {
+		return err
+	}
+	trigPos, err := binary.ReadVarint(b)
+	if err != nil {
+		return err
+	}
+	numParams, err := binary.ReadUvarint(b)
+	if err != nil {
+		return err
+	}
+	r.md.Interval = scope.Duration(interval)
+	r.md.TimeBase = scope.Duration(timeBase)
+	r.md.TriggerPos = int(trigPos)
+	for i := uint64(0); i < numParams; i++ {
+		var p Param
+		if p.Name, err = readString(); err != nil {
+			return err
+		}
+		if p.Value, err = readString(); err != nil {
+			return err
+		}
+		r.md.Params = append(r.md.Params, p)
+	}
+	numChans, err := binary.ReadUvarint(b)
+	if err != nil {
+		return err
+	}
+	if numChans == 0 {
+		return errors.New("no channels")
+	}
+	for i := uint64(0); i < numChans; i++ {
+		var c ChannelInfo
+		id, err := readString()
+		if err != nil {
+			return err
+		}
+		c.ID = scope.ChanID(id)
+		enc, err := b.ReadByte()
+		if err != nil {
+			return err
+		}
+		c.Encoding = Encoding(enc)
+		if c.Encoding == Code8 {
+			var tbl [256]float64
+			if err := binary.Read(b, binary.LittleEndian, &tbl); err != nil {
+				return err
+			}
+			c.Table = new([256]scope.Voltage)
+			for j, v := range tbl {
+				c.Table[j] = scope.Voltage(v)
+			}
+		}
+		if err := c.check(); err != nil {
+			return err
+		}
+		r.chans = append(r.chans, c)
+		r.sampleSize += int64(c.Encoding.size())
+	}
+	return nil
+}
+
+// readIndex reads the index written by Writer.Close and reports
+// whether it was found.
+func (r *Reader) readIndex(size int64) bool {
+	var tr [16]byte
+	if size < 16 {
+		return false
+	}
+	if _, err := r.r.ReadAt(tr[:], size-16); err != nil || string(tr[8:]) != endMagic {
+		return false
+	}
+	off := int64(binary.LittleEndian.Uint64(tr[:]))
+	var hdr [12]byte
+	if off < 0 || off+12 > size-16 {
+		return false
+	}
+	if _, err := r.r.ReadAt(hdr[:], off); err != nil || string(hdr[:4]) != indexMagic {
+		return false
+	}
+	count := binary.LittleEndian.Uint64(hdr[4:])
+	if count > uint64(size-16-off-12)/8 {
+		return false
+	}
+	idx := make([]byte, count*8)
+	if _, err := r.r.ReadAt(idx, off+12); err != nil {
+		return false
+	}
+	offsets := make([]int64, count)
+	for i := range offsets {
+		offsets[i] = int64(binary.LittleEndian.Uint64(idx[i*8:]))
+		if offsets[i] < 0 || offsets[i]+8 > size {
+			return false
+		}
+	}
+	r.offsets = offsets
+	return true
+}
+
+// scan finds the sweeps of a file without the index, starting at off.
+// A truncated sweep at the end of the file is ignored.
+func (r *Reader) scan(off, size int64) error {
+	var hdr [8]byte
+	for off+8 <= size {
+		if _, err := r.r.ReadAt(hdr[:], off); err != nil {
+			return fmt.Errorf("reading sweep at offset %d: %v", off, err)
+		}
+		if string(hdr[:4]) != sweepMagic {
+			if string(hdr[:4]) == indexMagic {
+				return nil
+			}
+			return fmt.Errorf("invalid sweep at offset %d", off)
+		}
+		next := off + 8 + int64(binary.LittleEndian.Uint32(hdr[4:]))*r.sampleSize
+		if next > size {
+			return nil
+		}
+		r.offsets = append(r.offsets, off)
+		off = next
+	}
+	return nil
+}
+
+// Metadata returns the metadata of the capture.
+func (r *Reader) Metadata() Metadata { return r.md }
+
+// Channels returns the channels stored in the file.
+func (r *Reader) Channels() []ChannelInfo { return r.chans }
+
+// NumSweeps returns the number of sweeps in the file.
+func (r *Reader) NumSweeps() int { return len(r.offsets) }
+
+// readRaw returns the samples of sweep i, as stored in the file.
+func (r *Reader) readRaw(i int) (int, []byte, error) {
+	if i < 0 || i >= len(r.offsets) {
+		return 0, nil, fmt.Errorf("sweep %d out of range, the file has %d sweeps", i, len(r.offsets))
+	}
+	var hdr [8]byte
+	if _, err := r.r.ReadAt(hdr[:], r.offsets[i]); err != nil {
+		return 0, nil, fmt.Errorf("reading sweep %d: %v", i, err)
+	}
+	if string(hdr[:4]) != sweepMagic {
+		return 0, nil, fmt.Errorf("invalid sweep %d at offset %d", i, r.offsets[i])
+	}
+	n := int(binary.LittleEndian.Uint32(hdr[4:]))
+	if r.offsets[i]+8+int64(n)*r.sampleSize > r.size {
+		return 0, nil, fmt.Errorf("sweep %d of %d samples exceeds the file size %d", i, n, r.size)
+	}
+	buf := make([]byte, int64(n)*r.sampleSize)
+	if _, err := r.r.ReadAt(buf, r.offsets[i]+8); err != nil {
+		return 0, nil, fmt.Errorf("reading sweep %d: %v", i, err)
+	}
+	return n, buf, nil
+}
+
+// ReadSweep returns the samples of sweep i.
+func (r *Reader) ReadSweep(i int) ([]scope.ChannelData, error) {
+	n, buf, err := r.readRaw(i)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]scope.ChannelData, len(r.chans))
+	for c, ch := range r.chans {
+		s := make([]scope.Voltage, n)
+		for j := range s {
+			switch ch.Encoding {
+			case Float64:
+				s[j] = scope.Voltage(math.Float64frombits(binary.LittleEndian.Uint64(buf[j*8:])))
+			case Float32:
+				s[j] = scope.Voltage(math.Float32frombits(binary.LittleEndian.Uint32(buf[j*4:])))
+			case Code8:
+				s[j] = ch.Table[buf[j]]
+			}
+		}
+		ret[c] = scope.ChannelData{ID: ch.ID, Samples: s}
+		buf = buf[n*ch.Encoding.size():]
+	}
+	return ret, nil
+}
+
+// ReadCodes returns the ADC codes of the channels of sweep i,
+// nil for the channels not stored with the Code8 encoding.
+func (r *Reader) ReadCodes(i int) ([][]byte, error) {
+	n, buf, err := r.readRaw(i)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([][]byte, len(r.chans))
+	for c, ch := range r.chans {
+		if ch.Encoding == Code8 {
+			ret[c] = buf[:n]
+		}
+		buf = buf[n*ch.Encoding.size():]
+	}
+	return ret, nil
+}
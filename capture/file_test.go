@@ -0,0 +1,285 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package capture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+func testTable() *[256]scope.Voltage {
+	var t [256]scope.Voltage
+	for i := range t {
+		t[i] = scope.Voltage(i-128) * 0.04
+	}
+	return &t
+}
+
+func testChannels() []ChannelInfo {
+	return []ChannelInfo{
+		{ID: "exact", Encoding: Float64},
+		{ID: "compact", Encoding: Float32},
+		{ID: "adc", Encoding: Code8, Table: testTable()},
+	}
+}
+
+func testFileSweeps() [][]scope.ChannelData {
+	tbl := testTable()
+	var ret [][]scope.ChannelData
+	for i := 0; i < 5; i++ {
+		n := 10 + i
+		s := []scope.ChannelData{{ID: "exact"}, {ID: "compact"}, {ID: "adc"}}
+		for j := 0; j < n; j++ {
+			s[0].Samples = append(s[0].Samples, scope.Voltage(i)+scope.Voltage(j)/3)
+			s[1].Samples = append(s[1].Samples, scope.Voltage(float32(j)*0.25))
+			s[2].Samples = append(s[2].Samples, tbl[(i*50+j*7)%256])
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+var testFileMetadata = Metadata{
+	Device:     "dummy device",
+	Interval:   scope.Microsecond,
+	TimeBase:   10 * scope.Microsecond,
+	TriggerPos: -1,
+	Params: []Param{
+		{Name: "mode", Value: "none"},
+		{Name: "level", Value: "0.5000"},
+	},
+}
+
+func writeFile(t *testing.T, sweeps [][]scope.ChannelData, closeFile bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, testFileMetadata, testChannels())
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for _, s := range sweeps {
+		if err := w.WriteSweep(s); err != nil {
+			t.Fatalf("WriteSweep: %v", err)
+		}
+	}
+	if closeFile {
+		err = w.Close()
+	} else {
+		err = w.Flush()
+	}
+	if err != nil {
+		t.Fatalf("Close/Flush: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	sweeps := testFileSweeps()
+	for _, tc := range []struct {
+		desc      string
+		closeFile bool
+		truncate  int
+	}{
+		{desc: "closed", closeFile: true},
+		{desc: "not closed", closeFile: false},
+		{desc: "truncated sweep", closeFile: false, truncate: 3},
+	} {
+		b := writeFile(t, sweeps, tc.closeFile)
+		want := sweeps
+		if tc.truncate > 0 {
+			b = b[:len(b)-tc.truncate]
+			want = sweeps[:len(sweeps)-1]
+		}
+		r, err := NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			t.Fatalf("%s: NewReader: %v", tc.desc, err)
+		}
+		if got := r.Metadata(); !reflect.DeepEqual(got, testFileMetadata) {
+			t.Errorf("%s: Metadata: got %+v, want %+v", tc.desc, got, testFileMetadata)
+		}
+		if got := r.Channels(); !reflect.DeepEqual(got, testChannels()) {
+			t.Errorf("%s: Channels: got %+v, want %+v", tc.desc, got, testChannels())
+		}
+		if got := r.NumSweeps(); got != len(want) {
+			t.Fatalf("%s: NumSweeps: got %d, want %d", tc.desc, got, len(want))
+		}
+		// read in reverse order, to check the random access.
+		for i := len(want) - 1; i >= 0; i-- {
+			got, err := r.ReadSweep(i)
+			if err != nil {
+				t.Fatalf("%s: ReadSweep(%d): %v", tc.desc, i, err)
+			}
+			if !reflect.DeepEqual(got, want[i]) {
+				t.Errorf("%s: ReadSweep(%d): got %v, want %v", tc.desc, i, got, want[i])
+			}
+		}
+		if _, err := r.ReadSweep(len(want)); err == nil {
+			t.Errorf("%s: ReadSweep(%d) did not return an error", tc.desc, len(want))
+		}
+	}
+}
+
+func TestFileCodes(t *testing.T) {
+	b := writeFile(t, testFileSweeps(), true)
+	r, err := NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	codes, err := r.ReadCodes(1)
+	if err != nil {
+		t.Fatalf("ReadCodes: %v", err)
+	}
+	if codes[0] != nil || codes[1] != nil {
+		t.Errorf("ReadCodes: got codes %v, %v for float channels, want nil", codes[0], codes[1])
+	}
+	var want []byte
+	for j := 0; j < 11; j++ {
+		want = append(want, byte((50+j*7)%256))
+	}
+	if !bytes.Equal(codes[2], want) {
+		t.Errorf("ReadCodes: got %v, want %v", codes[2], want)
+	}
+}
+
+func TestFileWriterErrors(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, testFileMetadata, nil); err == nil {
+		t.Error("NewWriter without channels did not return an error")
+	}
+	if _, err := NewWriter(&bytes.Buffer{}, testFileMetadata, []ChannelInfo{{ID: "adc", Encoding: Code8}}); err == nil {
+		t.Error("NewWriter with a Code8 channel without a table did not return an error")
+	}
+	if _, err := NewWriter(&bytes.Buffer{}, testFileMetadata, []ChannelInfo{{ID: "x", Encoding: 7}}); err == nil {
+		t.Error("NewWriter with an unknown encoding did not return an error")
+	}
+	good := testFileSweeps()[0]
+	for _, tc := range []struct {
+		desc  string
+		sweep []scope.ChannelData
+	}{
+		{"fewer channels", good[:2]},
+		{"other channel", []scope.ChannelData{good[0], {ID: "other", Samples: good[1].Samples}, good[2]}},
+		{"different lengths", []scope.ChannelData{good[0], {ID: "compact", Samples: good[1].Samples[:1]}, good[2]}},
+		{"not in table", []scope.ChannelData{good[0], good[1], {ID: "adc", Samples: good[0].Samples}}},
+	} {
+		w, err := NewWriter(&bytes.Buffer{}, testFileMetadata, testChannels())
+		if err != nil {
+			t.Fatalf("NewWriter: %v", err)
+		}
+		if err := w.WriteSweep(tc.sweep); err == nil {
+			t.Errorf("%s: WriteSweep did not return an error", tc.desc)
+		}
+	}
+}
+
+func TestFileWriterRejectedSweep(t *testing.T) {
+	good := testFileSweeps()[0]
+	bad := []scope.ChannelData{good[0], good[1], {ID: "adc", Samples: append([]scope.Voltage{good[2].Samples[0]}, good[0].Samples[1:]...)}}
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, testFileMetadata, testChannels())
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.WriteSweep(bad); err == nil {
+		t.Fatalf("WriteSweep with a sample not in the table did not return an error")
+	}
+	if err := w.WriteSweep(good); err != nil {
+		t.Fatalf("WriteSweep: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	// the file isn't closed, the reader finds the sweeps by scanning them.
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	if got := r.NumSweeps(); got != 1 {
+		t.Fatalf("NumSweeps: got %d, want 1", got)
+	}
+	got, err := r.ReadSweep(0)
+	if err != nil {
+		t.Fatalf("ReadSweep(0): %v", err)
+	}
+	if !reflect.DeepEqual(got, good) {
+		t.Errorf("ReadSweep(0): got %v, want %v", got, good)
+	}
+}
+
+func TestFileReaderErrors(t *testing.T) {
+	b := writeFile(t, testFileSweeps(), true)
+	corrupt := append([]byte(nil), b...)
+	corrupt[16] = 0xff
+	for _, tc := range []struct {
+		desc string
+		in   []byte
+	}{
+		{"empty", nil},
+		{"not a capture", []byte("time (s),sin (V)\n0,1\n")},
+		{"truncated header", b[:20]},
+		{"corrupt header", corrupt},
+	} {
+		if _, err := NewReader(bytes.NewReader(tc.in), int64(len(tc.in))); err == nil {
+			t.Errorf("%s: NewReader did not return an error", tc.desc)
+		}
+	}
+}
+
+func TestFileReaderBounds(t *testing.T) {
+	sweeps := testFileSweeps()
+	b := writeFile(t, sweeps, true)
+
+	// the last entry of the index points past the end of the file,
+	// the reader ignores the index and finds the sweeps by scanning them.
+	badIndex := append([]byte(nil), b...)
+	binary.LittleEndian.PutUint64(badIndex[len(b)-24:], uint64(len(b)))
+	r, err := NewReader(bytes.NewReader(badIndex), int64(len(badIndex)))
+	if err != nil {
+		t.Fatalf("NewReader with a bad index: %v", err)
+	}
+	if got, want := r.NumSweeps(), len(sweeps); got != want {
+		t.Fatalf("NumSweeps with a bad index: got %d, want %d", got, want)
+	}
+	for i, want := range sweeps {
+		got, err := r.ReadSweep(i)
+		if err != nil {
+			t.Fatalf("ReadSweep(%d) with a bad index: %v", i, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReadSweep(%d) with a bad index: got %v, want %v", i, got, want)
+		}
+	}
+
+	// the first sweep claims more samples than the file holds.
+	badCount := append([]byte(nil), b...)
+	first := 16 + int(binary.LittleEndian.Uint32(b[12:]))
+	binary.LittleEndian.PutUint32(badCount[first+4:], 0xffffffff)
+	r, err = NewReader(bytes.NewReader(badCount), int64(len(badCount)))
+	if err != nil {
+		t.Fatalf("NewReader with a bad sample count: %v", err)
+	}
+	if _, err := r.ReadSweep(0); err == nil {
+		t.Error("ReadSweep(0) with a bad sample count did not return an error")
+	}
+	if _, err := r.ReadCodes(0); err == nil {
+		t.Error("ReadCodes(0) with a bad sample count did not return an error")
+	}
+	if _, err := r.ReadSweep(1); err != nil {
+		t.Errorf("ReadSweep(1) with a bad sample count of sweep 0: %v", err)
+	}
+}
@@ -20,8 +20,12 @@ import "github.com/zagrodzki/goscope/scope"
 // devices. At the same time it exposes the same sort of API that was used
 // previously, with a channel for reading sample data.
 type Recorder struct {
-	TB   scope.Duration
-	Data chan scope.Data
+	TB scope.Duration
+	// Sweeps makes the recorder assemble the chunks of samples into
+	// sweeps of TB, see AssembleSweeps, instead of passing every chunk
+	// on the Data channel.
+	Sweeps bool
+	Data   chan scope.Data
 }
 
 // TimeBase returns the configured timebase.
@@ -32,6 +36,20 @@ func (g *Recorder) TimeBase() scope.Duration {
 // Reset initializes the recorder. The Data channel is initialized only after Reset.
 func (g *Recorder) Reset(i scope.Duration, dat <-chan []scope.ChannelData) {
 	g.Data = make(chan scope.Data, 1)
+	if g.Sweeps {
+		go func() {
+			AssembleSweeps(dat, int(g.TB/i), func(d []scope.ChannelData) {
+				// the sweep is read after AssembleSweeps reuses the buffer.
+				sweep := make([]scope.ChannelData, len(d))
+				for j, c := range d {
+					sweep[j] = scope.ChannelData{ID: c.ID, Samples: append([]scope.Voltage(nil), c.Samples...)}
+				}
+				g.Data <- scope.Data{Channels: sweep, Num: len(sweep[0].Samples), Interval: i}
+			})
+			close(g.Data)
+		}()
+		return
+	}
 	go func() {
 		for d := range dat {
 			if len(d) == 0 || len(d[0].Samples) == 0 {
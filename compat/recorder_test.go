@@ -96,3 +96,28 @@ func TestRecorderError(t *testing.T) {
 		t.Errorf("Got data sequence %+v, want %+v", got, want)
 	}
 }
+
+func TestRecorderSweeps(t *testing.T) {
+	r := &Recorder{TB: 4 * scope.Microsecond, Sweeps: true}
+	ch := make(chan []scope.ChannelData)
+	r.Reset(scope.Microsecond, ch)
+	go func() {
+		for _, s := range [][]scope.Voltage{{1, 2, 3}, {4, 5, 6}, {7, 8}, {9, 10}, {11}} {
+			ch <- []scope.ChannelData{{ID: "one", Samples: s}}
+		}
+		close(ch)
+	}()
+	var got [][]scope.Voltage
+	for d := range r.Data {
+		if d.Num != 4 || d.Interval != scope.Microsecond {
+			t.Errorf("sweep %+v: want 4 samples every 1us", d)
+		}
+		got = append(got, d.Channels[0].Samples)
+	}
+	// the samples past the end of a sweep are dropped,
+	// the incomplete last sweep isn't passed on.
+	want := [][]scope.Voltage{{1, 2, 3, 4}, {7, 8, 9, 10}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sweeps: got %v, want %v", got, want)
+	}
+}
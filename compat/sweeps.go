@@ -0,0 +1,55 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package compat
+
+import "github.com/zagrodzki/goscope/scope"
+
+// AssembleSweeps reads the chunks of samples from ch until it's closed,
+// assembles them into sweeps of n samples and calls sweep with every
+// complete sweep. The samples of a chunk beyond the end of a sweep are
+// dropped, so that every sweep starts with a new chunk, like the sweeps
+// passed on by a trigger. The data passed to sweep is valid only
+// until sweep returns.
+func AssembleSweeps(ch <-chan []scope.ChannelData, n int, sweep func([]scope.ChannelData)) {
+	if n < 1 {
+		n = 1
+	}
+	var buf []scope.ChannelData
+	for data := range ch {
+		if len(data) == 0 {
+			continue
+		}
+		if buf == nil {
+			buf = make([]scope.ChannelData, len(data))
+			for i, d := range data {
+				buf[i].ID = d.ID
+				buf[i].Samples = make([]scope.Voltage, 0, 2*n)
+			}
+		}
+		for i, d := range data {
+			buf[i].Samples = append(buf[i].Samples, d.Samples...)
+		}
+		if len(buf[0].Samples) < n {
+			continue
+		}
+		for i := range buf {
+			buf[i].Samples = buf[i].Samples[:n]
+		}
+		sweep(buf)
+		for i := range buf {
+			buf[i].Samples = buf[i].Samples[:0]
+		}
+	}
+}
@@ -0,0 +1,519 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package control keeps the settings of an oscilloscope changed by
+// a user interface: the time/div, the vertical settings of the channels,
+// the trigger and the other device params. It runs the device, restarting
+// it when the params change, and assembles the samples into sweeps
+// for the display.
+package control
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/zagrodzki/goscope/compat"
+	"github.com/zagrodzki/goscope/gui"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Run describes a run of the device, from one Reset to the next.
+type Run struct {
+	// Interval is the sampling interval.
+	Interval scope.Duration
+	// TimeBase is the length of a sweep and SweepLen the number
+	// of samples in it.
+	TimeBase scope.Duration
+	SweepLen int
+	// Roll is true if the sweeps are displayed in the roll mode.
+	Roll bool
+}
+
+// Frontend displays the sweeps assembled by a Controller.
+// The methods are called by the device and by the goroutine reading
+// the samples, they must not call the Controller.
+type Frontend interface {
+	// Reset is called when the device starts a run, before any sweep
+	// of the run.
+	Reset(r Run)
+	// Sweep is called with every complete sweep. In the roll mode
+	// it's called with every chunk of samples, with all the samples
+	// of the last sweep length, and full is false until a complete
+	// sweep arrived. The data is valid only until Sweep returns.
+	Sweep(data []scope.ChannelData, full bool)
+	// Error reports an acquisition error.
+	Error(err error)
+}
+
+// Config are the initial settings of a Controller.
+type Config struct {
+	TimePerDiv  scope.Duration
+	VoltsPerDiv float64
+	// Trigger are the trigger params, Other any additional device params.
+	Trigger, Other []scope.Param
+	// RollAbove is the time/div from which the sweeps are displayed
+	// in the roll mode, 0 disables the roll mode.
+	RollAbove scope.Duration
+}
+
+// channel are the vertical settings of a single channel.
+type channel struct {
+	voltsPerDiv *gui.ScaleParam
+	offset      *gui.PositionParam
+}
+
+// Controller runs a device and keeps its settings.
+// Controller implements scope.DataRecorder, it attaches itself
+// to the device in New.
+type Controller struct {
+	dev scope.Device
+	fe  Frontend
+
+	// mu guards the settings and the state of the device.
+	mu         sync.Mutex
+	timePerDiv *gui.ScaleParam
+	// tb and roll change only while the device is stopped. Reset
+	// is called from the Start of the device, with mu held.
+	tb      scope.Duration
+	chans   []scope.ChanID
+	chanCtl map[scope.ChanID]*channel
+	// selChan is the index in chans of the channel
+	// changed by the vertical controls.
+	selChan int
+	// trigger are the params of the trigger, by name.
+	trigger map[string]scope.Param
+	// params are all the device params, selParam is the index of the one
+	// changed by the generic param controls.
+	params    []scope.Param
+	selParam  int
+	running   bool
+	rollAbove scope.Duration
+	roll      bool
+	// trigMode is the trigger mode restored when leaving the roll mode,
+	// the triggering is disabled in the roll mode.
+	trigMode string
+	// readerDone is closed when the goroutine reading the samples
+	// since the last Reset exits.
+	readerDone chan struct{}
+}
+
+// New returns a Controller of the device dev with the initial settings cfg,
+// displaying the sweeps in fe. The device is stopped until Start.
+func New(dev scope.Device, fe Frontend, cfg Config) *Controller {
+	c := &Controller{
+		dev:        dev,
+		fe:         fe,
+		timePerDiv: gui.NewScaleParam("time/div", "s", 1e-6, 10, float64(cfg.TimePerDiv)/float64(scope.Second)),
+		chans:      dev.Channels(),
+		chanCtl:    make(map[scope.ChanID]*channel),
+		trigger:    make(map[string]scope.Param),
+		rollAbove:  cfg.RollAbove,
+	}
+	for _, ch := range c.chans {
+		c.chanCtl[ch] = &channel{
+			voltsPerDiv: gui.NewScaleParam("volts/div", "V", 1e-3, 10, cfg.VoltsPerDiv),
+			offset:      gui.NewPositionParam("offset", 0.5),
+		}
+	}
+	for _, p := range cfg.Trigger {
+		c.trigger[p.Name()] = p
+	}
+	c.params = append(append(c.params, cfg.Trigger...), cfg.Other...)
+	c.apply()
+	dev.Attach(c)
+	return c
+}
+
+// apply sets the sweep length from the time/div and enables the roll mode
+// if the time/div is at least rollAbove. The trigger mode is set to none
+// in the roll mode and restored when leaving it.
+// Must be called with c.mu held, while the device is stopped.
+func (c *Controller) apply() {
+	c.tb = scope.Duration(c.timePerDiv.Float() * gui.DivCols * float64(scope.Second))
+	roll := c.rollAbove > 0 && c.TimePerDiv() >= c.rollAbove
+	if roll == c.roll {
+		return
+	}
+	c.roll = roll
+	if p, ok := c.trigger["mode"]; ok {
+		if roll {
+			c.trigMode = p.Value()
+			if err := p.Set("none"); err != nil {
+				log.Printf("trigger mode: %v", err)
+			}
+		} else if err := p.Set(c.trigMode); err != nil {
+			log.Printf("trigger mode: %v", err)
+		}
+	}
+}
+
+// TimeBase returns the length of the sweeps.
+func (c *Controller) TimeBase() scope.Duration {
+	return c.tb
+}
+
+// TimePerDiv returns the time/div.
+func (c *Controller) TimePerDiv() scope.Duration {
+	return scope.Duration(c.timePerDiv.Float() * float64(scope.Second))
+}
+
+// Reset starts reading the samples from ch, sampled every interval.
+func (c *Controller) Reset(interval scope.Duration, ch <-chan []scope.ChannelData) {
+	// the device is restarted when the settings change, wait for
+	// the reader of the previous run to finish.
+	if c.readerDone != nil {
+		<-c.readerDone
+	}
+	r := Run{Interval: interval, TimeBase: c.tb, SweepLen: 1, Roll: c.roll}
+	if interval > 0 && c.tb > interval {
+		r.SweepLen = int(c.tb / interval)
+	}
+	c.fe.Reset(r)
+	c.readerDone = make(chan struct{})
+	if r.Roll {
+		go c.keepRolling(ch, r.SweepLen, c.readerDone)
+	} else {
+		go c.keepReading(ch, r.SweepLen, c.readerDone)
+	}
+}
+
+// Error passes the acquisition error to the frontend.
+func (c *Controller) Error(err error) {
+	c.fe.Error(err)
+}
+
+// keepReading assembles the chunks of samples into sweeps of n samples.
+func (c *Controller) keepReading(ch <-chan []scope.ChannelData, n int, done chan<- struct{}) {
+	defer close(done)
+	compat.AssembleSweeps(ch, n, func(data []scope.ChannelData) {
+		c.fe.Sweep(data, true)
+	})
+}
+
+// keepRolling passes every chunk of samples to the frontend as soon as
+// it arrives, together with the samples received before it, up to n.
+func (c *Controller) keepRolling(ch <-chan []scope.ChannelData, n int, done chan<- struct{}) {
+	defer close(done)
+	roll := gui.NewRoll(n)
+	for data := range ch {
+		if len(data) == 0 {
+			continue
+		}
+		roll.Add(data)
+		c.fe.Sweep(roll.Sweep(), roll.Full())
+	}
+}
+
+// Start starts the device.
+func (c *Controller) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.running {
+		c.running = true
+		c.dev.Start()
+	}
+}
+
+// Stop stops the device, if it's running.
+func (c *Controller) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.running {
+		c.running = false
+		c.dev.Stop()
+	}
+}
+
+// RunStop starts the device if it's stopped and stops it if it's running.
+// It returns true if the device is running afterwards.
+func (c *Controller) RunStop() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running = !c.running
+	if c.running {
+		c.dev.Start()
+	} else {
+		c.dev.Stop()
+	}
+	return c.running
+}
+
+// Running returns true if the device is running.
+func (c *Controller) Running() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.running
+}
+
+// Roll returns true if the sweeps are displayed in the roll mode.
+func (c *Controller) Roll() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.roll
+}
+
+// restart restarts the device, if it's running, so that the changes
+// of the params that take effect on the next Reset are applied.
+// Must be called with c.mu held.
+func (c *Controller) restart() {
+	if c.running {
+		c.dev.Stop()
+		c.apply()
+		c.dev.Start()
+		return
+	}
+	c.apply()
+}
+
+// Param returns the time/div or the device param called name,
+// nil if there is none.
+func (c *Controller) Param(name string) scope.Param {
+	if name == c.timePerDiv.Name() {
+		return c.timePerDiv
+	}
+	for _, p := range c.params {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Params returns the time/div followed by all the device params.
+func (c *Controller) Params() []scope.Param {
+	return append([]scope.Param{c.timePerDiv}, c.params...)
+}
+
+// Value returns the value of the time/div or the device param called name,
+// "" if there is none.
+func (c *Controller) Value(name string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p := c.Param(name); p != nil {
+		return p.Value()
+	}
+	return ""
+}
+
+// locked returns an error if the param p can't be changed, because
+// it's the trigger mode and the triggering is disabled in the roll mode.
+// Must be called with c.mu held.
+func (c *Controller) locked(p scope.Param) error {
+	if c.roll && p == c.trigger["mode"] {
+		return fmt.Errorf("triggering is disabled in the roll mode")
+	}
+	return nil
+}
+
+// Set sets the param p, the time/div or one of the device params,
+// to v and applies the change.
+func (c *Controller) Set(p scope.Param, v string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.locked(p); err != nil {
+		return err
+	}
+	if err := p.Set(v); err != nil {
+		return err
+	}
+	c.restart()
+	return nil
+}
+
+// Step changes the param p, the time/div or one of the device params,
+// to the next (up is true) or the previous setting, applies the change
+// and returns the new value.
+func (c *Controller) Step(p scope.Param, up bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.step(p, up, false)
+}
+
+// step changes the device param p, cycling through the values
+// of a SelectParam if cycle is true.
+// Must be called with c.mu held.
+func (c *Controller) step(p scope.Param, up, cycle bool) (string, error) {
+	if err := c.locked(p); err != nil {
+		return p.Value(), err
+	}
+	var v string
+	var err error
+	if sp, ok := p.(scope.SelectParam); ok && cycle {
+		v, err = gui.CycleParam(sp, up)
+	} else {
+		v, err = gui.StepParam(p, up)
+	}
+	if err != nil {
+		return v, err
+	}
+	c.restart()
+	return v, nil
+}
+
+// StepTimeBase changes the time/div to the next larger (up is true)
+// or smaller setting and returns the new value.
+func (c *Controller) StepTimeBase(up bool) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// the time/div is a RangeParam, stepping it doesn't fail.
+	v, _ := c.step(c.timePerDiv, up, false)
+	return v
+}
+
+// StepTrigger changes the trigger param called name, stepping through
+// the range of a RangeParam and cycling through the values
+// of a SelectParam, wrapping around in both directions.
+// It returns the param and its new value, the param is nil if
+// the trigger has no param called name.
+func (c *Controller) StepTrigger(name string, up bool) (scope.Param, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, ok := c.trigger[name]
+	if !ok {
+		return nil, "", nil
+	}
+	v, err := c.step(p, up, true)
+	return p, v, err
+}
+
+// SelectParam selects the next device param changed by StepParam and
+// returns it, nil if there are no device params.
+func (c *Controller) SelectParam() scope.Param {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.params) == 0 {
+		return nil
+	}
+	c.selParam = (c.selParam + 1) % len(c.params)
+	return c.params[c.selParam]
+}
+
+// SelectedParam returns the device param changed by StepParam,
+// nil if there are no device params.
+func (c *Controller) SelectedParam() scope.Param {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.params) == 0 {
+		return nil
+	}
+	return c.params[c.selParam]
+}
+
+// StepParam changes the selected device param and returns it
+// and its new value, nil if there are no device params.
+func (c *Controller) StepParam(up bool) (scope.Param, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.params) == 0 {
+		return nil, "", nil
+	}
+	p := c.params[c.selParam]
+	v, err := c.step(p, up, false)
+	return p, v, err
+}
+
+// Channels returns the channels of the device.
+func (c *Controller) Channels() []scope.ChanID {
+	return c.chans
+}
+
+// SelectChannel selects the i-th channel, changed by the vertical controls.
+// It returns false if there is no such channel.
+func (c *Controller) SelectChannel(i int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if i < 0 || i >= len(c.chans) {
+		return false
+	}
+	c.selChan = i
+	return true
+}
+
+// SelectedChannel returns the channel changed by the vertical controls.
+func (c *Controller) SelectedChannel() scope.ChanID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.chans[c.selChan]
+}
+
+// StepVoltsPerDiv changes the volts/div of the selected channel and
+// returns the channel and the new value.
+func (c *Controller) StepVoltsPerDiv(up bool) (scope.ChanID, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := c.chans[c.selChan]
+	v, _ := gui.StepParam(c.chanCtl[ch].voltsPerDiv, up)
+	return ch, v
+}
+
+// StepOffset moves the selected channel up or down the screen and returns
+// the channel and the new position.
+func (c *Controller) StepOffset(up bool) (scope.ChanID, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := c.chans[c.selChan]
+	v, _ := gui.StepParam(c.chanCtl[ch].offset, up)
+	return ch, v
+}
+
+// TraceParams returns the vertical settings of the channel ch.
+func (c *Controller) TraceParams(ch scope.ChanID) scope.TraceParams {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cc, ok := c.chanCtl[ch]
+	if !ok {
+		return scope.TraceParams{}
+	}
+	return scope.TraceParams{Zero: cc.offset.Position(), PerDiv: cc.voltsPerDiv.Float()}
+}
+
+// VoltsPerDiv returns the volts/div of the channel ch.
+func (c *Controller) VoltsPerDiv(ch scope.ChanID) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cc, ok := c.chanCtl[ch]
+	if !ok {
+		return ""
+	}
+	return cc.voltsPerDiv.Value()
+}
+
+// State returns the state of the acquisition, as shown on the display
+// of an oscilloscope: Stop, Roll or the trigger mode, followed by
+// the acquisition mode, if it's not normal.
+func (c *Controller) State() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	mode := "none"
+	if p, ok := c.trigger["mode"]; ok {
+		mode = p.Value()
+	}
+	state := "Stop"
+	switch {
+	case c.running && c.roll:
+		state = "Roll"
+	case c.running:
+		state = map[string]string{
+			"none":   "Run",
+			"single": "Single",
+			"normal": "Normal",
+			"auto":   "Auto",
+		}[mode]
+	}
+	if p := c.Param("acquisition"); p != nil && p.Value() != "normal" {
+		state += ", " + p.Value()
+	}
+	return state
+}
@@ -0,0 +1,196 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package control
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zagrodzki/goscope/dummy"
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/triggers"
+)
+
+// testFrontend records the runs and the lengths of the sweeps.
+type testFrontend struct {
+	mu     sync.Mutex
+	runs   []Run
+	sweeps map[bool][]int
+	err    error
+}
+
+func (f *testFrontend) Reset(r Run) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runs = append(f.runs, r)
+	f.sweeps = make(map[bool][]int)
+}
+
+func (f *testFrontend) Sweep(data []scope.ChannelData, full bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, d := range data {
+		f.sweeps[full] = append(f.sweeps[full], len(d.Samples))
+	}
+}
+
+func (f *testFrontend) Error(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// wait waits until the frontend received a full sweep of the current run
+// and returns the run and the lengths of its sweeps.
+func (f *testFrontend) wait(t *testing.T) (Run, map[bool][]int) {
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(time.Millisecond) {
+		f.mu.Lock()
+		if len(f.sweeps[true]) > 0 {
+			s := map[bool][]int{
+				false: append([]int(nil), f.sweeps[false]...),
+				true:  append([]int(nil), f.sweeps[true]...),
+			}
+			r := f.runs[len(f.runs)-1]
+			f.mu.Unlock()
+			return r, s
+		}
+		f.mu.Unlock()
+	}
+	t.Fatalf("no sweep received")
+	return Run{}, nil
+}
+
+func newTestController(t *testing.T, cfg Config) (*Controller, *testFrontend) {
+	t.Helper()
+	dev, err := dummy.Open("sin,square")
+	if err != nil {
+		t.Fatalf("dummy.Open: %v", err)
+	}
+	cfg.Trigger = dev.(*triggers.Trigger).TriggerParams()
+	fe := &testFrontend{}
+	return New(dev, fe, cfg), fe
+}
+
+func TestControllerSweeps(t *testing.T) {
+	c, fe := newTestController(t, Config{TimePerDiv: scope.Millisecond, VoltsPerDiv: 1})
+	c.Start()
+	defer c.Stop()
+	r, sweeps := fe.wait(t)
+	// the dummy device samples every millisecond.
+	if want := (Run{Interval: scope.Millisecond, TimeBase: 10 * scope.Millisecond, SweepLen: 10}); r != want {
+		t.Errorf("run: got %+v, want %+v", r, want)
+	}
+	for _, n := range sweeps[true] {
+		if n != 10 {
+			t.Fatalf("sweep lengths: got %v, want all 10", sweeps[true])
+		}
+	}
+
+	if got, want := c.StepTimeBase(true), "2ms"; got != want {
+		t.Errorf("StepTimeBase(true): got %q, want %q", got, want)
+	}
+	if r, _ := fe.wait(t); r.SweepLen != 20 {
+		t.Errorf("run after StepTimeBase: got %+v, want SweepLen 20", r)
+	}
+	if !c.SelectChannel(1) || c.SelectChannel(2) {
+		t.Errorf("SelectChannel(1), SelectChannel(2): want true, false")
+	}
+	if ch, v := c.StepVoltsPerDiv(false); ch != "square" || v != "500mV" {
+		t.Errorf("StepVoltsPerDiv(false): got %s %q, want square 500mV", ch, v)
+	}
+	if got, want := c.TraceParams("square"), (scope.TraceParams{Zero: 0.5, PerDiv: 0.5}); got != want {
+		t.Errorf("TraceParams(square): got %+v, want %+v", got, want)
+	}
+	if c.RunStop() || c.State() != "Stop" {
+		t.Errorf("RunStop(): got running, state %q, want stopped", c.State())
+	}
+}
+
+func TestControllerParams(t *testing.T) {
+	c, _ := newTestController(t, Config{TimePerDiv: scope.Millisecond, VoltsPerDiv: 1})
+	if err := c.Set(c.Param("time/div"), "5ms"); err != nil {
+		t.Fatalf("Set(time/div, 5ms): %v", err)
+	}
+	// the changes are applied also while the device is stopped.
+	if got, want := c.TimeBase(), 50*scope.Millisecond; got != want {
+		t.Errorf("TimeBase(): got %v, want %v", got, want)
+	}
+	for _, tc := range []struct {
+		up   bool
+		want string
+	}{
+		// the trigger keys cycle through the values in both directions.
+		{true, "single"},
+		{false, "none"},
+		{false, "auto"},
+	} {
+		if p, v, err := c.StepTrigger("mode", tc.up); p == nil || err != nil || v != tc.want {
+			t.Errorf("StepTrigger(mode, %v): got %v, %q, %v, want %q", tc.up, p, v, err, tc.want)
+		}
+	}
+	if c.State() != "Stop" {
+		t.Errorf("State(): got %q, want Stop", c.State())
+	}
+	if p, _, _ := c.StepTrigger("holdoff", true); p != nil {
+		t.Errorf("StepTrigger(holdoff): got %v, want nil", p)
+	}
+	// the generic param controls stop at the first and the last value.
+	p := c.SelectParam()
+	for p.Name() != "mode" {
+		p = c.SelectParam()
+	}
+	for i := 0; i < 2; i++ {
+		if p, v, err := c.StepParam(true); p.Name() != "mode" || err != nil || v != "none" {
+			t.Errorf("StepParam(true): got %v, %q, %v, want mode none", p, v, err)
+		}
+	}
+}
+
+func TestControllerRoll(t *testing.T) {
+	c, fe := newTestController(t, Config{TimePerDiv: 50 * scope.Microsecond, VoltsPerDiv: 1, RollAbove: 200 * scope.Millisecond})
+	if err := c.Set(c.Param("mode"), "normal"); err != nil {
+		t.Fatalf("Set(mode, normal): %v", err)
+	}
+	if err := c.Set(c.Param("time/div"), "100ms"); err != nil {
+		t.Fatalf("Set(time/div, 100ms): %v", err)
+	}
+	c.Start()
+	defer c.Stop()
+	if r, _ := fe.wait(t); r.Roll {
+		t.Errorf("run at 100ms/div: got %+v, want no roll mode", r)
+	}
+	c.StepTimeBase(true)
+	r, sweeps := fe.wait(t)
+	if !r.Roll || !c.Roll() || c.State() != "Roll" {
+		t.Errorf("run at 200ms/div: got %+v, state %q, want the roll mode", r, c.State())
+	}
+	if got := c.Value("mode"); got != "none" {
+		t.Errorf("trigger mode in the roll mode: got %q, want none", got)
+	}
+	if _, _, err := c.StepTrigger("mode", true); err == nil {
+		t.Errorf("StepTrigger(mode) in the roll mode: got nil error")
+	}
+	// the chunks of 1000 samples are delivered as they arrive,
+	// before the sweep of 2000 samples is complete.
+	if len(sweeps[false]) == 0 {
+		t.Errorf("roll mode: got no partial sweeps")
+	}
+	c.StepTimeBase(false)
+	if got := c.Value("mode"); c.Roll() || got != "normal" {
+		t.Errorf("trigger mode after the roll mode: got %q, roll %v, want normal", got, c.Roll())
+	}
+}
@@ -0,0 +1,225 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package cursors implements the measurement cursors of the oscilloscope
+// display: a pair of time cursors and a pair of voltage cursors, placed
+// in pixels of the display and read out as time and voltage.
+package cursors
+
+import (
+	"image"
+	"math"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Screen maps the pixels of the display area to the time since
+// the beginning of the sweep and to the voltage of a channel.
+// The mapping is the same as the one used to draw the traces.
+type Screen struct {
+	// Rect is the display area.
+	Rect image.Rectangle
+	// TimeBase is the length of the sweep displayed across Rect.
+	TimeBase scope.Duration
+	// Rows is the number of vertical divisions of Rect.
+	Rows int
+}
+
+// clamp returns v limited to the range [lo, hi].
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// Time returns the time at the column x. Columns outside of Rect
+// are treated as the nearest edge of Rect.
+func (s Screen) Time(x int) scope.Duration {
+	w := s.Rect.Dx() - 1
+	if w <= 0 {
+		return 0
+	}
+	x = clamp(x, s.Rect.Min.X, s.Rect.Max.X-1) - s.Rect.Min.X
+	return scope.Duration(math.Round(float64(x) / float64(w) * float64(s.TimeBase)))
+}
+
+// X returns the column showing time t.
+func (s Screen) X(t scope.Duration) int {
+	if s.TimeBase == 0 {
+		return s.Rect.Min.X
+	}
+	return s.Rect.Min.X + int(math.Round(float64(t)/float64(s.TimeBase)*float64(s.Rect.Dx()-1)))
+}
+
+// Voltage returns the voltage at the row y for a channel displayed with tp.
+// Rows outside of Rect are treated as the nearest edge of Rect.
+func (s Screen) Voltage(y int, tp scope.TraceParams) scope.Voltage {
+	h := s.Rect.Dy() - 1
+	if h <= 0 {
+		return 0
+	}
+	y = s.Rect.Max.Y - 1 - clamp(y, s.Rect.Min.Y, s.Rect.Max.Y-1)
+	return scope.Voltage((float64(y)/float64(h) - tp.Zero) * float64(s.Rows) * tp.PerDiv)
+}
+
+// Y returns the row showing voltage v of a channel displayed with tp.
+func (s Screen) Y(v scope.Voltage, tp scope.TraceParams) int {
+	pos := float64(v)/(float64(s.Rows)*tp.PerDiv) + tp.Zero
+	return s.Rect.Max.Y - 1 - int(math.Round(pos*float64(s.Rect.Dy()-1)))
+}
+
+// Cursor identifies one of the cursors.
+type Cursor int
+
+// The cursors.
+const (
+	// None means no cursor.
+	None Cursor = iota
+	// T1 and T2 are the time cursors, vertical lines on the display.
+	T1
+	T2
+	// V1 and V2 are the voltage cursors, horizontal lines on the display.
+	V1
+	V2
+)
+
+// Cursors holds the positions of the time and the voltage cursors.
+type Cursors struct {
+	Screen Screen
+	// ShowTime and ShowVoltage enable the time and the voltage cursors.
+	ShowTime, ShowVoltage bool
+	// T are the positions of the time cursors, relative to the
+	// beginning of the sweep.
+	T [2]scope.Duration
+	// V are the positions of the voltage cursors of Channel.
+	V [2]scope.Voltage
+	// Channel is the channel measured by the voltage cursors,
+	// displayed with TraceParams.
+	Channel     scope.ChanID
+	TraceParams scope.TraceParams
+}
+
+// Reset places the cursors at a quarter and at three quarters of
+// the width and the height of the display.
+func (c *Cursors) Reset() {
+	r := c.Screen.Rect
+	c.T = [2]scope.Duration{c.Screen.Time(r.Min.X + r.Dx()/4), c.Screen.Time(r.Min.X + r.Dx()*3/4)}
+	c.V = [2]scope.Voltage{c.Screen.Voltage(r.Min.Y+r.Dy()*3/4, c.TraceParams), c.Screen.Voltage(r.Min.Y+r.Dy()/4, c.TraceParams)}
+}
+
+// Nearest returns the enabled cursor closest to the point p,
+// or None if no cursors are enabled.
+func (c *Cursors) Nearest(p image.Point) Cursor {
+	ret, best := None, math.MaxInt32
+	check := func(cur Cursor, d int) {
+		if d < 0 {
+			d = -d
+		}
+		if d < best {
+			ret, best = cur, d
+		}
+	}
+	if c.ShowTime {
+		check(T1, p.X-c.Screen.X(c.T[0]))
+		check(T2, p.X-c.Screen.X(c.T[1]))
+	}
+	if c.ShowVoltage {
+		check(V1, p.Y-c.Screen.Y(c.V[0], c.TraceParams))
+		check(V2, p.Y-c.Screen.Y(c.V[1], c.TraceParams))
+	}
+	return ret
+}
+
+// Move moves the cursor cur to the point p.
+func (c *Cursors) Move(cur Cursor, p image.Point) {
+	switch cur {
+	case T1, T2:
+		c.T[cur-T1] = c.Screen.Time(p.X)
+	case V1, V2:
+		c.V[cur-V1] = c.Screen.Voltage(p.Y, c.TraceParams)
+	}
+}
+
+// ValueAt returns the value of the sweep samples, taken every interval,
+// at the time t, interpolated linearly between the samples.
+// ok is false if t is before the first or beyond the last sample.
+func ValueAt(samples []scope.Voltage, interval, t scope.Duration) (v scope.Voltage, ok bool) {
+	if len(samples) == 0 || interval == 0 {
+		return 0, false
+	}
+	// a time before the first sample wraps around to a very large
+	// Duration, the quotient is checked before the conversion to int
+	// to keep it from overflowing to a negative index.
+	if t/interval >= scope.Duration(len(samples)) {
+		return 0, false
+	}
+	i := int(t / interval)
+	if i == len(samples)-1 {
+		return samples[i], t == scope.Duration(i)*interval
+	}
+	frac := scope.Voltage(t-scope.Duration(i)*interval) / scope.Voltage(interval)
+	return samples[i] + frac*(samples[i+1]-samples[i]), true
+}
+
+// ChannelValues are the values of a channel at the time cursors.
+type ChannelValues struct {
+	ID scope.ChanID
+	V  [2]scope.Voltage
+	// OK is false for the cursors outside of the sweep.
+	OK [2]bool
+}
+
+// Readout holds the differences between the cursors and the values
+// of the channels at the time cursors.
+type Readout struct {
+	// DeltaT is the distance between the time cursors.
+	DeltaT scope.Duration
+	// Freq is 1/DeltaT, in Hz, or 0 if the time cursors overlap.
+	Freq float64
+	// DeltaV is the difference V2-V1 between the voltage cursors.
+	DeltaV scope.Voltage
+	// Values are the values of the channels at the time cursors,
+	// empty if the time cursors are not enabled.
+	Values []ChannelValues
+}
+
+// Readout returns the readout of the cursors for the sweep data,
+// sampled every interval.
+func (c *Cursors) Readout(data []scope.ChannelData, interval scope.Duration) Readout {
+	var r Readout
+	if c.T[1] > c.T[0] {
+		r.DeltaT = c.T[1] - c.T[0]
+	} else {
+		r.DeltaT = c.T[0] - c.T[1]
+	}
+	if r.DeltaT > 0 {
+		r.Freq = float64(scope.Second) / float64(r.DeltaT)
+	}
+	r.DeltaV = c.V[1] - c.V[0]
+	if !c.ShowTime {
+		return r
+	}
+	for _, d := range data {
+		cv := ChannelValues{ID: d.ID}
+		for i, t := range c.T {
+			cv.V[i], cv.OK[i] = ValueAt(d.Samples, interval, t)
+		}
+		r.Values = append(r.Values, cv)
+	}
+	return r
+}
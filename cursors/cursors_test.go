@@ -0,0 +1,143 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package cursors
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// screen is 101x81 pixels, 10ms wide and 8 divisions high,
+// so that every pixel is 100µs wide.
+var screen = Screen{
+	Rect:     image.Rect(10, 20, 111, 101),
+	TimeBase: 10 * scope.Millisecond,
+	Rows:     8,
+}
+
+func TestScreen(t *testing.T) {
+	tp := scope.TraceParams{Zero: 0.5, PerDiv: 1}
+	for _, tc := range []struct {
+		x    int
+		want scope.Duration
+	}{
+		{10, 0},
+		{11, 100 * scope.Microsecond},
+		{60, 5 * scope.Millisecond},
+		{110, 10 * scope.Millisecond},
+		{200, 10 * scope.Millisecond},
+		{0, 0},
+	} {
+		if got := screen.Time(tc.x); got != tc.want {
+			t.Errorf("Time(%d): got %v, want %v", tc.x, got, tc.want)
+		}
+	}
+	for _, x := range []int{10, 37, 110} {
+		if got := screen.X(screen.Time(x)); got != x {
+			t.Errorf("X(Time(%d)): got %d", x, got)
+		}
+	}
+	for _, tc := range []struct {
+		y    int
+		want scope.Voltage
+	}{
+		{100, -4},
+		{60, 0},
+		{20, 4},
+		{50, 1},
+		{0, 4},
+	} {
+		if got := screen.Voltage(tc.y, tp); math.Abs(float64(got-tc.want)) > 1e-9 {
+			t.Errorf("Voltage(%d): got %v, want %v", tc.y, got, tc.want)
+		}
+	}
+	for _, y := range []int{20, 43, 100} {
+		if got := screen.Y(screen.Voltage(y, tp), tp); got != y {
+			t.Errorf("Y(Voltage(%d)): got %d", y, got)
+		}
+	}
+}
+
+func TestValueAt(t *testing.T) {
+	samples := []scope.Voltage{0, 1, 3}
+	for _, tc := range []struct {
+		t      scope.Duration
+		want   scope.Voltage
+		wantOK bool
+	}{
+		{0, 0, true},
+		{500 * scope.Microsecond, 0.5, true},
+		{1500 * scope.Microsecond, 2, true},
+		{2 * scope.Millisecond, 3, true},
+		{2500 * scope.Microsecond, 3, false},
+		{5 * scope.Millisecond, 0, false},
+	} {
+		got, ok := ValueAt(samples, scope.Millisecond, tc.t)
+		if ok != tc.wantOK || (ok && got != tc.want) {
+			t.Errorf("ValueAt(%v): got %v, %v, want %v, %v", tc.t, got, ok, tc.want, tc.wantOK)
+		}
+	}
+	// the times before the first sample wrap around, Duration is unsigned.
+	for _, before := range []scope.Duration{math.MaxUint64, math.MaxUint64 - 499} {
+		for _, interval := range []scope.Duration{1, scope.Millisecond} {
+			if got, ok := ValueAt(samples, interval, before); ok {
+				t.Errorf("ValueAt(%d) every %v: got %v, true, want 0, false", uint64(before), interval, got)
+			}
+		}
+	}
+}
+
+func TestCursors(t *testing.T) {
+	c := &Cursors{
+		Screen:      screen,
+		ShowTime:    true,
+		TraceParams: scope.TraceParams{Zero: 0.5, PerDiv: 1},
+	}
+	c.Reset()
+	if got, want := c.T, [2]scope.Duration{2500 * scope.Microsecond, 7500 * scope.Microsecond}; got != want {
+		t.Errorf("T after Reset(): got %v, want %v", got, want)
+	}
+	if got, want := c.V, [2]scope.Voltage{-2, 2}; got != want {
+		t.Errorf("V after Reset(): got %v, want %v", got, want)
+	}
+	if got := c.Nearest(image.Point{20, 30}); got != T1 {
+		t.Errorf("Nearest() with time cursors: got %v, want T1", got)
+	}
+	c.ShowVoltage = true
+	if got := c.Nearest(image.Point{60, 39}); got != V2 {
+		t.Errorf("Nearest() with all cursors: got %v, want V2", got)
+	}
+	c.Move(T2, image.Point{45, 0})
+	c.Move(V1, image.Point{0, 70})
+	r := c.Readout([]scope.ChannelData{{ID: "a", Samples: []scope.Voltage{0, 1, 2, 3, 4, 5, 6, 7, 8}}}, scope.Millisecond)
+	if got, want := r.DeltaT, scope.Millisecond; got != want {
+		t.Errorf("DeltaT: got %v, want %v", got, want)
+	}
+	if got, want := r.Freq, 1000.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Freq: got %v, want %v", got, want)
+	}
+	if got, want := r.DeltaV, scope.Voltage(3); math.Abs(float64(got-want)) > 1e-9 {
+		t.Errorf("DeltaV: got %v, want %v", got, want)
+	}
+	if len(r.Values) != 1 {
+		t.Fatalf("Values: got %v, want 1 channel", r.Values)
+	}
+	if got, want := r.Values[0].V, [2]scope.Voltage{2.5, 3.5}; got != want || r.Values[0].OK != [2]bool{true, true} {
+		t.Errorf("Values[0]: got %v, %v, want %v", got, r.Values[0].OK, want)
+	}
+}
@@ -0,0 +1,140 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package decoders
+
+import (
+	"sync"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// maxAnnotations is the number of annotations kept by the Analyzer,
+// the oldest ones are discarded first.
+const maxAnnotations = 10000
+
+// Analyzer runs protocol decoders on the data passing from the device
+// to the recorder, collecting the annotations. The data is passed
+// to the recorder unchanged.
+// Analyzer implements both scope.Device interface (used by UI)
+// and scope.DataRecorder interface (used by underlying device).
+//
+// Decoders expect a continuous stream of samples. If the Analyzer is
+// attached to a trigger, the decoders will only see the triggered sweeps.
+type Analyzer struct {
+	scope.Device
+	decs []Decoder
+	rec  scope.DataRecorder
+
+	mu   sync.Mutex
+	anns []Annotation
+	// done is closed when the run started by the last Reset finishes.
+	done chan struct{}
+}
+
+// NewAnalyzer returns an initialized Analyzer, running decoders decs
+// on the data from dev.
+func NewAnalyzer(dev scope.Device, decs ...Decoder) *Analyzer {
+	return &Analyzer{
+		Device: dev,
+		decs:   decs,
+	}
+}
+
+// Attach configures the analyzer to pass the data to rec.
+func (a *Analyzer) Attach(rec scope.DataRecorder) {
+	a.rec = rec
+	a.Device.Attach(a)
+}
+
+// TimeBase returns the timebase of the underlying recorder.
+func (a *Analyzer) TimeBase() scope.Duration {
+	return a.rec.TimeBase()
+}
+
+// Reset initializes the recording. Annotations collected so far are discarded.
+// Reset waits for the previous recording to finish, i.e. for the device
+// to close its data channel.
+func (a *Analyzer) Reset(i scope.Duration, ch <-chan []scope.ChannelData) {
+	if a.done != nil {
+		// the decoders are used by the previous run until it finishes.
+		<-a.done
+	}
+	a.Clear()
+	for _, d := range a.decs {
+		d.Reset(i)
+	}
+	out := make(chan []scope.ChannelData, 2)
+	a.rec.Reset(i, out)
+	done := make(chan struct{})
+	a.done = done
+	go a.run(ch, out, done)
+}
+
+// Error passes the error down to the underlying recorder.
+func (a *Analyzer) Error(err error) {
+	a.rec.Error(err)
+}
+
+// Decoders returns the decoders run by the analyzer.
+func (a *Analyzer) Decoders() []Decoder {
+	return a.decs
+}
+
+// Annotations returns the annotations collected since the last Reset
+// or Clear, sorted by their start time. Only the last maxAnnotations
+// annotations are kept.
+func (a *Analyzer) Annotations() []Annotation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ret := make([]Annotation, len(a.anns))
+	copy(ret, a.anns)
+	Sort(ret)
+	return ret
+}
+
+// Clear discards the collected annotations.
+func (a *Analyzer) Clear() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.anns = nil
+}
+
+func (a *Analyzer) add(anns []Annotation) {
+	if len(anns) == 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.anns = append(a.anns, anns...)
+	if n := len(a.anns) - maxAnnotations; n > 0 {
+		a.anns = append(a.anns[:0], a.anns[n:]...)
+	}
+}
+
+func (a *Analyzer) run(in <-chan []scope.ChannelData, out chan<- []scope.ChannelData, done chan<- struct{}) {
+	for d := range in {
+		for _, dec := range a.decs {
+			a.add(dec.Decode(d))
+		}
+		out <- d
+	}
+	for _, dec := range a.decs {
+		if f, ok := dec.(Flusher); ok {
+			a.add(f.Flush())
+		}
+	}
+	close(out)
+	close(done)
+}
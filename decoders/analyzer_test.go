@@ -0,0 +1,179 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package decoders
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/testutil"
+)
+
+type fakeDev struct{}
+
+func (fakeDev) String() string            { return "fake" }
+func (fakeDev) Channels() []scope.ChanID  { return []scope.ChanID{testChan} }
+func (fakeDev) Attach(scope.DataRecorder) {}
+func (fakeDev) Start()                    {}
+func (fakeDev) Stop()                     {}
+
+func TestCRC16(t *testing.T) {
+	// example from the Modbus over serial line specification.
+	data := []byte{0x02, 0x07}
+	if got, want := crc16(data), uint16(0x1241); got != want {
+		t.Errorf("crc16(%x): got 0x%04x, want 0x%04x", data, got, want)
+	}
+}
+
+func TestAnalyzerModbus(t *testing.T) {
+	line := &serialLine{bitLen: 10}
+	line.idle(10)
+	// read 3 holding registers starting at 0x6b from device 0x11.
+	line.send(0x11, 0x03, 0x00, 0x6b, 0x00, 0x03, 0x76, 0x87)
+	line.idle(50)
+	// response with a broken CRC.
+	line.send(0x11, 0x03, 0x06, 0x02, 0x2b, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00)
+	line.idle(50)
+	// exception response, last frame in the capture.
+	line.send(0x0a, 0x81, 0x02)
+	line.send(crcBytes(0x0a, 0x81, 0x02)...)
+	line.idle(2)
+	samples := waveform(line.segs)
+
+	buf := testutil.NewBufferRecorder(scope.Millisecond)
+	an := NewAnalyzer(fakeDev{}, Stack(NewUART(UARTConfig{
+		Channel:   testChan,
+		Threshold: Threshold{Level: 1.5, Hysteresis: 0.2},
+		BaudRate:  100e3,
+	}), NewModbus()))
+	an.Attach(buf)
+	in := make(chan []scope.ChannelData)
+	an.Reset(scope.Microsecond, in)
+	for len(samples) > 0 {
+		n := 100
+		if n > len(samples) {
+			n = len(samples)
+		}
+		in <- []scope.ChannelData{{ID: testChan, Samples: samples[:n]}}
+		samples = samples[n:]
+	}
+	close(in)
+	if _, err := buf.Wait(); err != nil {
+		t.Fatalf("BufferRecorder.Wait: %v", err)
+	}
+
+	if got, want := an.Decoders()[0].Name(), "UART/Modbus"; got != want {
+		t.Errorf("stack name: got %q, want %q", got, want)
+	}
+	var modbus, warnings []string
+	for _, a := range an.Annotations() {
+		switch {
+		case a.Decoder == "Modbus" && a.Row == RowProtocol:
+			modbus = append(modbus, a.Label)
+		case a.Row == RowWarnings:
+			warnings = append(warnings, a.Decoder+": "+a.Label)
+		}
+	}
+	wantModbus := []string{
+		"address 17", "Read Holding Registers", "data 00 6b 00 03", "CRC ok",
+		"address 17", "Read Holding Registers", "data 06 02 2b 00 00 00 64", "CRC error",
+		"address 10", "exception response to Read Coils", "data 02", "CRC ok",
+	}
+	if !reflect.DeepEqual(modbus, wantModbus) {
+		t.Errorf("Modbus annotations: got %q, want %q", modbus, wantModbus)
+	}
+	wantWarnings := []string{"Modbus: CRC mismatch: got 0x0000, want 0xbac8"}
+	if !reflect.DeepEqual(warnings, wantWarnings) {
+		t.Errorf("warnings: got %q, want %q", warnings, wantWarnings)
+	}
+
+	an.Clear()
+	if got := an.Annotations(); len(got) != 0 {
+		t.Errorf("Annotations() after Clear(): got %v, want none", got)
+	}
+}
+
+func TestAnalyzerRestart(t *testing.T) {
+	line := &serialLine{bitLen: 10}
+	line.idle(10)
+	line.send(0x11, 0x03, 0x00, 0x6b, 0x00, 0x03, 0x76, 0x87)
+	// the frame is still open when the recording stops.
+	line.send(0x11)
+	samples := waveform(line.segs)
+
+	rec := testutil.NewDiscardRecorder(scope.Millisecond)
+	an := NewAnalyzer(fakeDev{}, Stack(NewUART(UARTConfig{
+		Channel:   testChan,
+		Threshold: Threshold{Level: 1.5, Hysteresis: 0.2},
+		BaudRate:  100e3,
+	}), NewModbus()))
+	an.Attach(rec)
+	for run := 0; run < 3; run++ {
+		in := make(chan []scope.ChannelData, 1)
+		an.Reset(scope.Microsecond, in)
+		if got := an.Annotations(); len(got) != 0 {
+			t.Errorf("run %d: Annotations() after Reset(): got %v, want none", run, got)
+		}
+		in <- []scope.ChannelData{{ID: testChan, Samples: samples}}
+		// the next Reset follows while the run may still be decoding.
+		close(in)
+	}
+	rec.Wait()
+}
+
+// countDecoder annotates every sample.
+type countDecoder struct {
+	interval scope.Duration
+	pos      int
+}
+
+func (*countDecoder) Name() string             { return "count" }
+func (d *countDecoder) Reset(i scope.Duration) { d.interval, d.pos = i, 0 }
+func (d *countDecoder) Decode(data []scope.ChannelData) []Annotation {
+	var ret []Annotation
+	for range data[0].Samples {
+		ret = append(ret, Annotation{Decoder: "count", Row: RowBits, Start: scope.Duration(d.pos) * d.interval})
+		d.pos++
+	}
+	return ret
+}
+
+func TestAnalyzerMaxAnnotations(t *testing.T) {
+	rec := testutil.NewDiscardRecorder(scope.Millisecond)
+	an := NewAnalyzer(fakeDev{}, &countDecoder{})
+	an.Attach(rec)
+	in := make(chan []scope.ChannelData)
+	an.Reset(scope.Microsecond, in)
+	const chunks, chunkLen = 25, 1000
+	for i := 0; i < chunks; i++ {
+		in <- []scope.ChannelData{{ID: testChan, Samples: make([]scope.Voltage, chunkLen)}}
+	}
+	close(in)
+	rec.Wait()
+	got := an.Annotations()
+	if len(got) != maxAnnotations {
+		t.Fatalf("Annotations(): got %d, want %d", len(got), maxAnnotations)
+	}
+	// the oldest annotations are discarded.
+	if got, want := got[0].Start, scope.Duration(chunks*chunkLen-maxAnnotations)*scope.Microsecond; got != want {
+		t.Errorf("start of the first annotation: got %v, want %v", got, want)
+	}
+}
+
+func crcBytes(data ...byte) []byte {
+	crc := crc16(data)
+	return []byte{byte(crc), byte(crc >> 8)}
+}
@@ -0,0 +1,200 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package decoders contains protocol decoders, turning sampled signals
+// into a sequence of annotations describing the decoded data.
+//
+// A Decoder consumes the samples directly, e.g. decoding bytes sent over
+// a serial line. An AnnotationDecoder consumes annotations produced by
+// another decoder and can be stacked on top of it with Stack, e.g. to
+// decode Modbus frames from the bytes decoded by UART. Decoders are run on
+// the data coming from a device by an Analyzer.
+package decoders
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Rows used by the decoders in this package. Decoders can define
+// their own rows as well.
+const (
+	// RowBits holds individual decoded bits.
+	RowBits = "bits"
+	// RowBytes holds bytes assembled from bits.
+	RowBytes = "bytes"
+	// RowProtocol holds protocol level events, commands and data.
+	RowProtocol = "protocol"
+	// RowWarnings holds decoding errors, e.g. framing or timing violations.
+	RowWarnings = "warnings"
+)
+
+// Annotation is a single item produced by a decoder, e.g. a bit, a byte
+// or a protocol command, spanning a period of time of the capture.
+type Annotation struct {
+	// Decoder is the name of the decoder that produced the annotation.
+	Decoder string
+	// Row groups annotations of the same kind, e.g. bits or bytes.
+	Row string
+	// Start and End delimit the annotated period, measured from the
+	// beginning of the capture.
+	Start, End scope.Duration
+	// Label is the description of the annotation, intended for the UI.
+	Label string
+	// Value holds the decoded value, if any. The type of the value
+	// depends on the decoder and row, e.g. a byte for RowBytes.
+	Value interface{}
+}
+
+// String returns a string representation of the annotation.
+func (a Annotation) String() string {
+	return fmt.Sprintf("%s-%s %s/%s: %s", a.Start, a.End, a.Decoder, a.Row, a.Label)
+}
+
+// Decoder represents a protocol decoder consuming the samples.
+type Decoder interface {
+	// Name returns the name of the decoder, for the UI.
+	Name() string
+
+	// Reset prepares the decoder for a new capture, with samples
+	// taken every interval.
+	Reset(interval scope.Duration)
+
+	// Decode processes the next chunk of samples and returns
+	// the annotations completed within that chunk.
+	Decode([]scope.ChannelData) []Annotation
+}
+
+// AnnotationDecoder represents a protocol decoder stacked on top of
+// another decoder, consuming the annotations produced by that decoder.
+type AnnotationDecoder interface {
+	// Name returns the name of the decoder, for the UI.
+	Name() string
+
+	// Reset prepares the decoder for a new capture.
+	Reset()
+
+	// DecodeAnnotations processes the next batch of annotations from
+	// the lower decoder and returns the annotations completed within
+	// that batch.
+	DecodeAnnotations([]Annotation) []Annotation
+}
+
+// Flusher is implemented by decoders that hold on to incomplete data,
+// waiting for more input to interpret it, e.g. a frame that ends with
+// a period of silence on the line.
+type Flusher interface {
+	// Flush is called at the end of the capture and returns
+	// the annotations for any data held by the decoder.
+	Flush() []Annotation
+}
+
+// Sort sorts the annotations by their start time, keeping the order of
+// annotations starting at the same time.
+func Sort(anns []Annotation) {
+	sort.SliceStable(anns, func(i, j int) bool { return anns[i].Start < anns[j].Start })
+}
+
+// Write writes the annotations to w, one per line, as tab separated
+// start, end, decoder, row and label values.
+func Write(w io.Writer, anns []Annotation) error {
+	for _, a := range anns {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.Start, a.End, a.Decoder, a.Row, a.Label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Threshold defines how sampled voltage is translated into logic levels.
+type Threshold struct {
+	// Level is the voltage separating the low and high logic levels.
+	Level scope.Voltage
+	// Hysteresis is the width of the band around Level, within which
+	// the logic level does not change.
+	Hysteresis scope.Voltage
+}
+
+type logicLevel int
+
+const (
+	levelUnknown logicLevel = iota
+	levelLow
+	levelHigh
+)
+
+// edgeDetector translates the samples of a single channel into
+// a sequence of logic level changes.
+type edgeDetector struct {
+	ch    scope.ChanID
+	thr   Threshold
+	state logicLevel
+	// pos is the index of the next sample, counted from the reset.
+	pos int
+}
+
+func (e *edgeDetector) reset() {
+	e.state = levelUnknown
+	e.pos = 0
+}
+
+// process calls f for every change of the logic level in the channel
+// samples, with the index of the first sample at the new level.
+// The initial level of the signal is not reported as a change.
+func (e *edgeDetector) process(data []scope.ChannelData, f func(pos int, high bool)) {
+	e.each(data, func(pos int, high, changed bool) {
+		if changed {
+			f(pos, high)
+		}
+	})
+}
+
+// each calls f for every sample of the channel with a known logic level.
+// changed is true if the level differs from the level of the previous sample.
+func (e *edgeDetector) each(data []scope.ChannelData, f func(pos int, high, changed bool)) {
+	var samples []scope.Voltage
+	for _, d := range data {
+		if d.ID == e.ch {
+			samples = d.Samples
+			break
+		}
+	}
+	hi := e.thr.Level + e.thr.Hysteresis/2
+	lo := e.thr.Level - e.thr.Hysteresis/2
+	for _, v := range samples {
+		newState := e.state
+		switch {
+		case v > hi:
+			newState = levelHigh
+		case v < lo:
+			newState = levelLow
+		}
+		if newState != levelUnknown {
+			f(e.pos, newState == levelHigh, newState != e.state && e.state != levelUnknown)
+		}
+		e.state = newState
+		e.pos++
+	}
+}
+
+// at converts a sample position to a timestamp.
+func at(pos float64, interval scope.Duration) scope.Duration {
+	if pos <= 0 {
+		return 0
+	}
+	return scope.Duration(pos * float64(interval))
+}
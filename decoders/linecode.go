@@ -0,0 +1,346 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package decoders
+
+import (
+	"fmt"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+const (
+	// number of level runs collected before the clock is recovered.
+	lcRecoveryRuns = 32
+	// how quickly the recovered clock follows changes in the signal.
+	lcTrackRate = 0.05
+	// NRZ runs longer than this many bits are treated as an idle line.
+	lcMaxRunBits = 64
+	// number of edges of the short runs kept while looking for the
+	// Manchester anchors. The oldest edges of a longer burst are dropped
+	// without decoding.
+	lcMaxBurst = 1024
+)
+
+// Coding is a line code, defining how bits are represented by the signal level.
+type Coding int
+
+const (
+	// Manchester represents every bit as a transition in the middle of
+	// the bit period, low to high for 1 and high to low for 0
+	// (IEEE 802.3 convention).
+	Manchester Coding = iota
+	// DiffManchester (biphase mark code) has a transition at the start of
+	// every bit. A 1 has an additional transition in the middle of the bit.
+	DiffManchester
+	// NRZ (non-return-to-zero level) represents 1 as high and 0 as low level.
+	NRZ
+	// NRZI (non-return-to-zero inverted) represents 1 as a transition at
+	// the start of the bit and 0 as no transition.
+	NRZI
+)
+
+// String returns the name of the line code.
+func (c Coding) String() string {
+	switch c {
+	case Manchester:
+		return "Manchester"
+	case DiffManchester:
+		return "differential Manchester"
+	case NRZ:
+		return "NRZ"
+	case NRZI:
+		return "NRZI"
+	}
+	return fmt.Sprintf("Coding(%d)", int(c))
+}
+
+// LineCodeConfig holds the parameters of the line code decoder.
+type LineCodeConfig struct {
+	// Channel is the channel carrying the signal.
+	Channel scope.ChanID
+	// Threshold is the logic level threshold.
+	Threshold Threshold
+	// Coding is the line code used by the signal.
+	Coding Coding
+	// BitRate is the expected bit rate, in bits per second. If 0,
+	// the bit rate is recovered from the signal.
+	BitRate float64
+	// Invert inverts the decoded bits, e.g. to decode the G.E. Thomas
+	// variant of Manchester code.
+	Invert bool
+}
+
+type edge struct {
+	pos    int
+	rising bool
+}
+
+// LineCode decodes bits of a signal using one of the line codes.
+//
+// With automatic clock recovery the decoder waits for a number of level
+// changes and estimates the duration of the shortest run of constant
+// level, which is half of the bit period for Manchester codes and the bit
+// period for NRZ codes. A Manchester signal consisting of alternating
+// zeros and ones only has no short runs and will be decoded at twice
+// the actual bit period. The estimate is then adjusted as the signal is
+// decoded, to follow small variations of the clock.
+type LineCode struct {
+	cfg      LineCodeConfig
+	edges    edgeDetector
+	interval scope.Duration
+
+	// unit is the shortest expected run of constant level, in samples.
+	// 0 if the clock was not recovered yet.
+	unit    float64
+	pending []edge
+	last    edge
+	hasLast bool
+
+	// Manchester codes: synced is true after the decoder found which
+	// edges are the anchors (always present transitions), mid-bit
+	// for Manchester or bit boundaries for differential Manchester.
+	synced     bool
+	between    bool
+	lastAnchor edge
+	burst      []edge
+}
+
+// NewLineCode returns a line code decoder using the configuration c.
+func NewLineCode(c LineCodeConfig) *LineCode {
+	return &LineCode{
+		cfg:   c,
+		edges: edgeDetector{ch: c.Channel, thr: c.Threshold},
+	}
+}
+
+// Name returns the name of the decoder.
+func (l *LineCode) Name() string { return l.cfg.Coding.String() }
+
+func (l *LineCode) biphase() bool {
+	return l.cfg.Coding == Manchester || l.cfg.Coding == DiffManchester
+}
+
+// Reset prepares the decoder for a new capture.
+func (l *LineCode) Reset(interval scope.Duration) {
+	l.edges.reset()
+	l.interval = interval
+	l.unit = 0
+	if l.cfg.BitRate > 0 {
+		l.unit = float64(scope.Second) / l.cfg.BitRate / float64(interval)
+		if l.biphase() {
+			l.unit /= 2
+		}
+	}
+	l.pending = nil
+	l.hasLast = false
+	l.synced = false
+	l.burst = nil
+}
+
+// BitRate returns the configured or recovered bit rate, in bits per second.
+// BitRate returns 0 if the clock was not recovered yet.
+func (l *LineCode) BitRate() float64 {
+	if l.unit == 0 {
+		return 0
+	}
+	bit := l.unit
+	if l.biphase() {
+		bit *= 2
+	}
+	return float64(scope.Second) / (bit * float64(l.interval))
+}
+
+// Decode processes a chunk of samples.
+func (l *LineCode) Decode(data []scope.ChannelData) []Annotation {
+	var ret []Annotation
+	l.edges.process(data, func(pos int, high bool) {
+		e := edge{pos, high}
+		if l.unit > 0 {
+			ret = l.edge(ret, e)
+			return
+		}
+		l.pending = append(l.pending, e)
+		if len(l.pending) <= lcRecoveryRuns {
+			return
+		}
+		l.recoverClock()
+		for _, e := range l.pending {
+			ret = l.edge(ret, e)
+		}
+		l.pending = nil
+	})
+	return ret
+}
+
+// recoverClock estimates the shortest run from the pending edges, as the
+// average of runs close to the shortest one.
+func (l *LineCode) recoverClock() {
+	shortest := -1
+	for i := 1; i < len(l.pending); i++ {
+		if r := l.pending[i].pos - l.pending[i-1].pos; shortest < 0 || r < shortest {
+			shortest = r
+		}
+	}
+	var sum, n int
+	for i := 1; i < len(l.pending); i++ {
+		if r := l.pending[i].pos - l.pending[i-1].pos; 2*r < 3*shortest {
+			sum += r
+			n++
+		}
+	}
+	l.unit = float64(sum) / float64(n)
+}
+
+func (l *LineCode) bit(ret []Annotation, start, end float64, b bool) []Annotation {
+	var v byte
+	if b != l.cfg.Invert {
+		v = 1
+	}
+	return append(ret, Annotation{
+		Decoder: l.Name(),
+		Row:     RowBits,
+		Start:   at(start, l.interval),
+		End:     at(end, l.interval),
+		Label:   fmt.Sprintf("%d", v),
+		Value:   v,
+	})
+}
+
+func (l *LineCode) warning(ret []Annotation, pos int, msg string) []Annotation {
+	return append(ret, Annotation{
+		Decoder: l.Name(),
+		Row:     RowWarnings,
+		Start:   at(float64(pos), l.interval),
+		End:     at(float64(pos), l.interval),
+		Label:   msg,
+	})
+}
+
+// edge processes a single level change, once the clock is known.
+func (l *LineCode) edge(ret []Annotation, e edge) []Annotation {
+	if !l.hasLast {
+		l.hasLast = true
+		l.last = e
+		l.burst = append(l.burst[:0], e)
+		return ret
+	}
+	run := float64(e.pos - l.last.pos)
+	k := int(run/l.unit + 0.5)
+	maxK := 2
+	if !l.biphase() {
+		maxK = lcMaxRunBits
+	}
+	if l.cfg.BitRate == 0 && k >= 1 && k <= maxK {
+		l.unit += (run/float64(k) - l.unit) * lcTrackRate
+	}
+	if l.biphase() {
+		ret = l.biphaseEdge(ret, e, k)
+	} else {
+		ret = l.nrzRun(ret, l.last, e, k)
+	}
+	l.last = e
+	return ret
+}
+
+// nrzRun decodes k bits of a run of constant level between edges from and to.
+func (l *LineCode) nrzRun(ret []Annotation, from, to edge, k int) []Annotation {
+	if k < 1 || k > lcMaxRunBits {
+		return ret
+	}
+	step := float64(to.pos-from.pos) / float64(k)
+	for i := 0; i < k; i++ {
+		start := float64(from.pos) + float64(i)*step
+		var b bool
+		switch l.cfg.Coding {
+		case NRZ:
+			b = from.rising
+		case NRZI:
+			b = i == 0
+		}
+		ret = l.bit(ret, start, start+step, b)
+	}
+	return ret
+}
+
+// biphaseEdge decodes Manchester codes. In both codes the signal has
+// anchor transitions that are present for every bit (mid-bit for
+// Manchester, bit boundaries for differential Manchester) and optional
+// transitions between the anchors. Anchors are one unit apart if there
+// is a transition in between, and two units apart otherwise.
+func (l *LineCode) biphaseEdge(ret []Annotation, e edge, k int) []Annotation {
+	if k < 1 || k > 2 || (l.synced && l.between && k == 2) {
+		if l.synced && k <= 2 {
+			ret = l.warning(ret, e.pos, "timing violation, lost sync")
+		}
+		// idle line or a violation, wait for a new burst.
+		l.synced = false
+		l.burst = append(l.burst[:0], e)
+		return ret
+	}
+	if l.synced {
+		switch {
+		case !l.between && k == 1:
+			l.between = true
+		case !l.between && k == 2:
+			ret = l.anchor(ret, l.lastAnchor, e, false)
+		default:
+			ret = l.anchor(ret, l.lastAnchor, e, true)
+			l.between = false
+		}
+		return ret
+	}
+	l.burst = append(l.burst, e)
+	if k == 1 {
+		if len(l.burst) > lcMaxBurst {
+			// drop an even number of edges, to keep the anchors in place.
+			l.burst = append(l.burst[:0], l.burst[lcMaxBurst/2:]...)
+		}
+		return ret
+	}
+	// A long run always spans between two anchors. The preceding runs
+	// in the burst are all short, every second edge is an anchor.
+	n := len(l.burst)
+	first := (n - 2) % 2
+	for i := first; i < n; i += 2 {
+		if i == first {
+			ret = l.anchor(ret, edge{pos: -1}, l.burst[i], false)
+			continue
+		}
+		ret = l.anchor(ret, l.burst[i-2], l.burst[i], true)
+	}
+	ret = l.anchor(ret, l.burst[n-2], l.burst[n-1], false)
+	l.synced = true
+	l.between = false
+	l.burst = l.burst[:0]
+	return ret
+}
+
+// anchor emits the bit completed by the anchor edge a, preceded by the
+// anchor prev (prev.pos is negative if not known). via is true if there
+// was a transition between prev and a.
+func (l *LineCode) anchor(ret []Annotation, prev, a edge, via bool) []Annotation {
+	l.lastAnchor = a
+	switch l.cfg.Coding {
+	case Manchester:
+		pos := float64(a.pos)
+		return l.bit(ret, pos-l.unit, pos+l.unit, a.rising)
+	case DiffManchester:
+		if prev.pos < 0 {
+			return ret
+		}
+		return l.bit(ret, float64(prev.pos), float64(a.pos), via)
+	}
+	return ret
+}
@@ -0,0 +1,183 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package decoders
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+const testBits = "1010010100111100111100000000111101010101100101100001001011101111"
+
+// encode returns the signal levels representing bits, one level per unit
+// (half bit for Manchester codes, full bit for NRZ codes). The signal
+// starts and ends with an idle period and the last bit is always followed
+// by a transition, so that every bit can be decoded.
+func encode(c Coding, bits string) []bool {
+	var ret []bool
+	emit := func(l bool, n int) {
+		for i := 0; i < n; i++ {
+			ret = append(ret, l)
+		}
+	}
+	switch c {
+	case Manchester:
+		emit(false, 8)
+		for _, b := range bits {
+			emit(b == '0', 1)
+			emit(b == '1', 1)
+		}
+		emit(false, 8)
+	case DiffManchester:
+		l := false
+		emit(l, 8)
+		for _, b := range bits {
+			l = !l
+			emit(l, 1)
+			if b == '1' {
+				l = !l
+			}
+			emit(l, 1)
+		}
+		emit(!l, 8)
+	case NRZ:
+		emit(bits[0] == '0', 80)
+		for _, b := range bits {
+			emit(b == '1', 1)
+		}
+		emit(bits[len(bits)-1] == '0', 80)
+	case NRZI:
+		l := false
+		emit(l, 80)
+		for _, b := range bits {
+			if b == '1' {
+				l = !l
+			}
+			emit(l, 1)
+		}
+		emit(!l, 80)
+	}
+	return ret
+}
+
+// sample converts levels into samples, with spu samples per level.
+func sample(levels []bool, spu float64) []scope.Voltage {
+	ret := make([]scope.Voltage, int(float64(len(levels))*spu))
+	for i := range ret {
+		if levels[int(float64(i)/spu)] {
+			ret[i] = 1
+		} else {
+			ret[i] = -1
+		}
+	}
+	return ret
+}
+
+func bitString(anns []Annotation) string {
+	return strings.Join(labels(anns, RowBits), "")
+}
+
+func invert(bits string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '0' {
+			return '1'
+		}
+		return '0'
+	}, bits)
+}
+
+func TestLineCode(t *testing.T) {
+	for _, tc := range []struct {
+		coding  Coding
+		spu     float64
+		bitRate float64
+		invert  bool
+		want    string
+	}{
+		{coding: Manchester, spu: 5, want: testBits},
+		{coding: Manchester, spu: 7.3, want: testBits},
+		{coding: Manchester, spu: 7.3, invert: true, want: invert(testBits)},
+		{coding: Manchester, spu: 50, bitRate: 1e4, want: testBits},
+		{coding: DiffManchester, spu: 5, want: testBits},
+		{coding: DiffManchester, spu: 6.7, want: testBits},
+		{coding: DiffManchester, spu: 50, bitRate: 1e4, want: testBits},
+		{coding: NRZ, spu: 5.3, want: testBits},
+		{coding: NRZ, spu: 11.1, invert: true, want: invert(testBits)},
+		{coding: NRZ, spu: 100, bitRate: 1e4, want: testBits},
+		{coding: NRZI, spu: 5.3, want: testBits},
+		{coding: NRZI, spu: 100, bitRate: 1e4, want: testBits},
+	} {
+		samples := sample(encode(tc.coding, testBits), tc.spu)
+		for _, chunk := range []int{len(samples), 64, 7} {
+			dec := NewLineCode(LineCodeConfig{
+				Channel: testChan,
+				Coding:  tc.coding,
+				BitRate: tc.bitRate,
+				Invert:  tc.invert,
+			})
+			anns := decodeChunks(dec, scope.Microsecond, samples, chunk)
+			if got := bitString(anns); got != tc.want {
+				t.Errorf("%s, %v samples per unit, bit rate %v, chunks of %d: got bits\n%s\nwant\n%s", tc.coding, tc.spu, tc.bitRate, chunk, got, tc.want)
+			}
+			if w := labels(anns, RowWarnings); len(w) > 0 {
+				t.Errorf("%s, %v samples per unit, chunks of %d: got warnings %q, want none", tc.coding, tc.spu, chunk, w)
+			}
+			wantRate := 1e6 / tc.spu
+			if tc.coding == Manchester || tc.coding == DiffManchester {
+				wantRate /= 2
+			}
+			if got := dec.BitRate(); math.Abs(got-wantRate) > wantRate*0.02 {
+				t.Errorf("%s, %v samples per unit: BitRate(): got %v, want %v", tc.coding, tc.spu, got, wantRate)
+			}
+		}
+	}
+}
+
+func TestLineCodeLostSync(t *testing.T) {
+	samples := sample(encode(Manchester, testBits), 8)
+	// a single sample spike in the middle of the burst.
+	mid := len(samples)/2/8*8 + 4
+	samples[mid] = -samples[mid]
+	dec := NewLineCode(LineCodeConfig{Channel: testChan, Coding: Manchester, BitRate: 62500})
+	anns := decodeChunks(dec, scope.Microsecond, samples, len(samples))
+	if w := labels(anns, RowWarnings); len(w) != 1 {
+		t.Errorf("got warnings %q, want exactly one", w)
+	}
+	// the decoder should sync again on the following bits.
+	if got, want := bitString(anns), testBits[len(testBits)-16:]; !strings.HasSuffix(got, want) {
+		t.Errorf("got bits %s, want suffix %s", got, want)
+	}
+}
+
+func TestLineCodeLongPreamble(t *testing.T) {
+	// a preamble of zeros has only short runs, the anchors can't be found
+	// until the first long run.
+	preamble := strings.Repeat("0", 3*lcMaxBurst)
+	dec := NewLineCode(LineCodeConfig{Channel: testChan, Coding: Manchester})
+	decodeChunks(dec, scope.Microsecond, sample(encode(Manchester, preamble), 5), 64)
+	if got := len(dec.burst); got > lcMaxBurst {
+		t.Errorf("burst after the preamble: got %d edges, want at most %d", got, lcMaxBurst)
+	}
+	anns := decodeChunks(dec, scope.Microsecond, sample(encode(Manchester, preamble+testBits), 5), 64)
+	if got := bitString(anns); !strings.HasSuffix(got, testBits) {
+		t.Errorf("got bits %s, want suffix %s", got, testBits)
+	}
+	if w := labels(anns, RowWarnings); len(w) > 0 {
+		t.Errorf("got warnings %q, want none", w)
+	}
+}
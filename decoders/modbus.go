@@ -0,0 +1,160 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package decoders
+
+import (
+	"fmt"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+const (
+	// Modbus RTU frames are separated by at least 3.5 character times of silence.
+	mbFrameGap = 3.5
+	// the shortest frame: address, function and CRC.
+	mbMinFrame = 4
+	// function codes with the highest bit set are exception responses.
+	mbException = 0x80
+)
+
+var mbFunctions = map[byte]string{
+	0x01: "Read Coils",
+	0x02: "Read Discrete Inputs",
+	0x03: "Read Holding Registers",
+	0x04: "Read Input Registers",
+	0x05: "Write Single Coil",
+	0x06: "Write Single Register",
+	0x07: "Read Exception Status",
+	0x08: "Diagnostics",
+	0x0f: "Write Multiple Coils",
+	0x10: "Write Multiple Registers",
+	0x11: "Report Server ID",
+	0x16: "Mask Write Register",
+	0x17: "Read/Write Multiple Registers",
+}
+
+// Modbus decodes Modbus RTU frames from the bytes annotated by a serial line
+// decoder, e.g. UART. Frames are delimited by periods of silence on the line.
+type Modbus struct {
+	frame []Annotation
+}
+
+// NewModbus returns a new Modbus RTU decoder.
+func NewModbus() *Modbus {
+	return &Modbus{}
+}
+
+// Name returns the name of the decoder.
+func (*Modbus) Name() string { return "Modbus" }
+
+// Reset prepares the decoder for a new capture.
+func (m *Modbus) Reset() {
+	m.frame = m.frame[:0]
+}
+
+// DecodeAnnotations processes the bytes decoded by the lower decoder.
+// A frame is decoded when the silence following it is detected, i.e. when
+// the first byte of the next frame arrives.
+func (m *Modbus) DecodeAnnotations(anns []Annotation) []Annotation {
+	var ret []Annotation
+	for _, a := range anns {
+		if a.Row != RowBytes {
+			continue
+		}
+		if _, ok := a.Value.(byte); !ok {
+			continue
+		}
+		if n := len(m.frame); n > 0 {
+			last := m.frame[n-1]
+			charTime := float64(last.End - last.Start)
+			if float64(a.Start-last.End) >= mbFrameGap*charTime {
+				ret = append(ret, m.decodeFrame()...)
+			}
+		}
+		m.frame = append(m.frame, a)
+	}
+	return ret
+}
+
+// Flush decodes the last frame of the capture.
+func (m *Modbus) Flush() []Annotation {
+	return m.decodeFrame()
+}
+
+func (m *Modbus) ann(row string, start, end scope.Duration, v interface{}, format string, args ...interface{}) Annotation {
+	return Annotation{
+		Decoder: m.Name(),
+		Row:     row,
+		Start:   start,
+		End:     end,
+		Label:   fmt.Sprintf(format, args...),
+		Value:   v,
+	}
+}
+
+// decodeFrame decodes the bytes collected in m.frame and clears it.
+func (m *Modbus) decodeFrame() []Annotation {
+	frame := m.frame
+	m.frame = m.frame[:0]
+	if len(frame) == 0 {
+		return nil
+	}
+	start, end := frame[0].Start, frame[len(frame)-1].End
+	if len(frame) < mbMinFrame {
+		return []Annotation{m.ann(RowWarnings, start, end, nil, "frame too short, %d bytes", len(frame))}
+	}
+	b := make([]byte, len(frame))
+	for i, a := range frame {
+		b[i] = a.Value.(byte)
+	}
+	var ret []Annotation
+	ret = append(ret, m.ann(RowProtocol, frame[0].Start, frame[0].End, b[0], "address %d", b[0]))
+	fn := b[1]
+	name, ok := mbFunctions[fn&^mbException]
+	if !ok {
+		name = fmt.Sprintf("function 0x%02x", fn&^mbException)
+	}
+	if fn&mbException != 0 {
+		name = fmt.Sprintf("exception response to %s", name)
+	}
+	ret = append(ret, m.ann(RowProtocol, frame[1].Start, frame[1].End, fn, "%s", name))
+	n := len(b)
+	if data := b[2 : n-2]; len(data) > 0 {
+		ret = append(ret, m.ann(RowProtocol, frame[2].Start, frame[n-3].End, data, "data % x", data))
+	}
+	got := uint16(b[n-2]) | uint16(b[n-1])<<8
+	label := "CRC ok"
+	if want := crc16(b[:n-2]); got != want {
+		label = "CRC error"
+		ret = append(ret, m.ann(RowWarnings, frame[n-2].Start, end, got, "CRC mismatch: got 0x%04x, want 0x%04x", got, want))
+	}
+	return append(ret, m.ann(RowProtocol, frame[n-2].Start, end, got, "%s", label))
+}
+
+// crc16 computes the Modbus CRC (polynomial 0x8005, reflected, initial value 0xffff).
+func crc16(data []byte) uint16 {
+	crc := uint16(0xffff)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xa001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,264 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package decoders
+
+import (
+	"fmt"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// 1-Wire timings at standard speed. Reset pulse is specified as at least
+// 480µs, the decoder accepts slightly shorter pulses.
+const (
+	owResetMin    = 400 * scope.Microsecond
+	owPresenceMax = 75 * scope.Microsecond
+	owSampleAt    = 15 * scope.Microsecond
+	owSlot        = 60 * scope.Microsecond
+)
+
+// 1-Wire ROM commands.
+const (
+	owReadROM         = 0x33
+	owMatchROM        = 0x55
+	owSearchROM       = 0xf0
+	owAlarmSearch     = 0xec
+	owSkipROM         = 0xcc
+	owOverdriveSkip   = 0x3c
+	owOverdriveMatch  = 0x69
+	owROMLen          = 8
+	owCRCPolyReversed = 0x8c
+)
+
+var owROMCommands = map[byte]string{
+	owReadROM:        "READ ROM",
+	owMatchROM:       "MATCH ROM",
+	owSearchROM:      "SEARCH ROM",
+	owAlarmSearch:    "ALARM SEARCH",
+	owSkipROM:        "SKIP ROM",
+	owOverdriveSkip:  "OVERDRIVE SKIP ROM",
+	owOverdriveMatch: "OVERDRIVE MATCH ROM",
+}
+
+type owState int
+
+const (
+	// waiting for the first reset
+	owIdle owState = iota
+	// reset seen, next byte is a ROM command
+	owROMCommand
+	// collecting the ROM code following READ ROM or MATCH ROM
+	owROMCode
+	// search algorithm in progress, bits are not grouped into bytes
+	owSearch
+	// device selected, bytes are function commands and data
+	owData
+)
+
+// OneWire decodes the Dallas/Maxim 1-Wire bus at standard speed: reset and
+// presence pulses, ROM commands and codes, and the data bytes that follow.
+// Overdrive speed is not supported.
+type OneWire struct {
+	edges    edgeDetector
+	interval scope.Duration
+
+	fall     int
+	lastRise int
+	// afterReset is set after the reset pulse, until the presence pulse
+	// or the first time slot.
+	afterReset bool
+
+	state    owState
+	bits     int
+	cur      byte
+	curStart int
+	rom      []byte
+	romStart int
+}
+
+// NewOneWire returns a 1-Wire decoder for the bus sampled on channel ch.
+func NewOneWire(ch scope.ChanID, thr Threshold) *OneWire {
+	return &OneWire{
+		edges: edgeDetector{ch: ch, thr: thr},
+		fall:  -1,
+	}
+}
+
+// Name returns the name of the decoder.
+func (*OneWire) Name() string { return "1-Wire" }
+
+// Reset prepares the decoder for a new capture.
+func (o *OneWire) Reset(interval scope.Duration) {
+	o.edges.reset()
+	o.interval = interval
+	o.fall = -1
+	o.lastRise = 0
+	o.afterReset = false
+	o.state = owIdle
+	o.bits = 0
+}
+
+// Decode processes a chunk of samples.
+func (o *OneWire) Decode(data []scope.ChannelData) []Annotation {
+	var ret []Annotation
+	o.edges.process(data, func(pos int, high bool) {
+		if !high {
+			o.fall = pos
+			return
+		}
+		if o.fall < 0 {
+			// rising edge without a preceding falling edge,
+			// the capture started with the line pulled low.
+			return
+		}
+		ret = o.lowPulse(ret, o.fall, pos)
+		o.lastRise = pos
+		o.fall = -1
+	})
+	return ret
+}
+
+func (o *OneWire) ann(row string, start, end int, v interface{}, format string, args ...interface{}) Annotation {
+	return Annotation{
+		Decoder: o.Name(),
+		Row:     row,
+		Start:   at(float64(start), o.interval),
+		End:     at(float64(end), o.interval),
+		Label:   fmt.Sprintf(format, args...),
+		Value:   v,
+	}
+}
+
+func (o *OneWire) dur(samples int) scope.Duration {
+	return scope.Duration(samples) * o.interval
+}
+
+// lowPulse interprets a single low pulse on the bus.
+func (o *OneWire) lowPulse(ret []Annotation, fall, rise int) []Annotation {
+	low := o.dur(rise - fall)
+	switch {
+	case low >= owResetMin:
+		if o.bits > 0 && o.state != owSearch {
+			ret = append(ret, o.ann(RowWarnings, o.curStart, fall, nil, "incomplete byte, %d bits", o.bits))
+		}
+		o.afterReset = true
+		o.state = owROMCommand
+		o.bits = 0
+		return append(ret, o.ann(RowProtocol, fall, rise, nil, "RESET"))
+	case o.afterReset && o.dur(fall-o.lastRise) <= owPresenceMax:
+		o.afterReset = false
+		return append(ret, o.ann(RowProtocol, fall, rise, nil, "PRESENCE"))
+	}
+	if o.afterReset {
+		o.afterReset = false
+		ret = append(ret, o.ann(RowWarnings, o.lastRise, fall, nil, "no presence pulse"))
+	}
+	if o.state == owIdle {
+		// time slots before the first reset can't be interpreted.
+		return ret
+	}
+	// The master samples the line 15µs after the start of the slot.
+	// A line still pulled low means 0.
+	var bit byte = 1
+	if low > owSampleAt {
+		bit = 0
+	}
+	end := fall + int(owSlot/o.interval)
+	if end < rise {
+		end = rise
+	}
+	ret = append(ret, o.ann(RowBits, fall, end, bit, "%d", bit))
+	if o.state == owSearch {
+		return ret
+	}
+	if o.bits == 0 {
+		o.curStart = fall
+		o.cur = 0
+	}
+	// bytes are transmitted least significant bit first.
+	o.cur |= bit << uint(o.bits)
+	o.bits++
+	if o.bits < 8 {
+		return ret
+	}
+	o.bits = 0
+	ret = append(ret, o.ann(RowBytes, o.curStart, end, o.cur, "0x%02x", o.cur))
+	return o.byteDone(ret, o.cur, o.curStart, end)
+}
+
+// byteDone interprets a complete byte according to the protocol state.
+func (o *OneWire) byteDone(ret []Annotation, b byte, start, end int) []Annotation {
+	switch o.state {
+	case owROMCommand:
+		name, ok := owROMCommands[b]
+		if !ok {
+			o.state = owData
+			return append(ret, o.ann(RowWarnings, start, end, b, "unknown ROM command 0x%02x", b))
+		}
+		switch b {
+		case owReadROM, owMatchROM, owOverdriveMatch:
+			o.state = owROMCode
+			o.rom = o.rom[:0]
+		case owSearchROM, owAlarmSearch:
+			o.state = owSearch
+		default:
+			o.state = owData
+		}
+		return append(ret, o.ann(RowProtocol, start, end, b, "%s", name))
+	case owROMCode:
+		if len(o.rom) == 0 {
+			o.romStart = start
+		}
+		o.rom = append(o.rom, b)
+		if len(o.rom) < owROMLen {
+			return ret
+		}
+		o.state = owData
+		rom := make([]byte, owROMLen)
+		copy(rom, o.rom)
+		ret = append(ret, o.ann(RowProtocol, o.romStart, end, rom, "ROM family 0x%02x serial %x", rom[0], reverse(rom[1:7])))
+		if crc := crc8(rom[:7]); crc != rom[7] {
+			ret = append(ret, o.ann(RowWarnings, o.romStart, end, rom, "ROM CRC mismatch: got 0x%02x, want 0x%02x", rom[7], crc))
+		}
+		return ret
+	}
+	return append(ret, o.ann(RowProtocol, start, end, b, "DATA 0x%02x", b))
+}
+
+// reverse returns a copy of b in reverse order. Serial numbers are sent
+// least significant byte first, but usually printed the other way around.
+func reverse(b []byte) []byte {
+	ret := make([]byte, len(b))
+	for i := range b {
+		ret[len(b)-1-i] = b[i]
+	}
+	return ret
+}
+
+// crc8 computes the Dallas/Maxim CRC (x^8 + x^5 + x^4 + 1) used in ROM codes.
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		for i := 0; i < 8; i++ {
+			mix := (crc ^ b) & 1
+			crc >>= 1
+			if mix != 0 {
+				crc ^= owCRCPolyReversed
+			}
+			b >>= 1
+		}
+	}
+	return crc
+}
@@ -0,0 +1,224 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package decoders
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+const testChan = "data"
+
+// segment is a period of constant signal level.
+type segment struct {
+	high bool
+	len  int
+}
+
+// waveform generates samples from the segments, with 0V as low and 3.3V
+// as high level.
+func waveform(segs []segment) []scope.Voltage {
+	var ret []scope.Voltage
+	for _, s := range segs {
+		v := scope.Voltage(0)
+		if s.high {
+			v = 3.3
+		}
+		for i := 0; i < s.len; i++ {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+// decodeChunks runs the decoder on samples split into chunks of chunkLen.
+func decodeChunks(d Decoder, interval scope.Duration, samples []scope.Voltage, chunkLen int) []Annotation {
+	d.Reset(interval)
+	var ret []Annotation
+	for len(samples) > 0 {
+		n := chunkLen
+		if n > len(samples) {
+			n = len(samples)
+		}
+		ret = append(ret, d.Decode([]scope.ChannelData{
+			{ID: "other", Samples: make([]scope.Voltage, n)},
+			{ID: testChan, Samples: samples[:n]},
+		})...)
+		samples = samples[n:]
+	}
+	return ret
+}
+
+func labels(anns []Annotation, row string) []string {
+	var ret []string
+	for _, a := range anns {
+		if a.Row == row {
+			ret = append(ret, a.Label)
+		}
+	}
+	return ret
+}
+
+// oneWireBus generates 1-Wire bus activity sampled every 1µs.
+type oneWireBus []segment
+
+func (b *oneWireBus) idle(n int) {
+	*b = append(*b, segment{true, n})
+}
+
+func (b *oneWireBus) reset(presence bool) {
+	*b = append(*b, segment{false, 500}, segment{true, 30})
+	if presence {
+		*b = append(*b, segment{false, 120}, segment{true, 350})
+		return
+	}
+	*b = append(*b, segment{true, 470})
+}
+
+func (b *oneWireBus) write(data ...byte) {
+	for _, d := range data {
+		for i := uint(0); i < 8; i++ {
+			if d&(1<<i) != 0 {
+				*b = append(*b, segment{false, 6}, segment{true, 64})
+			} else {
+				*b = append(*b, segment{false, 60}, segment{true, 10})
+			}
+		}
+	}
+}
+
+func TestCRC8(t *testing.T) {
+	// example from Maxim application note 27.
+	rom := []byte{0x02, 0x1c, 0xb8, 0x01, 0x00, 0x00, 0x00}
+	if got, want := crc8(rom), byte(0xa2); got != want {
+		t.Errorf("crc8(%x): got 0x%02x, want 0x%02x", rom, got, want)
+	}
+}
+
+func TestOneWire(t *testing.T) {
+	for _, tc := range []struct {
+		desc         string
+		bus          func(*oneWireBus)
+		wantProtocol []string
+		wantWarnings []string
+	}{
+		{
+			desc: "read ROM",
+			bus: func(b *oneWireBus) {
+				b.idle(100)
+				b.reset(true)
+				b.write(0x33, 0x02, 0x1c, 0xb8, 0x01, 0x00, 0x00, 0x00, 0xa2)
+				b.idle(100)
+			},
+			wantProtocol: []string{"RESET", "PRESENCE", "READ ROM", "ROM family 0x02 serial 00000001b81c"},
+		},
+		{
+			desc: "skip ROM, convert T, bad CRC on the next read",
+			bus: func(b *oneWireBus) {
+				b.idle(100)
+				b.reset(true)
+				b.write(0xcc, 0x44)
+				b.idle(1000)
+				b.reset(true)
+				b.write(0x33, 0x02, 0x1c, 0xb8, 0x01, 0x00, 0x00, 0x00, 0xa3)
+			},
+			wantProtocol: []string{"RESET", "PRESENCE", "SKIP ROM", "DATA 0x44", "RESET", "PRESENCE", "READ ROM", "ROM family 0x02 serial 00000001b81c"},
+			wantWarnings: []string{"ROM CRC mismatch: got 0xa3, want 0xa2"},
+		},
+		{
+			desc: "no presence, unknown command",
+			bus: func(b *oneWireBus) {
+				b.idle(100)
+				b.reset(false)
+				b.write(0x12)
+			},
+			wantProtocol: []string{"RESET"},
+			wantWarnings: []string{"no presence pulse", "unknown ROM command 0x12"},
+		},
+		{
+			desc: "search ROM, bits not grouped",
+			bus: func(b *oneWireBus) {
+				b.idle(100)
+				b.reset(true)
+				b.write(0xf0, 0x00, 0xff)
+				b.reset(true)
+			},
+			wantProtocol: []string{"RESET", "PRESENCE", "SEARCH ROM", "RESET", "PRESENCE"},
+		},
+		{
+			desc: "slots before the first reset are ignored",
+			bus: func(b *oneWireBus) {
+				b.idle(100)
+				b.write(0xaa)
+				b.reset(true)
+				b.write(0xcc)
+			},
+			wantProtocol: []string{"RESET", "PRESENCE", "SKIP ROM"},
+		},
+	} {
+		bus := &oneWireBus{}
+		tc.bus(bus)
+		samples := waveform(*bus)
+		for _, chunk := range []int{len(samples), 1000, 37} {
+			got := decodeChunks(NewOneWire(testChan, Threshold{Level: 1.5, Hysteresis: 0.5}), scope.Microsecond, samples, chunk)
+			if got, want := labels(got, RowProtocol), tc.wantProtocol; !reflect.DeepEqual(got, want) {
+				t.Errorf("%s, chunks of %d samples: protocol annotations: got %q, want %q", tc.desc, chunk, got, want)
+			}
+			if got, want := labels(got, RowWarnings), tc.wantWarnings; !reflect.DeepEqual(got, want) {
+				t.Errorf("%s, chunks of %d samples: warnings: got %q, want %q", tc.desc, chunk, got, want)
+			}
+		}
+	}
+}
+
+func TestOneWireTimestamps(t *testing.T) {
+	bus := &oneWireBus{}
+	bus.idle(100)
+	bus.reset(true)
+	bus.write(0xcc)
+	got := decodeChunks(NewOneWire(testChan, Threshold{Level: 1.5}), scope.Microsecond, waveform(*bus), 100)
+	want := []Annotation{
+		{Decoder: "1-Wire", Row: RowProtocol, Start: 100 * scope.Microsecond, End: 600 * scope.Microsecond, Label: "RESET"},
+		{Decoder: "1-Wire", Row: RowProtocol, Start: 630 * scope.Microsecond, End: 750 * scope.Microsecond, Label: "PRESENCE"},
+	}
+	if !reflect.DeepEqual(got[:2], want) {
+		t.Errorf("reset annotations: got %v, want %v", got[:2], want)
+	}
+	bytesRow := labels(got, RowBytes)
+	if want := []string{"0xcc"}; !reflect.DeepEqual(bytesRow, want) {
+		t.Errorf("bytes: got %q, want %q", bytesRow, want)
+	}
+	if bits := labels(got, RowBits); strings.Join(bits, "") != "00110011" {
+		t.Errorf("bits: got %q, want LSB first bits of 0xcc", bits)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, got[:1]); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got, want := buf.String(), "100µs\t600µs\t1-Wire\tprotocol\tRESET\n"; got != want {
+		t.Errorf("Write: got %q, want %q", got, want)
+	}
+}
+
+func TestNewOneWire(t *testing.T) {
+	// the decoder starts without a pending falling edge, same as after Reset.
+	if got := NewOneWire(testChan, Threshold{Level: 2.5}).fall; got != -1 {
+		t.Errorf("NewOneWire().fall: got %d, want -1", got)
+	}
+}
@@ -0,0 +1,75 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package decoders
+
+import (
+	"strings"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+type stack struct {
+	base  Decoder
+	upper []AnnotationDecoder
+}
+
+// Stack returns a decoder running base on the samples and passing the
+// annotations produced by every decoder to the next one in upper.
+// The returned decoder produces the annotations of all decoders in the stack.
+func Stack(base Decoder, upper ...AnnotationDecoder) Decoder {
+	return &stack{base: base, upper: upper}
+}
+
+func (s *stack) Name() string {
+	names := []string{s.base.Name()}
+	for _, u := range s.upper {
+		names = append(names, u.Name())
+	}
+	return strings.Join(names, "/")
+}
+
+func (s *stack) Reset(interval scope.Duration) {
+	s.base.Reset(interval)
+	for _, u := range s.upper {
+		u.Reset()
+	}
+}
+
+func (s *stack) Decode(data []scope.ChannelData) []Annotation {
+	return s.propagate(s.base.Decode(data), false)
+}
+
+// Flush flushes the decoders from the bottom of the stack, so that
+// the data flushed from one decoder is processed by the next.
+func (s *stack) Flush() []Annotation {
+	var anns []Annotation
+	if f, ok := s.base.(Flusher); ok {
+		anns = f.Flush()
+	}
+	return s.propagate(anns, true)
+}
+
+// propagate passes anns up the stack.
+func (s *stack) propagate(anns []Annotation, flush bool) []Annotation {
+	all := anns
+	for _, u := range s.upper {
+		anns = u.DecodeAnnotations(anns)
+		if f, ok := u.(Flusher); ok && flush {
+			anns = append(anns, f.Flush()...)
+		}
+		all = append(all, anns...)
+	}
+	return all
+}
@@ -0,0 +1,195 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package decoders
+
+import (
+	"fmt"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Parity represents the parity bit setting of a serial line.
+type Parity int
+
+const (
+	// ParityNone means no parity bit.
+	ParityNone Parity = iota
+	// ParityEven means the number of ones in data and parity bits is even.
+	ParityEven
+	// ParityOdd means the number of ones in data and parity bits is odd.
+	ParityOdd
+)
+
+// UARTConfig holds the serial line parameters.
+type UARTConfig struct {
+	// Channel is the channel carrying the signal.
+	Channel scope.ChanID
+	// Threshold is the logic level threshold.
+	Threshold Threshold
+	// BaudRate is the number of bits per second.
+	BaudRate float64
+	// DataBits is the number of data bits in a frame, 8 if not set.
+	DataBits int
+	// Parity is the parity bit setting.
+	Parity Parity
+	// Invert should be set for lines with low idle level, e.g. RS-232
+	// levels sampled before the line driver.
+	Invert bool
+}
+
+// UART decodes asynchronous serial data, sent least significant bit first,
+// with one start bit and at least one stop bit. The decoded frames are
+// annotated as bytes, regardless of the number of data bits.
+type UART struct {
+	cfg      UARTConfig
+	edges    edgeDetector
+	interval scope.Duration
+	// bitLen is the length of a single bit, in samples.
+	bitLen float64
+
+	inFrame bool
+	// waitIdle is set after a framing error, until the line is idle again.
+	waitIdle bool
+	start    int
+	// bit is the index of the next bit to sample, 0 is the start bit.
+	bit     int
+	next    int
+	data    uint
+	parity  bool
+	errors  []string
+	bitAnns []Annotation
+}
+
+// NewUART returns a decoder for a serial line with parameters c.
+func NewUART(c UARTConfig) *UART {
+	if c.DataBits == 0 {
+		c.DataBits = 8
+	}
+	return &UART{
+		cfg:   c,
+		edges: edgeDetector{ch: c.Channel, thr: c.Threshold},
+	}
+}
+
+// Name returns the name of the decoder.
+func (*UART) Name() string { return "UART" }
+
+// Reset prepares the decoder for a new capture.
+func (u *UART) Reset(interval scope.Duration) {
+	u.edges.reset()
+	u.interval = interval
+	u.bitLen = float64(scope.Second) / u.cfg.BaudRate / float64(interval)
+	u.inFrame = false
+	u.waitIdle = true
+}
+
+func (u *UART) frameBits() int {
+	n := 1 + u.cfg.DataBits + 1
+	if u.cfg.Parity != ParityNone {
+		n++
+	}
+	return n
+}
+
+// samplePos returns the position of the middle of bit idx in the frame.
+func (u *UART) samplePos(idx int) int {
+	return u.start + int((float64(idx)+0.5)*u.bitLen)
+}
+
+func (u *UART) ann(row string, start, end float64, v interface{}, label string) Annotation {
+	return Annotation{
+		Decoder: u.Name(),
+		Row:     row,
+		Start:   at(start, u.interval),
+		End:     at(end, u.interval),
+		Label:   label,
+		Value:   v,
+	}
+}
+
+// Decode processes a chunk of samples.
+func (u *UART) Decode(data []scope.ChannelData) []Annotation {
+	var ret []Annotation
+	u.edges.each(data, func(pos int, high, changed bool) {
+		mark := high != u.cfg.Invert
+		if !u.inFrame {
+			switch {
+			case mark:
+				u.waitIdle = false
+			case !u.waitIdle && changed:
+				// start bit
+				u.inFrame = true
+				u.start = pos
+				u.bit = 0
+				u.next = u.samplePos(0)
+				u.data = 0
+				u.parity = false
+				u.errors = u.errors[:0]
+				u.bitAnns = u.bitAnns[:0]
+			}
+			return
+		}
+		if pos != u.next {
+			return
+		}
+		ret = u.sampleBit(ret, mark)
+	})
+	return ret
+}
+
+// sampleBit interprets the value of the next bit in the frame.
+func (u *UART) sampleBit(ret []Annotation, mark bool) []Annotation {
+	idx := u.bit
+	u.bit++
+	u.next = u.samplePos(u.bit)
+	bitStart := float64(u.start) + float64(idx)*u.bitLen
+	var v byte
+	if mark {
+		v = 1
+	}
+	parityIdx := 1 + u.cfg.DataBits
+	stopIdx := u.frameBits() - 1
+	switch {
+	case idx == 0:
+		// a start bit still at mark level was a glitch.
+		u.inFrame = !mark
+		return ret
+	case idx <= u.cfg.DataBits:
+		u.data |= uint(v) << uint(idx-1)
+		u.parity = u.parity != mark
+		u.bitAnns = append(u.bitAnns, u.ann(RowBits, bitStart, bitStart+u.bitLen, v, fmt.Sprintf("%d", v)))
+		return ret
+	case idx == parityIdx && idx != stopIdx:
+		u.parity = u.parity != mark
+		if want := u.cfg.Parity == ParityOdd; u.parity != want {
+			u.errors = append(u.errors, "parity error")
+		}
+		return ret
+	}
+	// stop bit
+	if !mark {
+		u.errors = append(u.errors, "framing error")
+		u.waitIdle = true
+	}
+	u.inFrame = false
+	end := bitStart + u.bitLen
+	ret = append(ret, u.bitAnns...)
+	b := byte(u.data)
+	ret = append(ret, u.ann(RowBytes, float64(u.start), end, b, fmt.Sprintf("0x%02x", u.data)))
+	for _, e := range u.errors {
+		ret = append(ret, u.ann(RowWarnings, float64(u.start), end, b, e))
+	}
+	return ret
+}
@@ -0,0 +1,138 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package decoders
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// serialLine generates UART frames, with bitLen samples per bit.
+type serialLine struct {
+	segs   []segment
+	bitLen int
+	parity Parity
+}
+
+func (s *serialLine) idle(bits int) {
+	s.segs = append(s.segs, segment{true, bits * s.bitLen})
+}
+
+func (s *serialLine) send(data ...byte) {
+	for _, d := range data {
+		s.segs = append(s.segs, segment{false, s.bitLen})
+		ones := 0
+		for i := uint(0); i < 8; i++ {
+			bit := d&(1<<i) != 0
+			if bit {
+				ones++
+			}
+			s.segs = append(s.segs, segment{bit, s.bitLen})
+		}
+		switch s.parity {
+		case ParityEven:
+			s.segs = append(s.segs, segment{ones%2 == 1, s.bitLen})
+		case ParityOdd:
+			s.segs = append(s.segs, segment{ones%2 == 0, s.bitLen})
+		}
+		s.segs = append(s.segs, segment{true, s.bitLen})
+	}
+}
+
+func TestUART(t *testing.T) {
+	for _, tc := range []struct {
+		desc         string
+		line         func(*serialLine)
+		cfgParity    Parity
+		wantBytes    []string
+		wantWarnings []string
+	}{
+		{
+			desc: "back to back bytes",
+			line: func(s *serialLine) {
+				s.idle(3)
+				s.send('h', 'e', 'l', 'l', 'o')
+				s.idle(3)
+			},
+			wantBytes: []string{"0x68", "0x65", "0x6c", "0x6c", "0x6f"},
+		},
+		{
+			desc: "even parity",
+			line: func(s *serialLine) {
+				s.parity = ParityEven
+				s.idle(3)
+				s.send(0x00, 0x01, 0xff)
+				s.idle(3)
+			},
+			cfgParity: ParityEven,
+			wantBytes: []string{"0x00", "0x01", "0xff"},
+		},
+		{
+			desc: "odd parity expected, even sent",
+			line: func(s *serialLine) {
+				s.parity = ParityEven
+				s.idle(3)
+				s.send(0x01, 0x03)
+				s.idle(3)
+			},
+			cfgParity:    ParityOdd,
+			wantBytes:    []string{"0x01", "0x03"},
+			wantWarnings: []string{"parity error", "parity error"},
+		},
+		{
+			desc: "break condition, framing error",
+			line: func(s *serialLine) {
+				s.idle(3)
+				s.segs = append(s.segs, segment{false, 20 * s.bitLen})
+				s.idle(3)
+				s.send(0x55)
+				s.idle(1)
+			},
+			wantBytes:    []string{"0x00", "0x55"},
+			wantWarnings: []string{"framing error"},
+		},
+		{
+			desc: "capture starting in the middle of a frame",
+			line: func(s *serialLine) {
+				s.segs = append(s.segs, segment{false, 4 * s.bitLen})
+				s.idle(5)
+				s.send(0xa5)
+				s.idle(1)
+			},
+			wantBytes: []string{"0xa5"},
+		},
+	} {
+		line := &serialLine{bitLen: 10}
+		tc.line(line)
+		samples := waveform(line.segs)
+		for _, chunk := range []int{len(samples), 33} {
+			dec := NewUART(UARTConfig{
+				Channel:   testChan,
+				Threshold: Threshold{Level: 1.5},
+				BaudRate:  100e3,
+				Parity:    tc.cfgParity,
+			})
+			got := decodeChunks(dec, scope.Microsecond, samples, chunk)
+			if got, want := labels(got, RowBytes), tc.wantBytes; !reflect.DeepEqual(got, want) {
+				t.Errorf("%s, chunks of %d: bytes: got %q, want %q", tc.desc, chunk, got, want)
+			}
+			if got, want := labels(got, RowWarnings), tc.wantWarnings; !reflect.DeepEqual(got, want) {
+				t.Errorf("%s, chunks of %d: warnings: got %q, want %q", tc.desc, chunk, got, want)
+			}
+		}
+	}
+}
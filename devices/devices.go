@@ -0,0 +1,91 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package devices finds and opens the devices of all supported systems,
+// the dummy device and the USB oscilloscopes. The devices are identified
+// as system:id, e.g. "dummy:" or "usb:1:5".
+package devices
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/zagrodzki/goscope/dummy"
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/usb"
+)
+
+type system struct {
+	name      string
+	enumerate func() map[string]string
+	open      func(string) (scope.Device, error)
+}
+
+var systems = []system{
+	{
+		name:      "dummy",
+		enumerate: dummy.Enumerate,
+		open:      dummy.Open,
+	},
+	{
+		name:      "usb",
+		enumerate: usb.Enumerate,
+		open:      usb.Open,
+	},
+}
+
+// List returns the IDs of all detected devices.
+func List() []string {
+	var all []string
+	for _, sys := range systems {
+		for id := range sys.enumerate() {
+			all = append(all, fmt.Sprintf("%s:%s", sys.name, id))
+		}
+	}
+	return all
+}
+
+// Open opens the detected device with given ID. If id is empty,
+// the first detected device is opened.
+func Open(id string) (scope.Device, error) {
+	all := List()
+	if len(all) == 0 {
+		return nil, fmt.Errorf("did not find any supported devices")
+	}
+	if id == "" {
+		id = all[0]
+		if len(all) > 1 {
+			log.Printf("Multiple devices found: %v", all)
+			log.Printf("Using the first device (%s)", id)
+		}
+	}
+	found := false
+	for _, d := range all {
+		if d == id {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("device %s not detected on the list. Available devices: %v", id, all)
+	}
+	parts := strings.SplitN(id, ":", 2)
+	for _, sys := range systems {
+		if sys.name == parts[0] {
+			return sys.open(parts[1])
+		}
+	}
+	return nil, fmt.Errorf("device %s: unknown system %s", id, parts[0])
+}
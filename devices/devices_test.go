@@ -0,0 +1,30 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package devices
+
+import "testing"
+
+func TestOpen(t *testing.T) {
+	dev, err := Open("dummy:")
+	if err != nil {
+		t.Fatalf("Open(dummy:): %v", err)
+	}
+	if got, want := dev.String(), "dummy device"; got != want {
+		t.Errorf("Open(dummy:): got %q, want %q", got, want)
+	}
+	if _, err := Open("dummy:nonexistent"); err == nil {
+		t.Errorf("Open(dummy:nonexistent): got nil error, want not detected")
+	}
+}
@@ -42,7 +42,10 @@ func (d *dum) Attach(rec scope.DataRecorder) {
 }
 
 func (d *dum) Start() {
-	d.stop = make(chan struct{}, 1)
+	// stop is captured by the goroutine, so that a Start following
+	// a Stop doesn't steal the stop signal of the previous run.
+	stop := make(chan struct{}, 1)
+	d.stop = stop
 	ch := make(chan []scope.ChannelData)
 	d.r.Reset(scope.Millisecond, ch)
 	go func() {
@@ -57,7 +60,7 @@ func (d *dum) Start() {
 			}
 			select {
 			case ch <- dat:
-			case <-d.stop:
+			case <-stop:
 				close(ch)
 				return
 			}
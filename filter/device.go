@@ -0,0 +1,121 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package filter
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Device filters the samples of the channels of the underlying device.
+// Device implements both scope.Device interface (used by UI)
+// and scope.DataRecorder interface (used by underlying device).
+type Device struct {
+	scope.Device
+	rec scope.DataRecorder
+
+	mu      sync.Mutex
+	filters map[scope.ChanID]Filter
+	// done is closed when the run started by the last Reset finishes.
+	done chan struct{}
+}
+
+// New returns a Device passing the data of dev unchanged,
+// until filters are set.
+func New(dev scope.Device) *Device {
+	return &Device{
+		Device:  dev,
+		filters: make(map[scope.ChanID]Filter),
+	}
+}
+
+// Set sets filter f for channel ch, or removes the filter if f is nil.
+// The new filter is used from the next Reset of the recording.
+func (d *Device) Set(ch scope.ChanID, f Filter) error {
+	found := false
+	for _, c := range d.Channels() {
+		found = found || c == ch
+	}
+	if !found {
+		return fmt.Errorf("device does not have a channel %s, available channels: %v", ch, d.Channels())
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if f == nil {
+		delete(d.filters, ch)
+	} else {
+		d.filters[ch] = f
+	}
+	return nil
+}
+
+// Attach configures the device to pass the data to rec.
+func (d *Device) Attach(rec scope.DataRecorder) {
+	d.rec = rec
+	d.Device.Attach(d)
+}
+
+// TimeBase returns the timebase of the underlying recorder.
+func (d *Device) TimeBase() scope.Duration {
+	return d.rec.TimeBase()
+}
+
+// Reset initializes the recording, clearing the state of the filters.
+// Reset waits for the previous recording to finish, i.e. for the device
+// to close its data channel.
+func (d *Device) Reset(i scope.Duration, ch <-chan []scope.ChannelData) {
+	if d.done != nil {
+		// the filters are used by the previous run until it finishes.
+		<-d.done
+	}
+	d.mu.Lock()
+	filters := make(map[scope.ChanID]Filter, len(d.filters))
+	for id, f := range d.filters {
+		f.Reset(i)
+		filters[id] = f
+	}
+	d.mu.Unlock()
+	if len(filters) == 0 {
+		d.rec.Reset(i, ch)
+		return
+	}
+	out := make(chan []scope.ChannelData, 2)
+	d.rec.Reset(i, out)
+	done := make(chan struct{})
+	d.done = done
+	go d.run(filters, ch, out, done)
+}
+
+// Error passes the error down to the underlying recorder.
+func (d *Device) Error(err error) {
+	d.rec.Error(err)
+}
+
+func (d *Device) run(filters map[scope.ChanID]Filter, in <-chan []scope.ChannelData, out chan<- []scope.ChannelData, done chan<- struct{}) {
+	for data := range in {
+		ret := make([]scope.ChannelData, len(data))
+		for i, c := range data {
+			ret[i] = c
+			if f, ok := filters[c.ID]; ok {
+				ret[i].Samples = f.Process(c.Samples)
+			}
+		}
+		out <- ret
+	}
+	close(out)
+	close(done)
+}
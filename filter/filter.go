@@ -0,0 +1,79 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package filter implements digital filters for the sampled signals:
+// FIR filters designed with the windowed-sinc method and IIR Butterworth
+// filters built from biquad sections. Filters keep their state between
+// chunks of samples, so a continuous stream can be filtered chunk by chunk.
+package filter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Filter processes a stream of samples of a single channel.
+type Filter interface {
+	// Reset clears the state of the filter and prepares it for
+	// samples taken every interval.
+	Reset(interval scope.Duration)
+
+	// Process returns the filtered samples. The input is not modified.
+	Process([]scope.Voltage) []scope.Voltage
+}
+
+// sampleRate returns the sampling frequency in Hz for samples
+// taken every interval.
+func sampleRate(interval scope.Duration) float64 {
+	return float64(scope.Second) / float64(interval)
+}
+
+// BandwidthLimit returns a filter limiting the bandwidth of the signal to
+// cutoff Hz, a second order Butterworth low pass filter, like the analog
+// bandwidth limit found in oscilloscopes. If cutoff is above the Nyquist
+// frequency, the signal is passed unchanged.
+func BandwidthLimit(cutoff float64) Filter {
+	return LowPass(cutoff, 2)
+}
+
+var freqPrefixes = []struct {
+	sfx string
+	mul float64
+}{
+	{"G", 1e9},
+	{"M", 1e6},
+	{"k", 1e3},
+}
+
+// ParseFrequency parses a frequency in Hz, with an optional unit prefix
+// and an optional "Hz" suffix, e.g. "20MHz", "500k" or "1e6".
+func ParseFrequency(s string) (float64, error) {
+	v := strings.TrimSuffix(s, "Hz")
+	mul := 1.0
+	for _, p := range freqPrefixes {
+		if strings.HasSuffix(v, p.sfx) {
+			v, mul = strings.TrimSuffix(v, p.sfx), p.mul
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f <= 0 || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid frequency %q, want a positive number with an optional unit, e.g. 20MHz", s)
+	}
+	return f * mul, nil
+}
@@ -0,0 +1,244 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package filter
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/zagrodzki/goscope/compat"
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/testutil"
+)
+
+// sampling at 1MHz.
+const testInterval = scope.Microsecond
+
+func sine(freq float64, n int) []scope.Voltage {
+	ret := make([]scope.Voltage, n)
+	for i := range ret {
+		ret[i] = scope.Voltage(math.Sin(2 * math.Pi * freq * float64(i) * 1e-6))
+	}
+	return ret
+}
+
+// gain returns the gain of the filter for a sine wave at freq,
+// measured after the filter settles.
+func gain(f Filter, freq float64) float64 {
+	f.Reset(testInterval)
+	out := f.Process(sine(freq, 20000))
+	var sumSq float64
+	for _, v := range out[10000:] {
+		sumSq += float64(v) * float64(v)
+	}
+	return math.Sqrt(2 * sumSq / 10000)
+}
+
+func TestResponse(t *testing.T) {
+	// gain expected at given frequencies, in dB.
+	type point struct {
+		freq, wantDB float64
+	}
+	for _, tc := range []struct {
+		desc   string
+		f      Filter
+		points []point
+		tolDB  float64
+	}{
+		{
+			desc:   "Butterworth low pass, 2nd order",
+			f:      LowPass(10e3, 2),
+			points: []point{{100, 0}, {10e3, -3.01}, {100e3, -40}},
+			tolDB:  0.5,
+		},
+		{
+			desc:   "Butterworth low pass, 3rd order",
+			f:      LowPass(10e3, 3),
+			points: []point{{100, 0}, {10e3, -3.01}, {100e3, -60}},
+			tolDB:  1,
+		},
+		{
+			desc:   "Butterworth high pass, 4th order",
+			f:      HighPass(10e3, 4),
+			points: []point{{1e3, -80}, {10e3, -3.01}, {100e3, 0}},
+			tolDB:  1,
+		},
+		{
+			desc:   "Butterworth band pass",
+			f:      BandPass(5e3, 50e3, 2),
+			points: []point{{500, -40}, {5e3, -3.01}, {15e3, 0}, {50e3, -3.01}, {400e3, -50}},
+			tolDB:  1.5,
+		},
+		{
+			desc:   "bandwidth limit above Nyquist",
+			f:      BandwidthLimit(20e6),
+			points: []point{{100, 0}, {100e3, 0}, {400e3, 0}},
+			tolDB:  0.01,
+		},
+		{
+			desc:   "FIR low pass",
+			f:      LowPassFIR(50e3, 201),
+			points: []point{{100, 0}, {30e3, 0}, {50e3, -6}, {100e3, -70}},
+			tolDB:  1,
+		},
+		{
+			desc:   "FIR high pass",
+			f:      HighPassFIR(50e3, 200),
+			points: []point{{10e3, -70}, {50e3, -6}, {100e3, 0}},
+			tolDB:  1,
+		},
+		{
+			desc:   "FIR band pass",
+			f:      BandPassFIR(50e3, 150e3, 201),
+			points: []point{{10e3, -70}, {100e3, 0}, {300e3, -70}},
+			tolDB:  1,
+		},
+	} {
+		for _, p := range tc.points {
+			got := 20 * math.Log10(gain(tc.f, p.freq))
+			// stop band attenuation may be better than expected.
+			if p.wantDB < -20 && got < p.wantDB {
+				continue
+			}
+			if math.Abs(got-p.wantDB) > tc.tolDB {
+				t.Errorf("%s: gain at %gHz: got %.2fdB, want %.2fdB", tc.desc, p.freq, got, p.wantDB)
+			}
+		}
+	}
+}
+
+func TestChunks(t *testing.T) {
+	in := sine(30e3, 1000)
+	for i := range in {
+		// add a step, to excite the filters.
+		if i > 500 {
+			in[i]++
+		}
+	}
+	for _, f := range []Filter{LowPass(10e3, 4), BandPassFIR(10e3, 100e3, 51)} {
+		f.Reset(testInterval)
+		want := f.Process(in)
+		f.Reset(testInterval)
+		var got []scope.Voltage
+		for i := 0; i < len(in); i += 77 {
+			end := i + 77
+			if end > len(in) {
+				end = len(in)
+			}
+			got = append(got, f.Process(in[i:end])...)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%T: output processed in chunks differs from the output processed at once", f)
+		}
+	}
+}
+
+func TestParseFrequency(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"20MHz", 20e6, false},
+		{"500k", 500e3, false},
+		{"1e6", 1e6, false},
+		{"1.5GHz", 1.5e9, false},
+		{"100Hz", 100, false},
+		{"", 0, true},
+		{"-1MHz", 0, true},
+		{"fast", 0, true},
+	} {
+		got, err := ParseFrequency(tc.in)
+		if gotErr := err != nil; gotErr != tc.wantErr || got != tc.want {
+			t.Errorf("ParseFrequency(%q): got %v, %v, want %v, error: %v", tc.in, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+type fakeDev struct {
+	rec  scope.DataRecorder
+	data [][]scope.ChannelData
+}
+
+func (*fakeDev) String() string                  { return "fake" }
+func (*fakeDev) Channels() []scope.ChanID        { return []scope.ChanID{"a", "b"} }
+func (d *fakeDev) Attach(rec scope.DataRecorder) { d.rec = rec }
+func (*fakeDev) Stop()                           {}
+func (d *fakeDev) Start() {
+	ch := make(chan []scope.ChannelData)
+	d.rec.Reset(testInterval, ch)
+	go func() {
+		for _, c := range d.data {
+			ch <- c
+		}
+		close(ch)
+	}()
+}
+
+func TestDevice(t *testing.T) {
+	noisy := sine(400e3, 1000)
+	for i := range noisy {
+		noisy[i]++
+	}
+	dev := &fakeDev{data: [][]scope.ChannelData{
+		{{ID: "a", Samples: noisy[:500]}, {ID: "b", Samples: noisy[:500]}},
+		{{ID: "a", Samples: noisy[500:]}, {ID: "b", Samples: noisy[500:]}},
+	}}
+	d := New(dev)
+	if err := d.Set("c", BandwidthLimit(1e3)); err == nil {
+		t.Errorf("Set(unknown channel): no error")
+	}
+	if err := d.Set("a", BandwidthLimit(10e3)); err != nil {
+		t.Fatalf("Set(a): %v", err)
+	}
+	rec := &compat.Recorder{}
+	d.Attach(rec)
+	d.Start()
+	var a, b []scope.Voltage
+	for data := range rec.Data {
+		a = append(a, data.Channels[0].Samples...)
+		b = append(b, data.Channels[1].Samples...)
+	}
+	if !reflect.DeepEqual(b, noisy) {
+		t.Errorf("channel b without a filter was modified")
+	}
+	for i, v := range a[500:] {
+		if math.Abs(float64(v)-1) > 0.01 {
+			t.Errorf("channel a, sample %d: got %v, want 1 with the 400kHz component removed", 500+i, v)
+			break
+		}
+	}
+}
+
+func TestDeviceRestart(t *testing.T) {
+	noisy := sine(400e3, 1000)
+	dev := &fakeDev{data: [][]scope.ChannelData{
+		{{ID: "a", Samples: noisy[:500]}, {ID: "b", Samples: noisy[:500]}},
+		{{ID: "a", Samples: noisy[500:]}, {ID: "b", Samples: noisy[500:]}},
+	}}
+	d := New(dev)
+	if err := d.Set("a", BandwidthLimit(10e3)); err != nil {
+		t.Fatalf("Set(a): %v", err)
+	}
+	rec := testutil.NewDiscardRecorder(scope.Millisecond)
+	d.Attach(rec)
+	// every Start resets the filter, while the previous run
+	// may still be filtering.
+	for i := 0; i < 3; i++ {
+		d.Start()
+	}
+	rec.Wait()
+}
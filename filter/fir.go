@@ -0,0 +1,125 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package filter
+
+import (
+	"math"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// FIR is a finite impulse response filter designed with the windowed-sinc
+// method, using the Blackman window. The output is delayed by half of
+// the filter length.
+type FIR struct {
+	low, high float64
+	taps      int
+
+	coeffs []float64
+	// hist holds the last len(coeffs)-1 input samples.
+	hist []float64
+}
+
+// newFIR returns a FIR band pass filter passing the frequencies between
+// low and high Hz. low of 0 means a low pass filter, high of +Inf
+// a high pass filter.
+func newFIR(low, high float64, taps int) *FIR {
+	if taps%2 == 0 {
+		// odd length, for a symmetric filter with an integer delay.
+		taps++
+	}
+	return &FIR{low: low, high: high, taps: taps}
+}
+
+// LowPassFIR returns a FIR low pass filter with cutoff frequency in Hz,
+// with taps coefficients. More taps give a sharper transition.
+func LowPassFIR(cutoff float64, taps int) *FIR {
+	return newFIR(0, cutoff, taps)
+}
+
+// HighPassFIR returns a FIR high pass filter with cutoff frequency in Hz.
+func HighPassFIR(cutoff float64, taps int) *FIR {
+	return newFIR(cutoff, math.Inf(1), taps)
+}
+
+// BandPassFIR returns a FIR band pass filter passing frequencies
+// between low and high Hz.
+func BandPassFIR(low, high float64, taps int) *FIR {
+	return newFIR(low, high, taps)
+}
+
+// lowPassCoeffs returns the windowed-sinc low pass filter with cutoff fc,
+// relative to the sample rate, normalized to the gain of 1 at DC.
+func lowPassCoeffs(fc float64, taps int) []float64 {
+	ret := make([]float64, taps)
+	if fc >= 0.5 {
+		// cutoff above the Nyquist frequency, pass everything.
+		ret[taps/2] = 1
+		return ret
+	}
+	var sum float64
+	m := float64(taps - 1)
+	for i := range ret {
+		x := float64(i) - m/2
+		h := 2 * fc
+		if x != 0 {
+			h = math.Sin(2*math.Pi*fc*x) / (math.Pi * x)
+		}
+		// Blackman window
+		h *= 0.42 - 0.5*math.Cos(2*math.Pi*float64(i)/m) + 0.08*math.Cos(4*math.Pi*float64(i)/m)
+		ret[i] = h
+		sum += h
+	}
+	for i := range ret {
+		ret[i] /= sum
+	}
+	return ret
+}
+
+// Reset designs the filter for the sample rate and clears its state.
+func (f *FIR) Reset(interval scope.Duration) {
+	fs := sampleRate(interval)
+	// band pass is the difference of two low pass filters,
+	// high pass is all pass minus low pass.
+	f.coeffs = lowPassCoeffs(f.high/fs, f.taps)
+	if f.low > 0 {
+		lp := lowPassCoeffs(f.low/fs, f.taps)
+		for i := range f.coeffs {
+			f.coeffs[i] -= lp[i]
+		}
+	}
+	f.hist = make([]float64, f.taps-1)
+}
+
+// Process returns the filtered samples.
+func (f *FIR) Process(in []scope.Voltage) []scope.Voltage {
+	n := len(f.hist)
+	buf := make([]float64, n+len(in))
+	copy(buf, f.hist)
+	for i, v := range in {
+		buf[n+i] = float64(v)
+	}
+	out := make([]scope.Voltage, len(in))
+	for i := range out {
+		// buf[i+n] is the current sample, coefficients are symmetric.
+		var acc float64
+		for j, c := range f.coeffs {
+			acc += c * buf[i+j]
+		}
+		out[i] = scope.Voltage(acc)
+	}
+	copy(f.hist, buf[len(buf)-n:])
+	return out
+}
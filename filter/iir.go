@@ -0,0 +1,148 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package filter
+
+import (
+	"math"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// biquad is a second order IIR section, in the transposed direct form II.
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	z1, z2             float64
+}
+
+func (b *biquad) process(x float64) float64 {
+	y := b.b0*x + b.z1
+	b.z1 = b.b1*x - b.a1*y + b.z2
+	b.z2 = b.b2*x - b.a2*y
+	return y
+}
+
+// section describes a single section of a Butterworth filter.
+type section struct {
+	highPass bool
+	freq     float64
+	// q is the quality factor of the section, 0 for a first order section.
+	q float64
+}
+
+// design returns the coefficients of the section for sample rate fs,
+// using the bilinear transform. Sections with the cutoff above
+// the Nyquist frequency pass low pass signals unchanged.
+func (s section) design(fs float64) biquad {
+	if s.freq >= fs/2 {
+		if s.highPass {
+			return biquad{}
+		}
+		return biquad{b0: 1}
+	}
+	w0 := 2 * math.Pi * s.freq / fs
+	if s.q == 0 {
+		k := math.Tan(w0 / 2)
+		b := biquad{a1: (k - 1) / (k + 1)}
+		if s.highPass {
+			b.b0 = 1 / (1 + k)
+			b.b1 = -b.b0
+		} else {
+			b.b0 = k / (1 + k)
+			b.b1 = b.b0
+		}
+		return b
+	}
+	cos := math.Cos(w0)
+	alpha := math.Sin(w0) / (2 * s.q)
+	a0 := 1 + alpha
+	b := biquad{
+		a1: -2 * cos / a0,
+		a2: (1 - alpha) / a0,
+	}
+	if s.highPass {
+		b.b0 = (1 + cos) / 2 / a0
+		b.b1 = -(1 + cos) / a0
+	} else {
+		b.b0 = (1 - cos) / 2 / a0
+		b.b1 = (1 - cos) / a0
+	}
+	b.b2 = b.b0
+	return b
+}
+
+// IIR is a Butterworth filter, built from a cascade of biquad sections.
+type IIR struct {
+	sections []section
+	biquads  []biquad
+}
+
+// butterworth returns the sections of a Butterworth filter of order n.
+func butterworth(highPass bool, freq float64, n int) []section {
+	var ret []section
+	if n%2 == 1 {
+		ret = append(ret, section{highPass: highPass, freq: freq})
+	}
+	for k := 1; k <= n/2; k++ {
+		// poles of the normalized Butterworth filter come in conjugate
+		// pairs at angles theta from the negative real axis.
+		theta := float64(2*k-1) * math.Pi / float64(2*n)
+		if n%2 == 1 {
+			theta = float64(k) * math.Pi / float64(n)
+		}
+		ret = append(ret, section{highPass: highPass, freq: freq, q: 1 / (2 * math.Cos(theta))})
+	}
+	return ret
+}
+
+// LowPass returns a Butterworth low pass filter of order n,
+// with cutoff (-3dB) frequency in Hz.
+func LowPass(cutoff float64, n int) *IIR {
+	return &IIR{sections: butterworth(false, cutoff, n)}
+}
+
+// HighPass returns a Butterworth high pass filter of order n,
+// with cutoff (-3dB) frequency in Hz.
+func HighPass(cutoff float64, n int) *IIR {
+	return &IIR{sections: butterworth(true, cutoff, n)}
+}
+
+// BandPass returns a band pass filter passing frequencies between low
+// and high Hz, a cascade of Butterworth high pass and low pass filters
+// of order n.
+func BandPass(low, high float64, n int) *IIR {
+	return &IIR{sections: append(butterworth(true, low, n), butterworth(false, high, n)...)}
+}
+
+// Reset designs the filter for the sample rate and clears its state.
+func (f *IIR) Reset(interval scope.Duration) {
+	fs := sampleRate(interval)
+	f.biquads = make([]biquad, len(f.sections))
+	for i, s := range f.sections {
+		f.biquads[i] = s.design(fs)
+	}
+}
+
+// Process returns the filtered samples.
+func (f *IIR) Process(in []scope.Voltage) []scope.Voltage {
+	out := make([]scope.Voltage, len(in))
+	for i, v := range in {
+		x := float64(v)
+		for j := range f.biquads {
+			x = f.biquads[j].process(x)
+		}
+		out[i] = scope.Voltage(x)
+	}
+	return out
+}
@@ -18,13 +18,20 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
 	"sort"
 	"strings"
 
+	"github.com/zagrodzki/goscope/acquisition"
+	"github.com/zagrodzki/goscope/capture"
 	"github.com/zagrodzki/goscope/compat"
-	"github.com/zagrodzki/goscope/dummy"
+	"github.com/zagrodzki/goscope/devices"
+	"github.com/zagrodzki/goscope/filter"
+	"github.com/zagrodzki/goscope/mathchan"
+	"github.com/zagrodzki/goscope/measurements"
 	"github.com/zagrodzki/goscope/scope"
-	"github.com/zagrodzki/goscope/usb"
+	"github.com/zagrodzki/goscope/triggers"
 )
 
 var (
@@ -33,36 +40,35 @@ var (
 	chID     = flag.String("chan", "", "name of the channel to use. If not specified, use the first channel")
 	period   = flag.Duration("period", 0, "how long period of samples to collect, run forever if set to 0")
 	showHist = flag.Bool("histogram", false, "If true, output histogram of samples, otherwise only the mode")
+	measure  = flag.Bool("measure", false, "If true, output automatic measurements of the samples instead of the histogram")
+	stats    = flag.Bool("stats", false, "If true, print statistics of the measurements across all sweeps at exit")
+	bwLimit  = flag.String("bw_limit", "", "bandwidth limit applied to all channels, e.g. 20MHz or 1MHz. Empty means no limit")
+	acqMode  = flag.String("acquisition", "normal", "acquisition mode: normal, average, peak or hires")
+	averages = flag.String("averages", "16", "number of sweeps averaged in average acquisition mode")
+	decimate = flag.String("decimation", "16", "number of samples reduced to a min/max pair in peak acquisition mode or averaged in hires acquisition mode")
+	chID2    = flag.String("chan2", "", "name of the second channel. If set together with -measure, also output measurements comparing it with the first channel, e.g. phase and gain")
+	output   = flag.String("output", "", "if set, write the samples of all channels to this file: a goscope capture file if the name ends with .gsc, TSV if it ends with .tsv, otherwise CSV")
+	sweeps   = flag.Int("sweeps", 0, "how many sweeps to collect, run until -period is covered if set to 0")
+	timeBase = flag.Duration("timebase", 0, "length of a sweep. If set, the samples are assembled into sweeps of this length, starting at the trigger with -trigger_mode, otherwise every chunk of samples from the device is a sweep")
+
+	triggerSource = flag.String("trigger_source", "", "Name of the channel to use as a trigger source")
+	triggerThresh = flag.String("trigger_threshold", "0", "Trigger threshold")
+	triggerEdge   = flag.String("trigger_edge", "rising", "Trigger edge, rising or falling")
+	triggerMode   = flag.String("trigger_mode", "none", "Trigger mode: single, normal, auto or none")
 )
 
+var mathChans mathchan.Defs
+
+func init() {
+	flag.Var(&mathChans, "math", "math channel to add, as name=expression, e.g. diff=(CH1-CH2)*10. Can be repeated.")
+}
+
 func must(e error) {
 	if e != nil {
 		log.Fatalf(e.Error())
 	}
 }
 
-type system struct {
-	name      string
-	enumerate func() map[string]string
-	open      func(string) (scope.Device, error)
-}
-
-var (
-	systems = []system{
-		{
-			name:      "dummy",
-			enumerate: dummy.Enumerate,
-			open:      dummy.Open,
-		},
-		{
-			name:      "usb",
-			enumerate: usb.Enumerate,
-			open:      usb.Open,
-		},
-	}
-	systemsByName = make(map[string]int)
-)
-
 type orderedHist struct {
 	s map[scope.Voltage]int
 	k []scope.Voltage
@@ -88,47 +94,215 @@ func (o *orderedHist) sort() {
 	sort.Sort(o)
 }
 
-func main() {
-	flag.Parse()
-	var all []string
-	for idx, sys := range systems {
-		systemsByName[sys.name] = idx
-		for id := range sys.enumerate() {
-			all = append(all, fmt.Sprintf("%s:%s", sys.name, id))
+func printHistogram(samples []scope.Voltage) {
+	hist := &orderedHist{
+		s: make(map[scope.Voltage]int),
+	}
+	for _, d := range samples {
+		hist.s[d]++
+	}
+	hist.sort()
+	if *showHist {
+		out := make([]string, len(hist.k))
+		for k := range hist.k {
+			out[k] = fmt.Sprintf("%f: %d", hist.k[k], hist.s[hist.k[k]])
 		}
+		fmt.Println(out)
+	} else {
+		fmt.Println(hist.k[0])
+	}
+}
+
+func printMeasurements(samples []scope.Voltage, interval scope.Duration) {
+	r := measurements.Measure(samples, interval)
+	out := make([]string, len(measurements.All))
+	for i, m := range measurements.All {
+		out[i] = fmt.Sprintf("%s=%s", m, r.Format(m))
+	}
+	fmt.Println(strings.Join(out, " "))
+}
+
+func printDualMeasurements(a, b []scope.Voltage, interval scope.Duration) {
+	r := measurements.MeasureDual(a, b, interval)
+	out := make([]string, len(measurements.AllDual))
+	for i, m := range measurements.AllDual {
+		out[i] = fmt.Sprintf("%s=%s", m, r.Format(m))
+	}
+	fmt.Println(strings.Join(out, " "))
+}
+
+// paramsOf returns the params of the device layers that have them,
+// to be saved with the captured samples.
+func paramsOf(osc scope.Device) []scope.Param {
+	var ret []scope.Param
+	if d, ok := osc.(interface{ TriggerParams() []scope.Param }); ok {
+		ret = append(ret, d.TriggerParams()...)
 	}
-	if len(all) == 0 {
-		log.Fatalf("Did not find any supported devices")
+	if d, ok := osc.(interface{ AcquisitionParams() []scope.Param }); ok {
+		ret = append(ret, d.AcquisitionParams()...)
 	}
+	return ret
+}
+
+// addMathChannels adds the -math channels to osc. If osc is a trigger,
+// the channels are added to the device below it, so that they can
+// be used as the trigger source.
+func addMathChannels(osc scope.Device) (scope.Device, error) {
+	tr, ok := osc.(*triggers.Trigger)
+	if !ok || len(mathChans) == 0 {
+		return mathChans.Wrap(osc)
+	}
+	dev, err := mathChans.Wrap(tr.Device)
+	if err != nil {
+		return nil, err
+	}
+	return triggers.New(dev), nil
+}
+
+// triggerPos returns the position of the trigger in the sweeps
+// recorded from osc, -1 if osc doesn't trigger or the samples
+// aren't assembled into sweeps starting at the trigger.
+func triggerPos(osc scope.Device) int {
+	if *timeBase == 0 {
+		return -1
+	}
+	if d, ok := osc.(interface{ TriggerParams() []scope.Param }); ok {
+		for _, p := range d.TriggerParams() {
+			if p.Name() == "mode" && p.Value() != triggers.ModeNone.Value() {
+				// the trigger passes the sweeps starting at the triggering sample.
+				return 0
+			}
+		}
+	}
+	return -1
+}
+
+// outputFile writes the sweeps to a file.
+type outputFile struct {
+	w interface {
+		WriteSweep([]scope.ChannelData) error
+	}
+	// finish writes the buffered data to f.
+	finish func() error
+	f      *os.File
+}
+
+// createOutput creates the output file with the metadata md and the
+// channels of the first sweep. The file is a goscope capture file
+// if the name ends with .gsc, TSV if it ends with .tsv and CSV otherwise.
+func createOutput(name string, md capture.Metadata, first []scope.ChannelData) (*outputFile, error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(name, ".gsc") {
+		// The devices don't expose their ADC translation tables, and the
+		// filters and math channels produce voltages outside of them,
+		// so every channel is stored as Float64.
+		chans := make([]capture.ChannelInfo, len(first))
+		for i, d := range first {
+			chans[i] = capture.ChannelInfo{ID: d.ID, Encoding: capture.Float64}
+		}
+		w, err := capture.NewWriter(f, md, chans)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &outputFile{w: w, finish: w.Close, f: f}, nil
+	}
+	w := capture.NewCSVWriter(f, md)
+	if strings.HasSuffix(name, ".tsv") {
+		w.Comma = '\t'
+	}
+	return &outputFile{w: w, finish: w.Flush, f: f}, nil
+}
+
+// WriteSweep writes a sweep to the file.
+func (o *outputFile) WriteSweep(data []scope.ChannelData) error {
+	return o.w.WriteSweep(data)
+}
+
+// Close flushes the written sweeps and closes the file.
+func (o *outputFile) Close() error {
+	if err := o.finish(); err != nil {
+		o.f.Close()
+		return err
+	}
+	return o.f.Close()
+}
+
+func main() {
+	flag.Parse()
 	if *list {
 		fmt.Println("Devices found:")
-		for _, d := range all {
+		for _, d := range devices.List() {
 			fmt.Println(d)
 		}
 		return
 	}
-	id := all[0]
-	if *dev != "" {
-		for _, d := range all {
-			if d == *dev {
-				id = d
-				break
+	osc, err := devices.Open(*dev)
+	if err != nil {
+		log.Fatalf("Open: %+v", err)
+	}
+	fmt.Println(osc)
+	if osc, err = addMathChannels(osc); err != nil {
+		log.Fatalf("Invalid value of flag math: %v", err)
+	}
+	if tr, ok := osc.(*triggers.Trigger); ok {
+		for _, p := range tr.TriggerParams() {
+			var err error
+			switch p.Name() {
+			case "edge":
+				err = p.Set(*triggerEdge)
+			case "mode":
+				err = p.Set(*triggerMode)
+			case "level":
+				err = p.Set(*triggerThresh)
+			case "source":
+				err = p.Set(*triggerSource)
+			}
+			if err != nil {
+				log.Fatalf("Invalid value of flag trigger_%s: %v", p.Name(), err)
 			}
 		}
-		if id != *dev {
-			log.Fatalf("Device %s not detected on the list. Available devices: %v", *dev, all)
+	} else if *triggerMode != triggers.ModeNone.Value() {
+		log.Fatalf("Device %s does not support triggering", osc)
+	}
+	if *triggerMode != triggers.ModeNone.Value() && *timeBase == 0 {
+		log.Fatalf("Flag trigger_mode requires -timebase, the length of the triggered sweeps")
+	}
+	params := paramsOf(osc)
+	trigPos := triggerPos(osc)
+	if *acqMode != "normal" {
+		ad := acquisition.New(osc)
+		for _, p := range ad.AcquisitionParams() {
+			var err error
+			switch p.Name() {
+			case "acquisition":
+				err = p.Set(*acqMode)
+			case "averages":
+				err = p.Set(*averages)
+			case "decimation":
+				err = p.Set(*decimate)
+			}
+			if err != nil {
+				log.Fatalf("Invalid value of flag %s: %v", p.Name(), err)
+			}
 		}
-	} else if len(all) > 1 {
-		log.Printf("Multiple devices found: %v", all)
-		log.Printf("Using the first device (%s)", id)
+		osc = ad
+		params = append(params, ad.AcquisitionParams()...)
 	}
-	parts := strings.SplitN(id, ":", 2)
-	s := systemsByName[parts[0]]
-	osc, err := systems[s].open(parts[1])
-	if err != nil {
-		log.Fatalf("Open: %+v", err)
+	if *bwLimit != "" {
+		cutoff, err := filter.ParseFrequency(*bwLimit)
+		if err != nil {
+			log.Fatalf("Invalid value of flag bw_limit: %v", err)
+		}
+		fd := filter.New(osc)
+		for _, ch := range osc.Channels() {
+			must(fd.Set(ch, filter.BandwidthLimit(cutoff)))
+		}
+		osc = fd
 	}
-	fmt.Println(osc)
 	channels := osc.Channels()
 	ch := channels[0]
 	if *chID != "" {
@@ -138,43 +312,120 @@ func main() {
 			}
 		}
 		if ch != scope.ChanID(*chID) {
-			log.Fatalf("Device %s does not have a channel %q. Available channels: %v", id, *chID, channels)
+			log.Fatalf("Device %s does not have a channel %q. Available channels: %v", osc, *chID, channels)
+		}
+	}
+	if *chID2 != "" {
+		found := false
+		for _, c := range channels {
+			found = found || c == scope.ChanID(*chID2)
+		}
+		if !found {
+			log.Fatalf("Device %s does not have a channel %q. Available channels: %v", osc, *chID2, channels)
+		}
+	}
+	var st *measurements.Stats
+	if *stats {
+		if *chID2 != "" {
+			st = measurements.NewDualStats(osc, ch, scope.ChanID(*chID2))
+		} else {
+			st = measurements.NewStats(osc, ch)
 		}
+		osc = st
+	}
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	err = grab(osc, ch, params, trigPos, interrupt)
+	if st != nil {
+		st.Write(os.Stdout)
+	}
+	if err != nil {
+		log.Fatal(err)
 	}
-	rec := &compat.Recorder{}
+}
+
+// grab reads the samples of osc, prints the measurements or the histogram
+// of channel ch and writes all the channels to the -output file, together
+// with the params and the trigger position trigPos, until -period
+// or -sweeps is covered, the device stops or interrupt receives a signal.
+func grab(osc scope.Device, ch scope.ChanID, params []scope.Param, trigPos int, interrupt <-chan os.Signal) (err error) {
+	tb := scope.DurationFromNano(*timeBase)
+	rec := &compat.Recorder{TB: tb, Sweeps: tb > 0}
 	osc.Attach(rec)
 	osc.Start()
 	defer osc.Stop()
 	dur := scope.DurationFromNano(*period)
 	fmt.Printf("%s (%d)\n", dur, dur)
 	log.Printf("Reading %s of samples", dur)
-	for s := range rec.Data {
-		for _, chanData := range s.Channels {
-			if chanData.ID == ch {
-				hist := &orderedHist{
-					s: make(map[scope.Voltage]int),
+	var out *outputFile
+	defer func() {
+		if out == nil {
+			return
+		}
+		if cerr := out.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("writing %s: %v", *output, cerr)
+		}
+	}()
+	collected := 0
+	for {
+		var s scope.Data
+		var ok bool
+		select {
+		case s, ok = <-rec.Data:
+		case <-interrupt:
+			return nil
+		}
+		if !ok {
+			return nil
+		}
+		if s.Error != nil {
+			return s.Error
+		}
+		if *output != "" && len(s.Channels) > 0 {
+			if out == nil {
+				// the interval is known only after the first sweep.
+				md := capture.Metadata{
+					Device:     osc.String(),
+					Interval:   s.Interval,
+					TimeBase:   scope.Duration(s.Num) * s.Interval,
+					TriggerPos: trigPos,
+					Params:     capture.ParamValues(params),
 				}
-				for _, d := range chanData.Samples {
-					hist.s[d]++
+				var err error
+				if out, err = createOutput(*output, md, s.Channels); err != nil {
+					return fmt.Errorf("output: %v", err)
 				}
-				hist.sort()
-				if *showHist {
-					out := make([]string, len(hist.k))
-					for k := range hist.k {
-						out[k] = fmt.Sprintf("%f: %d", hist.k[k], hist.s[hist.k[k]])
+			}
+			if err := out.WriteSweep(s.Channels); err != nil {
+				return fmt.Errorf("writing %s: %v", *output, err)
+			}
+		}
+		if len(s.Channels) > 0 {
+			collected++
+		}
+		for _, chanData := range s.Channels {
+			if chanData.ID == ch {
+				if *measure {
+					printMeasurements(chanData.Samples, s.Interval)
+					for _, chanData2 := range s.Channels {
+						if *chID2 != "" && chanData2.ID == scope.ChanID(*chID2) {
+							printDualMeasurements(chanData.Samples, chanData2.Samples, s.Interval)
+						}
 					}
-					fmt.Println(out)
 				} else {
-					fmt.Println(hist.k[0])
+					printHistogram(chanData.Samples)
 				}
 				if *period != 0 {
 					covered := scope.Duration(len(chanData.Samples)) * s.Interval
 					if dur < covered {
-						return
+						return nil
 					}
 					dur -= covered
 				}
 			}
 		}
+		if *sweeps != 0 && collected == *sweeps {
+			return nil
+		}
 	}
 }
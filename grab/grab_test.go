@@ -0,0 +1,147 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zagrodzki/goscope/capture"
+	"github.com/zagrodzki/goscope/dummy"
+	"github.com/zagrodzki/goscope/mathchan"
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/triggers"
+)
+
+func TestGrabTriggeredSweeps(t *testing.T) {
+	defer func(o string, n int, tb time.Duration) { *output, *sweeps, *timeBase = o, n, tb }(*output, *sweeps, *timeBase)
+	// the square wave is high for 20 samples and low for 20 samples,
+	// the dummy device sends chunks of 1000 samples.
+	for _, tc := range []struct {
+		desc string
+		tb   time.Duration
+	}{
+		{"within a chunk", 30 * time.Millisecond},
+		{"across chunks", 1500 * time.Millisecond},
+	} {
+		name := filepath.Join(t.TempDir(), "out.csv")
+		*output, *sweeps, *timeBase = name, 3, tc.tb
+		osc, err := dummy.Open("square")
+		if err != nil {
+			t.Fatalf("dummy.Open: %v", err)
+		}
+		params := osc.(*triggers.Trigger).TriggerParams()
+		for _, p := range params {
+			if p.Name() == "mode" {
+				if err := p.Set("normal"); err != nil {
+					t.Fatalf("mode.Set: %v", err)
+				}
+			}
+		}
+		if err := grab(osc, "square", params, triggerPos(osc), nil); err != nil {
+			t.Fatalf("%s: grab: %v", tc.desc, err)
+		}
+
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		md, got, err := capture.ReadCSV(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: ReadCSV: %v", tc.desc, err)
+		}
+		tb := scope.DurationFromNano(tc.tb)
+		if md.TriggerPos != 0 || md.TimeBase != tb {
+			t.Errorf("%s: metadata: got trigger position %d, timebase %v, want 0, %v", tc.desc, md.TriggerPos, md.TimeBase, tb)
+		}
+		if len(got) != 3 {
+			t.Fatalf("%s: got %d sweeps, want 3", tc.desc, len(got))
+		}
+		n := int(tb / scope.Millisecond)
+		for i, s := range got {
+			samples := s[0].Samples
+			if len(samples) != n {
+				t.Fatalf("%s: sweep %d: got %d samples, want %d", tc.desc, i, len(samples), n)
+			}
+			// every sweep starts at the rising edge.
+			for j, v := range samples {
+				if want := scope.Voltage(1 - 2*(j/20%2)); v != want {
+					t.Errorf("%s: sweep %d sample %d: got %v, want %v", tc.desc, i, j, v, want)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestGrabTriggerOnMathChannel(t *testing.T) {
+	defer func(o string, n int, tb time.Duration, m mathchan.Defs) {
+		*output, *sweeps, *timeBase, mathChans = o, n, tb, m
+	}(*output, *sweeps, *timeBase, mathChans)
+	name := filepath.Join(t.TempDir(), "out.csv")
+	*output, *sweeps, *timeBase, mathChans = name, 2, 30*time.Millisecond, mathchan.Defs{"inv=-square"}
+	dev, err := dummy.Open("square")
+	if err != nil {
+		t.Fatalf("dummy.Open: %v", err)
+	}
+	osc, err := addMathChannels(dev)
+	if err != nil {
+		t.Fatalf("addMathChannels: %v", err)
+	}
+	params := osc.(*triggers.Trigger).TriggerParams()
+	for _, p := range params {
+		var err error
+		switch p.Name() {
+		case "mode":
+			err = p.Set("normal")
+		case "source":
+			err = p.Set("inv")
+		}
+		if err != nil {
+			t.Fatalf("TriggerParams[%q].Set: %v", p.Name(), err)
+		}
+	}
+	if err := grab(osc, "square", params, triggerPos(osc), nil); err != nil {
+		t.Fatalf("grab: %v", err)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	_, got, err := capture.ReadCSV(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("ReadCSV: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d sweeps, want 2", len(got))
+	}
+	for i, s := range got {
+		if len(s) != 2 || s[1].ID != "inv" {
+			t.Fatalf("sweep %d: got channels %v, want square and inv", i, s)
+		}
+		// every sweep starts at the rising edge of inv, the falling edge of square.
+		for j, v := range s[0].Samples {
+			if want := scope.Voltage(2*(j/20%2) - 1); v != want {
+				t.Errorf("sweep %d sample %d: got %v, want %v", i, j, v, want)
+				break
+			}
+		}
+	}
+}
@@ -17,3 +17,5 @@ gnuplot -e "$OPTS plot [0.5:3.5] x <= 1 ? 1 : x <= 2 ? -1 : x <= 3 ? 1 : -1 $LW5
 
 gnuplot -e "$OPTS f(x)=abs(0.1/x); g(x)=-0.03-0.25*(2.718**(2*-x**2)); set xrange [-10:10]; plot '+' using 1:(f(\$1)):(g(\$1)) with filledcurves closed" > spike-int-gp.png
 gnuplot -e "$OPTS f(x)=x<-1.05 ? -0.5-0.02/(x+1) : x<1 ? 0.52+(x/2.5)**2 : -0.5+0.02/(x-0.95); g(x)=x<-1 ? -0.5+0.03/(x+0.9) : x<0.95 ? 0.48-(x/2.5)**2 : -0.5-0.03/(x-0.85); set xrange [-3:3]; plot '+' using 1:(f(\$1)):(g(\$1)) with filledcurves closed" > square-int-gp.png
+
+gnuplot -e "$OPTS set samples 100000; plot [0:99999] x == 30000 ? 1 : x == 70000 ? -1 : 0 $LW5" > spikes-gp.png
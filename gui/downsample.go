@@ -0,0 +1,158 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"log"
+	"math"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Downsampler selects the strategy of drawing the samples when there are
+// more samples than pixel columns in the plot.
+type Downsampler int
+
+const (
+	// DownsampleAverage draws the average of the samples in every pixel column.
+	// It is cheap, but hides the spikes narrower than a pixel column.
+	DownsampleAverage Downsampler = iota
+	// DownsampleMinMax draws the vertical span between the minimum and
+	// the maximum of the samples in every pixel column, i.e. the envelope
+	// of the signal. All spikes are preserved.
+	DownsampleMinMax
+	// DownsampleLTTB draws a line through the samples selected with
+	// the Largest-Triangle-Three-Buckets algorithm, one sample per pixel
+	// column, which preserves the visual shape of the signal.
+	DownsampleLTTB
+)
+
+var downsampleType = flag.String("downsampling", "average", "strategy of drawing more samples than pixels: one of average, minmax, lttb")
+
+func downsampler() Downsampler {
+	switch *downsampleType {
+	case "average":
+		return DownsampleAverage
+	case "minmax":
+		return DownsampleMinMax
+	case "lttb":
+		return DownsampleLTTB
+	}
+	log.Fatalf("Invalid value %q for flag \"downsampling\", want one of: average, minmax, lttb", *downsampleType)
+	return DownsampleAverage
+}
+
+// span is the vertical extent of the samples mapped to one pixel column.
+type span struct {
+	x           int
+	min, max    int
+	first, last int
+}
+
+func samplesToSpans(samples []scope.Voltage, traceParams scope.TraceParams, rect image.Rectangle) []span {
+	if len(samples) == 0 {
+		return nil
+	}
+	m := newPointMapper(len(samples), traceParams, rect)
+	spans := make([]span, 0, rect.Dx())
+	for i, v := range samples {
+		x, y := m.x(i), round(m.y(v))
+		if len(spans) == 0 || spans[len(spans)-1].x != x {
+			spans = append(spans, span{x, y, y, y, y})
+			continue
+		}
+		s := &spans[len(spans)-1]
+		s.min = min(s.min, y)
+		s.max = max(s.max, y)
+		s.last = y
+	}
+	return spans
+}
+
+// drawEnvelope draws the spans as vertical lines, connecting the last sample
+// of every column with the first sample of the next one.
+func (plot Plot) drawEnvelope(spans []span, rect image.Rectangle, col color.RGBA) {
+	for i, s := range spans {
+		if i > 0 {
+			prev := spans[i-1]
+			plot.drawTraceLine(image.Point{prev.x, prev.last}, image.Point{s.x, s.first}, rect, col)
+		}
+		plot.drawTraceLine(image.Point{s.x, s.min}, image.Point{s.x, s.max}, rect, col)
+	}
+}
+
+// lttb returns the indices of n samples selected with
+// the Largest-Triangle-Three-Buckets algorithm described in
+// https://skemman.is/bitstream/1946/15343/3/SS_MSthesis.pdf.
+// The first and the last sample are always selected, the remaining samples
+// are split into n-2 buckets and from every bucket the sample forming
+// the largest triangle with the previously selected sample and the average
+// of the next bucket is selected.
+func lttb(samples []scope.Voltage, n int) []int {
+	if n >= len(samples) || n < 3 {
+		ret := make([]int, len(samples))
+		for i := range ret {
+			ret[i] = i
+		}
+		return ret
+	}
+	ret := make([]int, 0, n)
+	ret = append(ret, 0)
+	every := float64(len(samples)-2) / float64(n-2)
+	a := 0
+	for i := 0; i < n-2; i++ {
+		avgStart := int(float64(i+1)*every) + 1
+		avgEnd := min(int(float64(i+2)*every)+1, len(samples))
+		var avgX, avgY float64
+		for j := avgStart; j < avgEnd; j++ {
+			avgX += float64(j)
+			avgY += float64(samples[j])
+		}
+		avgX /= float64(avgEnd - avgStart)
+		avgY /= float64(avgEnd - avgStart)
+
+		ax, ay := float64(a), float64(samples[a])
+		maxArea := -1.0
+		next := a
+		for j := int(float64(i)*every) + 1; j < int(float64(i+1)*every)+1; j++ {
+			// twice the area of the triangle, the factor doesn't matter for comparison.
+			area := math.Abs((ax-avgX)*(float64(samples[j])-ay) - (ax-float64(j))*(avgY-ay))
+			if area > maxArea {
+				maxArea = area
+				next = j
+			}
+		}
+		ret = append(ret, next)
+		a = next
+	}
+	return append(ret, len(samples)-1)
+}
+
+// indexedSamplesToPoints maps the samples with given indices to the points
+// in the plot rectangle.
+func indexedSamplesToPoints(samples []scope.Voltage, indices []int, traceParams scope.TraceParams, rect image.Rectangle) []image.Point {
+	if len(samples) == 0 {
+		return nil
+	}
+	m := newPointMapper(len(samples), traceParams, rect)
+	points := make([]image.Point, len(indices))
+	for i, idx := range indices {
+		points[i] = image.Point{m.x(idx), round(m.y(samples[idx]))}
+	}
+	return points
+}
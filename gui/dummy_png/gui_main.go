@@ -20,16 +20,25 @@ import (
 
 	"github.com/zagrodzki/goscope/dummy"
 	"github.com/zagrodzki/goscope/gui"
+	"github.com/zagrodzki/goscope/mathchan"
+	"github.com/zagrodzki/goscope/scope"
 )
 
 var (
-	fileName = flag.String("file", "draw.png", "output file name")
-	width    = flag.Int("width", 800, "PNG width")
-	height   = flag.Int("height", 600, "PNG width")
-	tracePos = posFlag("tpos", "zero and volts per div, format: \"chanID:zero,perDiv\"")
-	cols     = colFlag("col", "color, format: \"chanID:R,G,B\"")
+	fileName  = flag.String("file", "draw.png", "output file name")
+	format    = flag.String("format", "png", "output format: png, or svg or pdf for a vector plot with the graticule and a table of measurements")
+	width     = flag.Int("width", 800, "PNG width")
+	height    = flag.Int("height", 600, "PNG width")
+	tracePos  = posFlag("tpos", "zero and volts per div, format: \"chanID:zero,perDiv\"")
+	cols      = colFlag("col", "color, format: \"chanID:R,G,B\"")
+	mathChans mathchan.Defs
+	xy        = flag.String("xy", "", "plot the second channel against the first instead of against time, format: \"xChanID,yChanID\"")
 )
 
+func init() {
+	flag.Var(&mathChans, "math", "math channel, format: \"chanID=expression\", e.g. diff=sin-triangle. Can be repeated.")
+}
+
 func main() {
 	flag.Parse()
 	dev, err := dummy.Open("")
@@ -37,7 +46,29 @@ func main() {
 		log.Fatalf("Cannot open the device: %v", err)
 	}
 
-	err = gui.PlotToPng(dev, *width, *height, *tracePos, *cols, *fileName)
+	osc, err := mathChans.Wrap(dev)
+	if err != nil {
+		log.Fatalf("Invalid value of flag math: %v", err)
+	}
+
+	switch {
+	case *format != "png" && *format != "svg" && *format != "pdf":
+		log.Fatalf("Invalid value %q of flag format, want png, svg or pdf", *format)
+	case *format != "png" && *xy != "":
+		log.Fatalf("XY plots can be saved only as png")
+	}
+
+	if *xy != "" {
+		var x, y scope.ChanID
+		if x, y, err = gui.ParseXY(*xy, osc.Channels()); err != nil {
+			log.Fatalf("Invalid value of flag xy: %v", err)
+		}
+		err = gui.XYPlotToPng(osc, *width, *height, x, y, *tracePos, *cols, *fileName)
+	} else if *format != "png" {
+		err = gui.PlotToVector(osc, *width, *height, *tracePos, *cols, *format, *fileName)
+	} else {
+		err = gui.PlotToPng(osc, *width, *height, *tracePos, *cols, *fileName)
+	}
 	if err != nil {
 		log.Fatalf("Cannot plot to file: %v", err)
 	}
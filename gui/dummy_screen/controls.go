@@ -0,0 +1,236 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"strconv"
+	"sync"
+
+	"github.com/zagrodzki/goscope/control"
+	"github.com/zagrodzki/goscope/gui"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// controls applies the changes made with the keyboard and the mouse
+// to the device and the display.
+type controls struct {
+	ctl *control.Controller
+	wf  *waveform
+
+	// mu guards the magnified part of the sweep.
+	mu sync.Mutex
+	// zoomPerDiv and zoomCenter select the magnified part of the sweep.
+	zoomPerDiv *gui.ScaleParam
+	zoomCenter scope.Duration
+}
+
+// newControls returns the controls of the device run by ctl,
+// displayed in wf.
+func newControls(ctl *control.Controller, wf *waveform) *controls {
+	c := &controls{
+		ctl:        ctl,
+		wf:         wf,
+		zoomPerDiv: gui.NewScaleParam("zoom time/div", "s", 1e-9, 10, float64(ctl.TimePerDiv())/float64(scope.Second)),
+		zoomCenter: ctl.TimeBase() / 2,
+	}
+	for _, ch := range ctl.Channels() {
+		c.applyChannel(ch)
+	}
+	if wf.cursors != nil {
+		wf.cursors.setChannel(ctl.SelectedChannel())
+	}
+	c.applyZoom()
+	return c
+}
+
+// applyChannel displays the channel ch with its vertical settings.
+func (c *controls) applyChannel(ch scope.ChanID) {
+	c.wf.SetChannel(ch, c.ctl.TraceParams(ch))
+}
+
+// start starts the device.
+func (c *controls) start() {
+	c.ctl.Start()
+	c.updateReadouts()
+}
+
+// stop stops the device, if it's running.
+func (c *controls) stop() {
+	c.ctl.Stop()
+}
+
+// runStop starts the device if it's stopped and stops it if it's running.
+func (c *controls) runStop() {
+	c.wf.setHeld(c.ctl.Running())
+	c.ctl.RunStop()
+	c.updateReadouts()
+}
+
+// stepTimeBase changes the time/div to the next larger (up is true)
+// or smaller setting. While the acquisition is stopped, it changes
+// the magnification of the held sweep instead.
+func (c *controls) stepTimeBase(up bool) {
+	if !c.ctl.Running() {
+		c.stepZoom(up)
+		return
+	}
+	c.ctl.StepTimeBase(up)
+	c.mu.Lock()
+	c.applyZoom()
+	c.mu.Unlock()
+	c.updateReadouts()
+}
+
+// stepZoom changes the magnification of the sweep, up selects
+// the larger time/div, i.e. the smaller magnification.
+func (c *controls) stepZoom(up bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	gui.StepParam(c.zoomPerDiv, up)
+	c.applyZoom()
+}
+
+// pan moves the magnified part of the sweep by one division
+// to the right (if right is true) or to the left.
+func (c *controls) pan(right bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	step := scope.Duration(c.zoomPerDiv.Float() * float64(scope.Second))
+	switch {
+	case right:
+		c.zoomCenter += step
+	case c.zoomCenter > step:
+		c.zoomCenter -= step
+	default:
+		c.zoomCenter = 0
+	}
+	c.applyZoom()
+}
+
+// applyZoom limits the magnified part of the sweep to the sweep
+// and displays it.
+// Must be called with c.mu held.
+func (c *controls) applyZoom() {
+	if perDiv := c.ctl.TimePerDiv(); scope.Duration(c.zoomPerDiv.Float()*float64(scope.Second)) > perDiv {
+		c.zoomPerDiv.Set(c.ctl.Value("time/div"))
+	}
+	window := scope.Duration(c.zoomPerDiv.Float() * gui.DivCols * float64(scope.Second))
+	tb := c.ctl.TimeBase()
+	if window > tb {
+		window = tb
+	}
+	if c.zoomCenter < window/2 {
+		c.zoomCenter = window / 2
+	}
+	if c.zoomCenter > tb-window/2 {
+		c.zoomCenter = tb - window/2
+	}
+	c.wf.setZoom(gui.Zoom{Window: window, Center: c.zoomCenter})
+}
+
+// selectChannel selects the channel changed by the vertical controls.
+func (c *controls) selectChannel(i int) {
+	if c.ctl.SelectChannel(i) && c.wf.cursors != nil {
+		c.wf.cursors.setChannel(c.ctl.SelectedChannel())
+	}
+}
+
+// toggleCursors enables or disables the time (if time is true) or the
+// voltage cursors. The voltage cursors measure the selected channel.
+func (c *controls) toggleCursors(time bool) {
+	if c.wf.cursors != nil {
+		c.wf.cursors.toggle(time)
+	}
+}
+
+// press picks the cursor nearest to the point p and moves it to p.
+func (c *controls) press(p image.Point) {
+	if c.wf.cursors != nil {
+		c.wf.cursors.press(p)
+	}
+}
+
+// drag moves the cursor picked by press to the point p.
+func (c *controls) drag(p image.Point) {
+	if c.wf.cursors != nil {
+		c.wf.cursors.move(p)
+	}
+}
+
+// release ends dragging the cursor picked by press.
+func (c *controls) release() {
+	if c.wf.cursors != nil {
+		c.wf.cursors.release()
+	}
+}
+
+// stepVoltsPerDiv changes the volts/div of the selected channel.
+func (c *controls) stepVoltsPerDiv(up bool) {
+	ch, _ := c.ctl.StepVoltsPerDiv(up)
+	c.applyChannel(ch)
+}
+
+// stepOffset moves the selected channel up or down the screen.
+func (c *controls) stepOffset(up bool) {
+	ch, _ := c.ctl.StepOffset(up)
+	c.applyChannel(ch)
+}
+
+// stepTrigger changes the trigger param name.
+func (c *controls) stepTrigger(name string, up bool) {
+	if p, _, err := c.ctl.StepTrigger(name, up); p != nil && err != nil {
+		log.Printf("trigger %s: %v", name, err)
+	}
+	c.updateReadouts()
+}
+
+// selectParam selects the next device param changed by stepParam.
+func (c *controls) selectParam() {
+	c.ctl.SelectParam()
+	c.updateReadouts()
+}
+
+// stepParam changes the selected device param.
+func (c *controls) stepParam(up bool) {
+	if p, _, err := c.ctl.StepParam(up); p != nil && err != nil {
+		log.Printf("%s: %v", p.Name(), err)
+	}
+	c.updateReadouts()
+}
+
+// updateReadouts updates the state and the trigger markers on the graticule.
+func (c *controls) updateReadouts() {
+	g := c.wf.graticule
+	if g == nil {
+		return
+	}
+	state := c.ctl.State()
+	if p := c.ctl.SelectedParam(); p != nil {
+		// the param changed by the generic param controls.
+		state += fmt.Sprintf(", %s: %s", p.Name(), c.ctl.Value(p.Name()))
+	}
+	g.SetState(state)
+	if mode := c.ctl.Value("mode"); mode == "" || mode == "none" {
+		g.SetTrigger(nil)
+		return
+	}
+	m := &gui.TriggerMarker{Source: scope.ChanID(c.ctl.Value("source"))}
+	l, _ := strconv.ParseFloat(c.ctl.Value("level"), 64)
+	m.Level = scope.Voltage(l)
+	g.SetTrigger(m)
+}
@@ -0,0 +1,222 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+
+	"github.com/zagrodzki/goscope/cursors"
+	"github.com/zagrodzki/goscope/gui"
+	"github.com/zagrodzki/goscope/measurements"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// readoutLineHeight is the distance between the lines of the cursor readouts.
+const readoutLineHeight = 16
+
+// cursorView shows the measurement cursors over the waveform
+// and their readouts.
+type cursorView struct {
+	mu  sync.Mutex
+	cur cursors.Cursors
+	// drag is the cursor being dragged with the mouse.
+	drag   cursors.Cursor
+	tp     map[scope.ChanID]scope.TraceParams
+	layer  *gui.CursorLayer
+	labels *gui.AnnotationLayer
+	// data is a copy of the last sweep, sampled every interval,
+	// kept to update the readouts when the cursors move.
+	data     []scope.ChannelData
+	interval scope.Duration
+	cols     map[scope.ChanID]color.RGBA
+}
+
+func newCursorView(size image.Point, rect image.Rectangle) *cursorView {
+	return &cursorView{
+		cur:    cursors.Cursors{Screen: cursors.Screen{Rect: rect, Rows: gui.DivRows}},
+		tp:     make(map[scope.ChanID]scope.TraceParams),
+		layer:  gui.NewCursorLayer(size, rect),
+		labels: gui.NewAnnotationLayer(size),
+		cols:   make(map[scope.ChanID]color.RGBA),
+	}
+}
+
+// setTimeBase sets the length of the displayed sweep.
+func (v *cursorView) setTimeBase(d scope.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.cur.Screen.TimeBase = d
+	v.layer.SetTimeBase(d)
+	v.updateLayer()
+}
+
+// setTraceParams sets the display params of channel ch.
+func (v *cursorView) setTraceParams(ch scope.ChanID, tp scope.TraceParams) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.tp[ch] = tp
+	if ch == v.cur.Channel {
+		v.cur.TraceParams = tp
+		v.updateLayer()
+	}
+}
+
+// setChannel selects the channel measured by the voltage cursors.
+func (v *cursorView) setChannel(ch scope.ChanID) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if ch == v.cur.Channel {
+		return
+	}
+	v.layer.SetVoltageCursors(v.cur.Channel)
+	v.cur.Channel = ch
+	v.cur.TraceParams = v.tp[ch]
+	v.updateLayer()
+}
+
+// toggle enables or disables the time (if time is true)
+// or the voltage cursors.
+func (v *cursorView) toggle(time bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.cur.ShowTime && !v.cur.ShowVoltage {
+		v.cur.Reset()
+	}
+	if time {
+		v.cur.ShowTime = !v.cur.ShowTime
+	} else {
+		v.cur.ShowVoltage = !v.cur.ShowVoltage
+	}
+	v.updateLayer()
+}
+
+// press picks the enabled cursor nearest to p and moves it to p.
+func (v *cursorView) press(p image.Point) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.drag = v.cur.Nearest(p)
+	if v.drag == cursors.None {
+		return
+	}
+	v.cur.Move(v.drag, p)
+	v.updateLayer()
+}
+
+// move moves the dragged cursor, if any, to p.
+func (v *cursorView) move(p image.Point) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.drag == cursors.None {
+		return
+	}
+	v.cur.Move(v.drag, p)
+	v.updateLayer()
+}
+
+// release ends dragging the cursor.
+func (v *cursorView) release() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.drag = cursors.None
+}
+
+// setColor sets the color of the readouts of channel ch.
+func (v *cursorView) setColor(ch scope.ChanID, col color.RGBA) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.cols[ch] = col
+}
+
+// color returns the color of the readouts of channel ch.
+// Must be called with v.mu held.
+func (v *cursorView) color(ch scope.ChanID) color.RGBA {
+	if col, ok := v.cols[ch]; ok {
+		return col
+	}
+	return gui.ColorBlack
+}
+
+// updateLayer updates the positions of the cursors on the display
+// and the readouts.
+// Must be called with v.mu held.
+func (v *cursorView) updateLayer() {
+	if v.cur.ShowTime {
+		v.layer.SetTimeCursors(v.cur.T[:]...)
+	} else {
+		v.layer.SetTimeCursors()
+	}
+	if v.cur.ShowVoltage {
+		v.layer.SetVoltageCursors(v.cur.Channel, v.cur.V[:]...)
+	} else {
+		v.layer.SetVoltageCursors(v.cur.Channel)
+	}
+	v.updateReadouts()
+}
+
+// setSweep updates the readouts with the values of the sweep data,
+// sampled every interval.
+func (v *cursorView) setSweep(data []scope.ChannelData, interval scope.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.cur.ShowTime && !v.cur.ShowVoltage {
+		return
+	}
+	v.interval = interval
+	v.data = v.data[:0]
+	for _, d := range data {
+		v.data = append(v.data, scope.ChannelData{ID: d.ID, Samples: append([]scope.Voltage(nil), d.Samples...)})
+	}
+	v.updateReadouts()
+}
+
+// updateReadouts updates the readouts of the cursors.
+// Must be called with v.mu held.
+func (v *cursorView) updateReadouts() {
+	if !v.cur.ShowTime && !v.cur.ShowVoltage {
+		v.labels.SetLabels()
+		return
+	}
+	r := v.cur.Readout(v.data, v.interval)
+	rect := v.cur.Screen.Rect
+	var labels []gui.Label
+	add := func(text string, col color.RGBA) {
+		labels = append(labels, gui.Label{
+			Pos:   image.Point{rect.Min.X + 10, rect.Min.Y + 40 + len(labels)*readoutLineHeight},
+			Text:  text,
+			Color: col,
+		})
+	}
+	if v.cur.ShowTime {
+		add(fmt.Sprintf("Δt=%s  1/Δt=%s", r.DeltaT, measurements.Hertz(r.Freq)), gui.ColorBlack)
+		for _, cv := range r.Values {
+			add(fmt.Sprintf("%s: %s, %s", cv.ID, cursorValue(cv, 0), cursorValue(cv, 1)), v.color(cv.ID))
+		}
+	}
+	if v.cur.ShowVoltage {
+		add(fmt.Sprintf("ΔV=%sV (%s)", r.DeltaV, v.cur.Channel), v.color(v.cur.Channel))
+	}
+	v.labels.SetLabels(labels...)
+}
+
+// cursorValue returns the value of the channel at the time cursor i.
+func cursorValue(cv cursors.ChannelValues, i int) string {
+	if !cv.OK[i] {
+		return "-"
+	}
+	return cv.V[i].String() + "V"
+}
@@ -15,13 +15,148 @@
 package main
 
 import (
+	"image"
+
 	"golang.org/x/exp/shiny/screen"
 	"golang.org/x/mobile/event/key"
 	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/mouse"
 )
 
+// Key bindings:
+//
+//	Left/Right       time/div smaller/larger; while stopped, magnify the held
+//	                 sweep more/less. From -roll_above time/div the waveform
+//	                 scrolls as the samples arrive and the triggering is off
+//	Shift-Left/Right magnify the sweep more/less in the zoom window (see -zoom)
+//	, and .          move the magnified part of the sweep left/right
+//	1-9              select the channel changed by the vertical controls
+//	Down/Up          volts/div of the selected channel smaller/larger
+//	PageDown/PageUp  move the selected channel down/up
+//	[ and ]          trigger level down/up
+//	E, M, S          next trigger edge, mode and source, with Shift the previous one
+//	Tab              select the next device param
+//	- and =          step the selected device param down/up
+//	Space            run/stop
+//	C, V             show/hide the time and the voltage cursors; the voltage
+//	                 cursors measure the selected channel
+//	Escape, Ctrl-C   quit
+//
+// The mouse wheel changes the time/div, with Shift the volts/div of the
+// selected channel, with Ctrl the trigger level and with Alt the position
+// of the selected channel. The left mouse button moves the cursor nearest
+// to the pointer, the cursor follows the pointer until the button is released.
+
+// handleKey applies the key press e to the controls.
+func handleKey(c *controls, e key.Event) {
+	if e.Direction == key.DirRelease {
+		return
+	}
+	back := e.Modifiers&key.ModShift > 0
+	switch e.Code {
+	case key.CodeLeftArrow:
+		if back {
+			c.stepZoom(false)
+		} else {
+			c.stepTimeBase(false)
+		}
+	case key.CodeRightArrow:
+		if back {
+			c.stepZoom(true)
+		} else {
+			c.stepTimeBase(true)
+		}
+	case key.CodeComma:
+		c.pan(false)
+	case key.CodeFullStop:
+		c.pan(true)
+	case key.CodeDownArrow:
+		c.stepVoltsPerDiv(false)
+	case key.CodeUpArrow:
+		c.stepVoltsPerDiv(true)
+	case key.CodePageDown:
+		c.stepOffset(false)
+	case key.CodePageUp:
+		c.stepOffset(true)
+	case key.CodeLeftSquareBracket:
+		c.stepTrigger("level", false)
+	case key.CodeRightSquareBracket:
+		c.stepTrigger("level", true)
+	case key.CodeE:
+		c.stepTrigger("edge", !back)
+	case key.CodeM:
+		c.stepTrigger("mode", !back)
+	case key.CodeS:
+		c.stepTrigger("source", !back)
+	case key.CodeTab:
+		c.selectParam()
+	case key.CodeHyphenMinus:
+		c.stepParam(false)
+	case key.CodeEqualSign:
+		c.stepParam(true)
+	case key.CodeC:
+		if e.Direction == key.DirPress {
+			c.toggleCursors(true)
+		}
+	case key.CodeV:
+		if e.Direction == key.DirPress {
+			c.toggleCursors(false)
+		}
+	case key.CodeSpacebar:
+		if e.Direction == key.DirPress {
+			c.runStop()
+		}
+	default:
+		if e.Code >= key.Code1 && e.Code <= key.Code9 {
+			c.selectChannel(int(e.Code - key.Code1))
+		}
+	}
+}
+
+// handleMouse applies the mouse event e to the controls.
+func handleMouse(c *controls, e mouse.Event) {
+	p := image.Point{int(e.X), int(e.Y)}
+	switch e.Direction {
+	case mouse.DirPress:
+		if e.Button == mouse.ButtonLeft {
+			c.press(p)
+		}
+	case mouse.DirRelease:
+		if e.Button == mouse.ButtonLeft {
+			c.release()
+		}
+	case mouse.DirNone:
+		c.drag(p)
+	case mouse.DirStep:
+		handleWheel(c, e)
+	}
+}
+
+// handleWheel applies the mouse wheel event e to the controls.
+func handleWheel(c *controls, e mouse.Event) {
+	var up bool
+	switch e.Button {
+	case mouse.ButtonWheelUp:
+		up = true
+	case mouse.ButtonWheelDown:
+	default:
+		return
+	}
+	switch {
+	case e.Modifiers&key.ModShift > 0:
+		c.stepVoltsPerDiv(up)
+	case e.Modifiers&key.ModControl > 0:
+		c.stepTrigger("level", up)
+	case e.Modifiers&key.ModAlt > 0:
+		c.stepOffset(up)
+	default:
+		// wheel up zooms in, like on the time/div knob turned clockwise.
+		c.stepTimeBase(!up)
+	}
+}
+
 // wait for image events, like mouse click, key press etc.
-func processEvents(eq screen.EventDeque, stop chan<- struct{}) {
+func processEvents(eq screen.EventDeque, c *controls, stop chan<- struct{}) {
 	done := false
 	for {
 		e := eq.NextEvent()
@@ -33,7 +168,11 @@ func processEvents(eq screen.EventDeque, stop chan<- struct{}) {
 		case key.Event:
 			if v.Code == key.CodeEscape || (v.Code == key.CodeC && v.Modifiers&key.ModControl > 0) {
 				done = true
+			} else {
+				handleKey(c, v)
 			}
+		case mouse.Event:
+			handleMouse(c, v)
 		}
 		if done {
 			stop <- struct{}{}
@@ -23,21 +23,21 @@ import (
 	"log"
 	"os"
 	"runtime/pprof"
-	"strings"
 	"sync"
 	"time"
 
-	"github.com/golang/freetype/truetype"
-	"github.com/zagrodzki/goscope/dummy"
+	"github.com/zagrodzki/goscope/acquisition"
+	"github.com/zagrodzki/goscope/control"
+	"github.com/zagrodzki/goscope/devices"
+	"github.com/zagrodzki/goscope/filter"
 	"github.com/zagrodzki/goscope/gui"
+	"github.com/zagrodzki/goscope/mathchan"
+	"github.com/zagrodzki/goscope/measurements"
 	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/spectrum"
 	"github.com/zagrodzki/goscope/triggers"
-	"github.com/zagrodzki/goscope/usb"
 	"golang.org/x/exp/shiny/driver"
 	"golang.org/x/exp/shiny/screen"
-	"golang.org/x/image/font"
-	"golang.org/x/image/font/gofont/goregular"
-	"golang.org/x/image/math/fixed"
 	"golang.org/x/time/rate"
 )
 
@@ -55,44 +55,83 @@ var (
 	screenHeight     = flag.Int("height", 600, "UI height, in pixels")
 	refreshRateLimit = flag.Float64("refresh_rate", 25, "maximum refresh rate, in frames per second. 0 = no limit")
 	cpuprofile       = flag.String("cpuprofile", "", "File to which the program should write it's CPU profile (performance stats)")
+	view             = flag.String("view", "time", "what to display: time (waveform), spectrum, or split (waveform above the spectrum)")
+	specWindow       = flag.String("spectrum_window", "hann", "window function for the spectrum: rectangular, hann, hamming, blackman-harris or flat-top")
+	specScale        = flag.String("spectrum_scale", "dbv", "vertical scale of the spectrum: dbv or linear")
+	specRef          = flag.Float64("spectrum_ref", 0, "magnitude at the top of the spectrum, in dBV or volts, depending on spectrum_scale")
+	specPerDiv       = flag.Float64("spectrum_per_div", 10, "magnitude per div of the spectrum, in dB or volts, depending on spectrum_scale")
+	specAverages     = flag.Int("spectrum_averages", 1, "number of spectra to average")
+	acqMode          = flag.String("acquisition", "normal", "acquisition mode: normal, average, peak or hires")
+	averages         = flag.String("averages", "16", "number of sweeps averaged in average acquisition mode")
+	decimate         = flag.String("decimation", "16", "number of samples reduced to a min/max pair in peak acquisition mode or averaged in hires acquisition mode")
+	intensity        = flag.Bool("intensity", false, "intensity graded display: accumulate the sweeps and color the pixels by how often the traces pass through them")
+	persistence      = flag.String("persistence", "infinite", "how long the sweeps stay visible with -intensity: off, infinite or a duration like 2s")
+	bwLimit          = flag.String("bw_limit", "", "bandwidth limit applied to all channels, e.g. 20MHz or 1MHz. Empty means no limit")
+	zoomWindow       = flag.Bool("zoom", false, "show a magnified part of the waveform in a secondary window below the waveform")
+	rollAbove        = flag.Duration("roll_above", 100*time.Millisecond, "time/div from which the waveform scrolls continuously as the samples arrive (roll mode), with the triggering disabled. 0 disables the roll mode")
+	xy               = flag.String("xy", "", "XY mode: plot the second channel against the first instead of against time, format: \"xChanID,yChanID\"")
+	mathChans        mathchan.Defs
 )
 
-var labelFont font.Face
-
 func init() {
-	f, err := truetype.Parse(goregular.TTF)
-	if err != nil {
-		log.Fatalf("truetype.Parse(goregular): %v", err)
-	}
-	labelFont = truetype.NewFace(f, &truetype.Options{
-		Size: 20,
-	})
+	flag.Var(&mathChans, "math", "math channel, format: \"chanID=expression\", e.g. diff=sin-triangle. Can be repeated.")
 }
 
-func addLabel(img *image.RGBA, origin image.Point, label string) {
-	point := fixed.Point26_6{fixed.Int26_6(origin.X * 64), fixed.Int26_6(origin.Y * 64)}
-	d := &font.Drawer{
-		Dst:  img,
-		Src:  image.NewUniform(gui.ColorBlack),
-		Face: labelFont,
-		Dot:  point,
-	}
-	d.DrawString(label)
+// spectrumView holds the settings of the spectrum display.
+type spectrumView struct {
+	rect   image.Rectangle
+	params gui.SpectrumParams
+	window spectrum.Window
+	avg    map[scope.ChanID]*spectrum.Averager
+}
+
+// traceDisplay is a display layer showing the sweeps.
+type traceDisplay interface {
+	scope.DisplayLayer
+	Add([]scope.ChannelData)
 }
 
 type waveform struct {
 	tb      scope.Duration
 	inter   scope.Duration
-	tp      map[scope.ChanID]scope.TraceParams
 	bgImage *image.RGBA
+	// timeRect is the area of the waveform display, empty if
+	// only the spectrum is displayed.
+	timeRect image.Rectangle
+	// spec is nil if the spectrum is not displayed.
+	spec *spectrumView
+	// traces draws the waveform, either a gui.TraceLayer,
+	// a gui.Persistence if the intensity graded display is enabled
+	// or a gui.XYLayer in the XY mode.
+	traces traceDisplay
+	labels *gui.AnnotationLayer
+	// graticule is nil if only the spectrum is displayed.
+	graticule *gui.GraticuleLayer
+	// cursors is nil if only the spectrum is displayed.
+	cursors *cursorView
+	// zoom is nil if the zoom window is not displayed.
+	zoom       *zoomView
+	display    *gui.Compositor
+	displayImg *image.RGBA
+	// chColor are the colors of the channels, assigned
+	// on the first sweep.
+	chColor map[scope.ChanID]color.RGBA
 
 	mu      sync.Mutex
 	plot    gui.Plot
 	bufPlot gui.Plot
-}
 
-func (w *waveform) TimeBase() scope.Duration {
-	return w.tb
+	zoomMu sync.Mutex
+	// last is the full resolution copy of the last sweep,
+	// sampled every lastInter.
+	last      []scope.ChannelData
+	lastInter scope.Duration
+	// held is true while the acquisition is stopped.
+	held bool
+	// roll is true if the waveform is displayed in the roll mode.
+	roll bool
+	// zoomSel is the magnified part of the sweep.
+	zoomSel gui.Zoom
 }
 
 var allColors = []color.RGBA{
@@ -110,104 +149,207 @@ func (w *waveform) swapPlot() {
 	copy(w.bufPlot.Pix, w.bgImage.Pix)
 }
 
-func (w *waveform) keepReading(dataCh <-chan []scope.ChannelData) {
-	var buf []scope.ChannelData
-	var tbCount = int(w.tb / w.inter)
-	chColor := make(map[scope.ChanID]color.RGBA)
-	for data := range dataCh {
-		if len(data) == 0 {
-			continue
+// setColors assigns the colors to the channels of data.
+func (w *waveform) setColors(data []scope.ChannelData, chColor map[scope.ChanID]color.RGBA) {
+	for i, d := range data {
+		chColor[d.ID] = allColors[i]
+		if tl, ok := w.traces.(interface {
+			SetColor(scope.ChanID, color.RGBA)
+		}); ok {
+			tl.SetColor(d.ID, allColors[i])
 		}
-		if buf == nil {
-			buf = make([]scope.ChannelData, len(data))
-			for i, d := range data {
-				buf[i].ID = d.ID
-				buf[i].Samples = make([]scope.Voltage, 0, 2*tbCount)
-				chColor[d.ID] = allColors[i]
-			}
+		if w.graticule != nil {
+			w.graticule.SetColor(d.ID, allColors[i])
 		}
-		for i, d := range data {
-			buf[i].Samples = append(buf[i].Samples, d.Samples...)
+		if w.cursors != nil {
+			w.cursors.setColor(d.ID, allColors[i])
 		}
-		if len(buf[0].Samples) >= tbCount {
-			for i := range data {
-				buf[i].Samples = buf[i].Samples[:tbCount]
-			}
+		if w.zoom != nil {
+			w.zoom.setColor(d.ID, allColors[i])
+		}
+	}
+}
 
-			// full timebase, draw and go to beginning
-			w.bufPlot.DrawAll(buf, w.tp, chColor)
-			w.swapPlot()
-			// truncate the buffers
-			for i := range buf {
-				buf[i].Samples = buf[i].Samples[:0]
-			}
+// Sweep draws the sweep data. In the roll mode, the data is displayed
+// as soon as it arrives, appended at the right edge of the waveform,
+// which scrolls to the left.
+func (w *waveform) Sweep(data []scope.ChannelData, full bool) {
+	if len(w.chColor) == 0 {
+		w.setColors(data, w.chColor)
+	}
+	if full {
+		w.draw(data, w.chColor)
+		w.swapPlot()
+	}
+	w.setSweep(data)
+}
+
+func (w *waveform) draw(buf []scope.ChannelData, chColor map[scope.ChanID]color.RGBA) {
+	if w.spec == nil {
+		return
+	}
+	for _, d := range buf {
+		avg, ok := w.spec.avg[d.ID]
+		if !ok {
+			avg = &spectrum.Averager{N: *specAverages}
+			w.spec.avg[d.ID] = avg
 		}
+		s := avg.Add(spectrum.Compute(d.Samples, w.inter, w.spec.window))
+		w.bufPlot.DrawSpectrum(s, w.spec.params, w.spec.rect, chColor[d.ID])
 	}
 }
 
-func (w *waveform) Reset(inter scope.Duration, d <-chan []scope.ChannelData) {
-	w.inter = inter
-	go w.keepReading(d)
+// Reset sets up the display for the new run r of the device.
+func (w *waveform) Reset(r control.Run) {
+	w.inter = r.Interval
+	if w.spec != nil {
+		// the frequency span is known only once the sample rate is known.
+		nyquist := float64(scope.Second) / float64(r.Interval) / 2
+		unit := "dB"
+		if w.spec.params.Scale == spectrum.Linear {
+			unit = "V"
+		}
+		specLabel := gui.Label{
+			Pos:   image.Point{10, w.spec.rect.Min.Y + 20},
+			Text:  fmt.Sprintf("%s/hdiv, %g%s/vdiv, %s window", measurements.Hertz(nyquist/gui.DivCols), w.spec.params.PerDiv, unit, w.spec.window),
+			Color: gui.ColorBlack,
+		}
+		w.labels.SetLabels(specLabel)
+	}
+	if w.graticule != nil {
+		w.graticule.SetInterval(r.Interval)
+	}
+	if tl, ok := w.traces.(*gui.TraceLayer); ok {
+		if r.Roll {
+			tl.SetSweepLength(r.SweepLen)
+		} else {
+			tl.SetSweepLength(0)
+		}
+	}
+	w.zoomMu.Lock()
+	defer w.zoomMu.Unlock()
+	w.roll = r.Roll
+	w.pauseTraces()
+	w.tb = r.TimeBase
+	w.display.SetTimeBase(r.TimeBase)
+	if w.cursors != nil {
+		w.cursors.setTimeBase(r.TimeBase)
+	}
 }
 
 func (w *waveform) Error(err error) {
 	log.Fatal(err)
 }
 
-func (w *waveform) SetTimeBase(d scope.Duration) {
-	w.tb = d
-}
-
 func (w *waveform) SetChannel(ch scope.ChanID, p scope.TraceParams) {
-	if w.tp == nil {
-		w.tp = make(map[scope.ChanID]scope.TraceParams)
+	w.display.SetChannel(ch, float32(p.Zero), scope.Voltage(p.PerDiv))
+	if w.zoom != nil {
+		w.zoom.display.SetChannel(ch, float32(p.Zero), scope.Voltage(p.PerDiv))
+	}
+	if w.cursors != nil {
+		w.cursors.setTraceParams(ch, p)
 	}
-	w.tp[ch] = p
 }
 
 func (w *waveform) Render(ret *image.RGBA) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	gui.DrawOver(ret, w.plot.RGBA)
+	if img := w.display.Render(); img != nil {
+		w.displayImg = img
+	}
+	gui.DrawOver(ret, w.displayImg)
+	if w.zoom != nil {
+		if img := w.zoom.display.Render(); img != nil {
+			w.zoom.img = img
+		}
+		gui.DrawOver(ret, w.zoom.img)
+	}
 }
 
-type system struct {
-	name      string
-	enumerate func() map[string]string
-	open      func(string) (scope.Device, error)
-}
-
-var (
-	systems = []system{
-		{
-			name:      "dummy",
-			enumerate: dummy.Enumerate,
-			open:      dummy.Open,
-		},
-		{
-			name:      "usb",
-			enumerate: usb.Enumerate,
-			open:      usb.Open,
-		},
+func newSpectrumView(rect image.Rectangle) *spectrumView {
+	w, err := spectrum.ParseWindow(*specWindow)
+	if err != nil {
+		log.Fatalf("Invalid value of flag spectrum_window: %v", err)
 	}
-	systemsByName = make(map[string]int)
-)
+	ret := &spectrumView{
+		rect:   rect,
+		window: w,
+		params: gui.SpectrumParams{Ref: *specRef, PerDiv: *specPerDiv},
+		avg:    make(map[scope.ChanID]*spectrum.Averager),
+	}
+	switch *specScale {
+	case "dbv":
+		ret.params.Scale = spectrum.DBV
+	case "linear":
+		ret.params.Scale = spectrum.Linear
+	default:
+		log.Fatalf("Invalid value %q of flag spectrum_scale, want dbv or linear", *specScale)
+	}
+	return ret
+}
 
-func newWaveform(screenSize image.Point) *waveform {
+// newWaveform returns the waveform display of the channels chans.
+func newWaveform(screenSize image.Point, chans []scope.ChanID) *waveform {
 	p := gui.NewPlot(screenSize)
 	p.Fill(gui.ColorWhite)
-	for i := 1; i < gui.DivRows; i++ {
-		p.DrawLine(image.Point{0, i * screenSize.Y / gui.DivRows}, image.Point{screenSize.X, i * screenSize.Y / gui.DivRows}, p.Bounds(), gui.ColorGrey)
+	ret := &waveform{
+		timeRect: p.Bounds(),
+		plot:     gui.NewPlot(screenSize),
+		bufPlot:  gui.NewPlot(screenSize),
+		chColor:  make(map[scope.ChanID]color.RGBA),
 	}
-	for i := 1; i < gui.DivCols; i++ {
-		p.DrawLine(image.Point{i * screenSize.X / gui.DivCols, 0}, image.Point{i * screenSize.X / gui.DivCols, screenSize.Y}, p.Bounds(), gui.ColorGrey)
+	switch *view {
+	case "time":
+	case "spectrum":
+		ret.timeRect = image.Rectangle{}
+		ret.spec = newSpectrumView(p.Bounds())
+	case "split":
+		ret.timeRect.Max.Y = screenSize.Y / 2
+		ret.spec = newSpectrumView(image.Rect(0, screenSize.Y/2, screenSize.X, screenSize.Y))
+		p.DrawLine(image.Point{0, screenSize.Y / 2}, image.Point{screenSize.X, screenSize.Y / 2}, p.Bounds(), gui.ColorBlack)
+	default:
+		log.Fatalf("Invalid value %q of flag view, want time, spectrum or split", *view)
 	}
-	addLabel(p.RGBA, image.Point{10, 20}, fmt.Sprintf("%s/hdiv, %s/vdiv", *timePerDiv, scope.Voltage(*voltsPerDiv)))
-	ret := &waveform{
-		bgImage: p.RGBA,
-		plot:    gui.NewPlot(screenSize),
-		bufPlot: gui.NewPlot(screenSize),
+	var layers []scope.DisplayLayer
+	ret.labels = gui.NewAnnotationLayer(screenSize)
+	if *xy != "" && !ret.timeRect.Empty() {
+		x, y, err := gui.ParseXY(*xy, chans)
+		if err != nil {
+			log.Fatalf("Invalid value of flag xy: %v", err)
+		}
+		// the XY display has its own graticule, the time based
+		// graticule, cursors and zoom don't apply.
+		ret.traces = gui.NewXYLayer(screenSize, ret.timeRect, x, y)
+		layers = append(layers, ret.traces)
+	} else if *zoomWindow && !ret.timeRect.Empty() {
+		zoomRect := ret.timeRect
+		ret.timeRect.Max.Y = (zoomRect.Min.Y + zoomRect.Max.Y) / 2
+		zoomRect.Min.Y = ret.timeRect.Max.Y
+		p.DrawLine(image.Point{0, zoomRect.Min.Y}, image.Point{screenSize.X, zoomRect.Min.Y}, p.Bounds(), gui.ColorBlack)
+		ret.zoom = newZoomView(screenSize, zoomRect, ret.timeRect)
+		layers = append(layers, ret.zoom.marks)
+	}
+	if ret.traces == nil && !ret.timeRect.Empty() {
+		if *intensity {
+			decay, err := gui.ParseDecay(*persistence)
+			if err != nil {
+				log.Fatalf("Invalid value of flag persistence: %v", err)
+			}
+			ret.traces = gui.NewPersistence(screenSize, ret.timeRect, decay)
+		} else {
+			ret.traces = gui.NewTraceLayer(screenSize, ret.timeRect)
+		}
+		ret.graticule = gui.NewGraticuleLayer(screenSize, ret.timeRect)
+		ret.cursors = newCursorView(screenSize, ret.timeRect)
+		layers = append(layers, ret.traces, ret.graticule, ret.cursors.layer, ret.cursors.labels)
+	}
+	if ret.spec != nil {
+		layers = append(layers, gui.NewGridLayer(screenSize, ret.spec.rect))
 	}
+	layers = append(layers, ret.labels)
+	ret.display = gui.NewCompositor(screenSize, color.RGBA{}, layers...)
+	ret.bgImage = p.RGBA
 	copy(ret.plot.Pix, p.RGBA.Pix)
 	copy(ret.bufPlot.Pix, p.RGBA.Pix)
 	return ret
@@ -216,44 +358,14 @@ func newWaveform(screenSize image.Point) *waveform {
 func main() {
 	flag.Parse()
 
-	var all []string
-	for idx, sys := range systems {
-		systemsByName[sys.name] = idx
-		for id := range sys.enumerate() {
-			all = append(all, fmt.Sprintf("%s:%s", sys.name, id))
-		}
-	}
-
-	if len(all) == 0 {
-		log.Fatalf("Did not find any supported devices")
-	}
 	if *list {
 		fmt.Println("Devices found:")
-		for _, d := range all {
+		for _, d := range devices.List() {
 			fmt.Println(d)
 		}
 		return
 	}
-	id := all[0]
-	if *device != "" {
-		for _, d := range all {
-			if d == *device {
-				id = d
-				break
-			}
-		}
-		if id != *device {
-			log.Fatalf("Device %s not detected on the list. Available devices: %v", *device, all)
-		}
-	} else if len(all) > 1 {
-		log.Printf("Multiple devices found: %v", all)
-		log.Printf("Using the first device (%s)", id)
-	}
-
-	parts := strings.SplitN(id, ":", 2)
-	s := systemsByName[parts[0]]
-	osc, err := systems[s].open(parts[1])
-
+	osc, err := devices.Open(*device)
 	if err != nil {
 		log.Fatalf("Open: %+v", err)
 	}
@@ -266,24 +378,25 @@ func main() {
 		pprof.StartCPUProfile(f)
 		defer pprof.StopCPUProfile()
 	}
-	screenSize := image.Point{*screenWidth, *screenHeight}
-	wf := newWaveform(screenSize)
-	wf.SetTimeBase(scope.DurationFromNano(*timePerDiv * gui.DivCols))
-
-	for _, id := range osc.Channels() {
-		wf.SetChannel(id, scope.TraceParams{Zero: 0.5, PerDiv: *voltsPerDiv})
-	}
-
 	// Note: this is not useful long term, because it assumes that
 	// every device implements software triggers via triggers.Trigger.
 	// But it's good enough in the interim, before code is changed to use
 	// generic TriggerParams. See design doc for details.
 	tr := osc.(*triggers.Trigger)
+	// the math channels are added below the trigger, so that they
+	// can be used as the trigger source.
+	if len(mathChans) > 0 {
+		dev, err := mathChans.Wrap(tr.Device)
+		if err != nil {
+			log.Fatalf("Invalid value of flag math: %v", err)
+		}
+		tr = triggers.New(dev)
+		osc = tr
+	}
 	// For now, the names of params are hardcoded here, but in the future
 	// names might change between devices and it's not very practical.
-	// The intention is to have params initialized to defaults and then changed
-	// only through the UI or by specifying the parameter name and value
-	// on the commandline. But because there is no UI yet, it's not really feasible.
+	// The flags set only the initial values, the params can be changed
+	// later through the key bindings, see events.go.
 	for _, p := range tr.TriggerParams() {
 		var err error
 		switch pn := p.Name(); pn {
@@ -301,9 +414,52 @@ func main() {
 		}
 	}
 
-	osc.Attach(wf)
-	osc.Start()
-	defer osc.Stop()
+	var devParams []scope.Param
+	if *acqMode != "normal" {
+		ad := acquisition.New(osc)
+		devParams = append(devParams, ad.AcquisitionParams()...)
+		for _, p := range ad.AcquisitionParams() {
+			var err error
+			switch p.Name() {
+			case "acquisition":
+				err = p.Set(*acqMode)
+			case "averages":
+				err = p.Set(*averages)
+			case "decimation":
+				err = p.Set(*decimate)
+			}
+			if err != nil {
+				log.Fatalf("AcquisitionParams[%q].Set: %v", p.Name(), err)
+			}
+		}
+		osc = ad
+	}
+
+	if *bwLimit != "" {
+		cutoff, err := filter.ParseFrequency(*bwLimit)
+		if err != nil {
+			log.Fatalf("Invalid value of flag bw_limit: %v", err)
+		}
+		fd := filter.New(osc)
+		for _, ch := range osc.Channels() {
+			if err := fd.Set(ch, filter.BandwidthLimit(cutoff)); err != nil {
+				log.Fatalf("Set filter: %v", err)
+			}
+		}
+		osc = fd
+	}
+
+	screenSize := image.Point{*screenWidth, *screenHeight}
+	wf := newWaveform(screenSize, osc.Channels())
+	ctl := newControls(control.New(osc, wf, control.Config{
+		TimePerDiv:  scope.DurationFromNano(*timePerDiv),
+		VoltsPerDiv: *voltsPerDiv,
+		Trigger:     tr.TriggerParams(),
+		Other:       devParams,
+		RollAbove:   scope.DurationFromNano(*rollAbove),
+	}), wf)
+	ctl.start()
+	defer ctl.stop()
 
 	driver.Main(func(s screen.Screen) {
 		w, err := s.NewWindow(&screen.NewWindowOptions{Width: screenSize.X, Height: screenSize.Y})
@@ -312,7 +468,7 @@ func main() {
 		}
 		defer w.Release()
 		stop := make(chan struct{})
-		go processEvents(w, stop)
+		go processEvents(w, ctl, stop)
 
 		b, err := s.NewBuffer(screenSize)
 		if err != nil {
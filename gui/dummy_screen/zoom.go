@@ -0,0 +1,159 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/zagrodzki/goscope/gui"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// zoomView shows the magnified part of the sweep in a secondary window
+// and marks that part on the waveform.
+type zoomView struct {
+	traces    *gui.TraceLayer
+	graticule *gui.GraticuleLayer
+	display   *gui.Compositor
+	img       *image.RGBA
+	// marks shows the edges of the magnified part on the waveform.
+	marks *gui.CursorLayer
+}
+
+// newZoomView returns a zoomView rendering images of given size,
+// with the zoom window covering rect and the marks drawn within timeRect.
+func newZoomView(size image.Point, rect, timeRect image.Rectangle) *zoomView {
+	v := &zoomView{
+		traces:    gui.NewTraceLayer(size, rect),
+		graticule: gui.NewGraticuleLayer(size, rect),
+		marks:     gui.NewCursorLayer(size, timeRect),
+	}
+	v.graticule.SetState("Zoom")
+	v.marks.SetColor(gui.ColorGrey)
+	v.display = gui.NewCompositor(size, color.RGBA{}, v.traces, v.graticule)
+	return v
+}
+
+// setColor sets the color of channel ch.
+func (v *zoomView) setColor(ch scope.ChanID, col color.RGBA) {
+	v.traces.SetColor(ch, col)
+	v.graticule.SetColor(ch, col)
+}
+
+// show shows the part of the sweep data, sampled every interval,
+// selected by z.
+func (v *zoomView) show(data []scope.ChannelData, interval scope.Duration, z gui.Zoom) {
+	if len(data) == 0 {
+		return
+	}
+	begin, end := z.Range(len(data[0].Samples), interval)
+	v.marks.SetTimeCursors(scope.Duration(begin)*interval, scope.Duration(end-1)*interval)
+	v.display.SetTimeBase(scope.Duration(end-begin) * interval)
+	v.graticule.SetInterval(interval)
+	v.traces.Add(z.Apply(data, interval))
+}
+
+// setSweep records the full sweep data and displays it. While the acquisition
+// is stopped the held sweep is displayed magnified, unless there is
+// a zoom window.
+func (w *waveform) setSweep(data []scope.ChannelData) {
+	w.zoomMu.Lock()
+	defer w.zoomMu.Unlock()
+	w.last = w.last[:0]
+	for _, d := range data {
+		w.last = append(w.last, scope.ChannelData{ID: d.ID, Samples: append([]scope.Voltage(nil), d.Samples...)})
+	}
+	w.lastInter = w.inter
+	if w.zoom != nil {
+		w.zoom.show(w.last, w.lastInter, w.zoomSel)
+	}
+	if w.held && w.zoom == nil {
+		w.showHeld()
+		return
+	}
+	if w.traces != nil {
+		w.traces.Add(w.last)
+	}
+	if w.cursors != nil {
+		w.cursors.setSweep(w.last, w.lastInter)
+	}
+}
+
+// showHeld displays the part of the held sweep selected by the zoom
+// in the waveform display.
+// Must be called with w.zoomMu held.
+func (w *waveform) showHeld() {
+	if w.traces == nil || len(w.last) == 0 {
+		return
+	}
+	begin, end := w.zoomSel.Range(len(w.last[0].Samples), w.lastInter)
+	tb := scope.Duration(end-begin) * w.lastInter
+	data := w.zoomSel.Apply(w.last, w.lastInter)
+	w.traces.Add(data)
+	if w.graticule != nil {
+		w.graticule.SetTimeBase(tb)
+	}
+	if w.cursors != nil {
+		w.cursors.setTimeBase(tb)
+		w.cursors.setSweep(data, w.lastInter)
+	}
+}
+
+// setHeld sets whether the acquisition is stopped. The sweep acquired
+// last is held on the display, where it can be magnified with setZoom.
+func (w *waveform) setHeld(held bool) {
+	w.zoomMu.Lock()
+	defer w.zoomMu.Unlock()
+	w.held = held
+	w.pauseTraces()
+	if w.zoom != nil {
+		return
+	}
+	if held {
+		w.showHeld()
+		return
+	}
+	if w.graticule != nil {
+		w.graticule.SetTimeBase(w.tb)
+	}
+	if w.cursors != nil {
+		w.cursors.setTimeBase(w.tb)
+	}
+}
+
+// pauseTraces stops the intensity graded display from accumulating
+// the sweeps while they are magnified or incomplete, i.e. while the
+// acquisition is held or in the roll mode.
+// Must be called with w.zoomMu held.
+func (w *waveform) pauseTraces() {
+	if p, ok := w.traces.(*gui.Persistence); ok {
+		p.Pause(w.held || w.roll)
+	}
+}
+
+// setZoom selects the magnified part of the sweep, displayed in the zoom
+// window or, if there is none, in place of the held sweep.
+func (w *waveform) setZoom(z gui.Zoom) {
+	w.zoomMu.Lock()
+	defer w.zoomMu.Unlock()
+	w.zoomSel = z
+	switch {
+	case w.zoom != nil:
+		w.zoom.show(w.last, w.lastInter, z)
+	case w.held:
+		w.showHeld()
+	}
+}
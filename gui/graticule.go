@@ -0,0 +1,257 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+var showGraticule = flag.Bool("graticule", false, "draw the graticule with channel and trigger markers and readouts over the plot")
+
+const (
+	// markerSize is the length of the markers on the edges of the graticule, in pixels.
+	markerSize = 7
+	// ticksPerDiv is the number of minor ticks per division on the center axes.
+	ticksPerDiv = 5
+	tickColor   = 120
+)
+
+// ChannelMarker describes the display of a channel on the graticule.
+type ChannelMarker struct {
+	ID     scope.ChanID
+	Params scope.TraceParams
+	Color  color.RGBA
+}
+
+// TriggerMarker describes the trigger settings shown on the graticule.
+type TriggerMarker struct {
+	// Source is the trigger source channel.
+	Source scope.ChanID
+	Level  scope.Voltage
+	// Position of the trigger within the sweep, 0 is the left edge and
+	// 1 is the right edge of the graticule.
+	Position float64
+}
+
+// Graticule describes the graticule with the markers and readouts
+// drawn over the traces.
+type Graticule struct {
+	Rect image.Rectangle
+	// TimeBase is the length of the displayed sweep.
+	TimeBase scope.Duration
+	// Interval is the sampling interval. The sample rate is not shown if 0.
+	Interval scope.Duration
+	Channels []ChannelMarker
+	// Trigger is nil if the trigger is disabled.
+	Trigger *TriggerMarker
+	// State is the acquisition state, e.g. "Stop" or "Auto".
+	State string
+}
+
+// DrawGraticule draws the division lines with minor ticks on the center
+// axes, ground markers of the channels on the left edge, trigger level
+// marker on the right edge, trigger position marker on the top edge and
+// readouts: time per division, sample rate and acquisition state on the top,
+// volts per division of every channel in the channel color on the bottom.
+func (plot Plot) DrawGraticule(g Graticule) {
+	r := g.Rect
+	for i := 1; i < DivRows; i++ {
+		y := r.Min.Y + i*r.Dy()/DivRows
+		plot.DrawLine(image.Point{r.Min.X, y}, image.Point{r.Max.X, y}, r, ColorGrey)
+	}
+	for i := 1; i < DivCols; i++ {
+		x := r.Min.X + i*r.Dx()/DivCols
+		plot.DrawLine(image.Point{x, r.Min.Y}, image.Point{x, r.Max.Y}, r, ColorGrey)
+	}
+	tick := color.RGBA{tickColor, tickColor, tickColor, 255}
+	cx, cy := r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2
+	for i := 1; i < DivCols*ticksPerDiv; i++ {
+		x := r.Min.X + i*r.Dx()/(DivCols*ticksPerDiv)
+		plot.DrawLine(image.Point{x, cy - 2}, image.Point{x, cy + 2}, r, tick)
+	}
+	for i := 1; i < DivRows*ticksPerDiv; i++ {
+		y := r.Min.Y + i*r.Dy()/(DivRows*ticksPerDiv)
+		plot.DrawLine(image.Point{cx - 2, y}, image.Point{cx + 2, y}, r, tick)
+	}
+
+	for _, ch := range g.Channels {
+		plot.drawMarker(image.Point{r.Min.X, channelY(ch.Params, 0, r)}, image.Point{1, 0}, r, ch.Color)
+	}
+	if t := g.Trigger; t != nil {
+		col := ColorBlack
+		params := scope.TraceParams{Zero: defaultZero, PerDiv: defaultVoltsPerDiv}
+		for _, ch := range g.Channels {
+			if ch.ID == t.Source {
+				col, params = ch.Color, ch.Params
+			}
+		}
+		plot.drawMarker(image.Point{r.Max.X - 1, channelY(params, t.Level, r)}, image.Point{-1, 0}, r, col)
+		x := r.Min.X + round(math.Max(0, math.Min(1, t.Position))*float64(r.Dx()-1))
+		plot.drawMarker(image.Point{x, r.Min.Y}, image.Point{0, 1}, r, col)
+	}
+
+	top := []string{fmt.Sprintf("%s/div", g.TimeBase/DivCols)}
+	if g.Interval > 0 {
+		top = append(top, formatSI(float64(scope.Second)/float64(g.Interval), "S/s"))
+	}
+	pad := 2 * markerSize
+	drawText(plot.RGBA, image.Point{r.Min.X + pad, r.Min.Y + pad + readoutFontSize}, strings.Join(top, "  "), ColorBlack, readoutFontSize)
+	if g.State != "" {
+		x := r.Max.X - pad - textWidth(g.State, readoutFontSize)
+		drawText(plot.RGBA, image.Point{x, r.Min.Y + pad + readoutFontSize}, g.State, ColorBlack, readoutFontSize)
+	}
+	x := r.Min.X + pad
+	for _, ch := range g.Channels {
+		text := fmt.Sprintf("%s %s/div", ch.ID, formatSI(ch.Params.PerDiv, "V"))
+		x += drawText(plot.RGBA, image.Point{x, r.Max.Y - pad}, text, ch.Color, readoutFontSize) + pad
+	}
+}
+
+// channelY returns the vertical position of voltage v of a channel
+// with trace params tp, limited to rect.
+func channelY(tp scope.TraceParams, v scope.Voltage, rect image.Rectangle) int {
+	y := round(newPointMapper(2, tp, rect).y(v))
+	return max(rect.Min.Y, min(y, rect.Max.Y-1))
+}
+
+// drawMarker draws a filled triangle with the base centered at p,
+// pointing in direction dir, which is one of (1,0), (-1,0), (0,1) or (0,-1).
+func (plot Plot) drawMarker(p, dir image.Point, rect image.Rectangle, col color.RGBA) {
+	for k := 0; k < markerSize; k++ {
+		h := markerSize - 1 - k
+		c := p.Add(dir.Mul(k))
+		perp := image.Point{dir.Y, dir.X}
+		plot.DrawLine(c.Sub(perp.Mul(h)), c.Add(perp.Mul(h)), rect, col)
+	}
+}
+
+// formatSI formats v with a metric prefix and unit, e.g. 0.5 V as "500mV".
+func formatSI(v float64, unit string) string {
+	if v == 0 {
+		return "0" + unit
+	}
+	mul, prefix := 1e-9, "n"
+	for _, p := range []struct {
+		mul    float64
+		prefix string
+	}{{1e9, "G"}, {1e6, "M"}, {1e3, "k"}, {1, ""}, {1e-3, "m"}, {1e-6, "µ"}} {
+		if math.Abs(v) >= p.mul {
+			mul, prefix = p.mul, p.prefix
+			break
+		}
+	}
+	s := strings.TrimRight(fmt.Sprintf("%.3f", v/mul), "0")
+	return strings.TrimSuffix(s, ".") + prefix + unit
+}
+
+// GraticuleLayer draws the graticule with the markers and readouts.
+// GraticuleLayer implements scope.DisplayLayer.
+type GraticuleLayer struct {
+	layer
+	order    []scope.ChanID
+	cols     map[scope.ChanID]color.RGBA
+	interval scope.Duration
+	trigger  *TriggerMarker
+	state    string
+}
+
+// NewGraticuleLayer returns a GraticuleLayer rendering images of given size,
+// with the graticule covering rect.
+func NewGraticuleLayer(size image.Point, rect image.Rectangle) *GraticuleLayer {
+	g := &GraticuleLayer{
+		cols: make(map[scope.ChanID]color.RGBA),
+	}
+	g.init(size, rect)
+	return g
+}
+
+// SetChannel configures the display parameters for channel ch.
+// Channels are shown in the order they were configured.
+func (g *GraticuleLayer) SetChannel(ch scope.ChanID, screenPos float32, perDiv scope.Voltage) {
+	g.layer.SetChannel(ch, screenPos, perDiv)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, c := range g.order {
+		if c == ch {
+			return
+		}
+	}
+	g.order = append(g.order, ch)
+}
+
+// SetColor sets the color of the markers and readouts of channel ch.
+func (g *GraticuleLayer) SetColor(ch scope.ChanID, col color.RGBA) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cols[ch] = col
+	g.dirty = true
+}
+
+// SetInterval sets the sampling interval, used for the sample rate readout.
+func (g *GraticuleLayer) SetInterval(i scope.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.interval = i
+	g.dirty = true
+}
+
+// SetTrigger sets the trigger markers, nil removes them.
+func (g *GraticuleLayer) SetTrigger(t *TriggerMarker) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.trigger = nil
+	if t != nil {
+		tc := *t
+		g.trigger = &tc
+	}
+	g.dirty = true
+}
+
+// SetState sets the acquisition state readout.
+func (g *GraticuleLayer) SetState(s string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.state = s
+	g.dirty = true
+}
+
+// Render returns the image of the graticule, or nil if it did not change
+// since the last Render.
+func (g *GraticuleLayer) Render() *image.RGBA {
+	return g.render(func() {
+		gr := Graticule{
+			Rect:     g.rect,
+			TimeBase: g.tb,
+			Interval: g.interval,
+			Trigger:  g.trigger,
+			State:    g.state,
+		}
+		for _, ch := range g.order {
+			col, ok := g.cols[ch]
+			if !ok {
+				col = ColorBlack
+			}
+			gr.Channels = append(gr.Channels, ChannelMarker{ID: ch, Params: g.traceParams(ch), Color: col})
+		}
+		g.plot.DrawGraticule(gr)
+	})
+}
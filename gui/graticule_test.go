@@ -0,0 +1,127 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+func TestFormatSI(t *testing.T) {
+	for _, tc := range []struct {
+		v    float64
+		unit string
+		want string
+	}{
+		{0, "V", "0V"},
+		{0.5, "V", "500mV"},
+		{2, "V", "2V"},
+		{1e6, "S/s", "1MS/s"},
+		{1.5e3, "S/s", "1.5kS/s"},
+		{-0.00025, "V", "-250µV"},
+		{1e-12, "s", "0.001ns"},
+	} {
+		if got := formatSI(tc.v, tc.unit); got != tc.want {
+			t.Errorf("formatSI(%v, %q): got %q, want %q", tc.v, tc.unit, got, tc.want)
+		}
+	}
+}
+
+// hasColor returns true if any pixel of img within r has color col.
+func hasColor(img *image.RGBA, r image.Rectangle, col color.RGBA) bool {
+	for x := r.Min.X; x < r.Max.X; x++ {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			if img.RGBAAt(x, y) == col {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestDrawGraticule(t *testing.T) {
+	plot := Plot{RGBA: image.NewRGBA(image.Rect(0, 0, 401, 321))}
+	plot.Fill(ColorWhite)
+	plot.DrawGraticule(Graticule{
+		Rect:     plot.Bounds(),
+		TimeBase: 10 * scope.Millisecond,
+		Interval: scope.Microsecond,
+		Channels: []ChannelMarker{
+			{ID: "CH1", Params: scope.TraceParams{Zero: 0.5, PerDiv: 1}, Color: ColorRed},
+			{ID: "CH2", Params: scope.TraceParams{Zero: 0.25, PerDiv: 1}, Color: ColorBlue},
+		},
+		Trigger: &TriggerMarker{Source: "CH2", Level: 2, Position: 0.5},
+		State:   "Auto",
+	})
+	for _, tc := range []struct {
+		desc string
+		p    image.Point
+		want color.RGBA
+	}{
+		{"grid line", image.Point{40, 100}, ColorGrey},
+		{"minor tick", image.Point{200, 8}, color.RGBA{tickColor, tickColor, tickColor, 255}},
+		{"CH1 ground marker", image.Point{0, 160}, ColorRed},
+		{"CH1 ground marker tip", image.Point{markerSize - 1, 160}, ColorRed},
+		{"CH2 ground marker", image.Point{2, 240}, ColorBlue},
+		// CH2 zero is at 1/4 of the height, 2V is 2 divisions above, in the middle.
+		{"trigger level marker", image.Point{400, 160}, ColorBlue},
+		{"trigger position marker", image.Point{200, 0}, ColorBlue},
+		{"trigger position marker tip", image.Point{200, markerSize - 1}, ColorBlue},
+		{"empty area", image.Point{100, 130}, ColorWhite},
+	} {
+		if got := plot.RGBAAt(tc.p.X, tc.p.Y); got != tc.want {
+			t.Errorf("%s: pixel %v: got %v, want %v", tc.desc, tc.p, got, tc.want)
+		}
+	}
+	// the readouts are drawn in the channel colors at the bottom,
+	// anti-aliased text has some pixels of exactly the drawing color.
+	bottom := image.Rect(0, 280, 401, 321)
+	for _, col := range []color.RGBA{ColorRed, ColorBlue} {
+		if !hasColor(plot.RGBA, bottom, col) {
+			t.Errorf("readout in color %v not found at the bottom of the graticule", col)
+		}
+	}
+	if !hasColor(plot.RGBA, image.Rect(300, 0, 401, 40), ColorBlack) {
+		t.Errorf("state readout not found in the top right corner")
+	}
+}
+
+func TestGraticuleLayer(t *testing.T) {
+	g := NewGraticuleLayer(layerSize, layerRect)
+	g.SetChannel("CH1", 0.5, 1)
+	g.SetChannel("CH2", 0.5, 1)
+	g.SetChannel("CH1", 0.75, 1)
+	if want := []scope.ChanID{"CH1", "CH2"}; len(g.order) != 2 || g.order[0] != want[0] || g.order[1] != want[1] {
+		t.Errorf("channel order: got %v, want %v", g.order, want)
+	}
+	g.SetColor("CH1", ColorGreen)
+	img := g.Render()
+	if img == nil {
+		t.Fatalf("Render(): got nil, want the graticule")
+	}
+	if got := img.RGBAAt(0, 20); got != ColorGreen {
+		t.Errorf("CH1 ground marker: got %v, want %v", got, ColorGreen)
+	}
+	if g.Render() != nil {
+		t.Errorf("second Render(): got an image, want nil")
+	}
+	g.SetState("Stop")
+	if g.Render() == nil {
+		t.Errorf("Render() after SetState: got nil, want an image")
+	}
+}
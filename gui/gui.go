@@ -76,62 +76,123 @@ func (p *aggrPoint) add(y float64) {
 	p.sizeY++
 }
 
-func (p *aggrPoint) toPoint(x int) image.Point {
-	return image.Point{x, round(p.sumY / float64(p.sizeY))}
+func (p *aggrPoint) y() float64 {
+	return p.sumY / float64(p.sizeY)
 }
 
-func samplesToPoints(samples []scope.Voltage, traceParams scope.TraceParams, rect image.Rectangle) []image.Point {
-	if len(samples) == 0 {
-		return nil
-	}
+// pointMapper maps the sample indices and values to the pixels
+// of the plot rectangle.
+type pointMapper struct {
+	pixelStartX float64
+	pixelEndY   float64
+	ratioX      float64
+	ratioY      float64
+	sampleMinY  float64
+}
 
+func newPointMapper(numSamples int, traceParams scope.TraceParams, rect image.Rectangle) pointMapper {
 	sampleMaxY := (1 - traceParams.Zero) * DivRows * traceParams.PerDiv
 	sampleMinY := -traceParams.Zero * DivRows * traceParams.PerDiv
-	sampleWidthX := float64(len(samples) - 1)
+	sampleWidthX := float64(numSamples - 1)
 	sampleWidthY := sampleMaxY - sampleMinY
 
-	pixelStartX := float64(rect.Min.X)
-	pixelEndY := float64(rect.Max.Y - 1)
 	pixelWidthX := float64(rect.Dx() - 1)
 	pixelWidthY := float64(rect.Dy() - 1)
-	ratioX := pixelWidthX / sampleWidthX
-	ratioY := pixelWidthY / sampleWidthY
+	return pointMapper{
+		pixelStartX: float64(rect.Min.X),
+		pixelEndY:   float64(rect.Max.Y - 1),
+		ratioX:      pixelWidthX / sampleWidthX,
+		ratioY:      pixelWidthY / sampleWidthY,
+		sampleMinY:  sampleMinY,
+	}
+}
+
+func (m pointMapper) x(i int) int {
+	return round(m.pixelStartX + float64(i)*m.ratioX)
+}
+
+func (m pointMapper) y(v scope.Voltage) float64 {
+	return m.pixelEndY - float64(v-scope.Voltage(m.sampleMinY))*m.ratioY
+}
+
+// SamplesToPoints maps the samples to the points of the trace drawn within
+// rect, one point per column of rect, averaging the samples falling into
+// the same column. The samples are spread across the whole width of rect.
+func SamplesToPoints(samples []scope.Voltage, traceParams scope.TraceParams, rect image.Rectangle) []image.Point {
+	return samplesToPoints(samples, traceParams, rect)
+}
 
-	points := make([]image.Point, rect.Dx())
+func samplesToPoints(samples []scope.Voltage, traceParams scope.TraceParams, rect image.Rectangle) []image.Point {
+	points := make([]image.Point, 0, rect.Dx())
+	mapSamples(samples, traceParams, rect, func(x int, y float64) {
+		points = append(points, image.Point{x, round(y)})
+	})
+	return points
+}
+
+// mapSamples maps the samples to the columns of rect and calls point
+// with every column and the average y of the samples falling into it,
+// from left to right.
+func mapSamples(samples []scope.Voltage, traceParams scope.TraceParams, rect image.Rectangle, point func(x int, y float64)) {
+	if len(samples) == 0 {
+		return
+	}
+
+	m := newPointMapper(len(samples), traceParams, rect)
 	lastAggr := aggrPoint{}
 	lastX := rect.Min.X
-	pi := 0
 	for i, y := range samples {
-		mapX := round(pixelStartX + float64(i)*ratioX)
-		mapY := pixelEndY - float64(y-scope.Voltage(sampleMinY))*ratioY
+		mapX := m.x(i)
+		mapY := m.y(y)
 		if lastX != mapX {
-			points[pi] = lastAggr.toPoint(lastX)
-			pi++
+			point(lastX, lastAggr.y())
 			lastX = mapX
 			lastAggr = aggrPoint{}
 		}
 		lastAggr.add(mapY)
 	}
-	points[pi] = lastAggr.toPoint(lastX)
-	pi++
-
-	return points[:pi]
+	point(lastX, lastAggr.y())
 }
 
 // Plot represents the entire plotting area.
 type Plot struct {
 	*image.RGBA
 	interp Interpolator
+	down   Downsampler
+	line   LineDrawing
+	width  float64
+	// graticule enables drawing the graticule in DrawFromDevice.
+	graticule bool
 }
 
 // NewPlot returns a new Plot of specified size.
 func NewPlot(p image.Point) Plot {
 	return Plot{
-		image.NewRGBA(image.Rect(0, 0, p.X, p.Y)),
-		interpolator(),
+		RGBA:      image.NewRGBA(image.Rect(0, 0, p.X, p.Y)),
+		interp:    interpolator(),
+		down:      downsampler(),
+		line:      lineDrawing(),
+		width:     *lineWidth,
+		graticule: *showGraticule,
 	}
 }
 
+// WithDownsampler returns a copy of the plot, drawing into the same image,
+// that uses the downsampling strategy d.
+func (plot Plot) WithDownsampler(d Downsampler) Plot {
+	plot.down = d
+	return plot
+}
+
+// WithLineDrawing returns a copy of the plot, drawing into the same image,
+// that draws the traces using algorithm l and lines of given width.
+// The width is only used by LineAntiAliased.
+func (plot Plot) WithLineDrawing(l LineDrawing, width float64) Plot {
+	plot.line = l
+	plot.width = width
+	return plot
+}
+
 var (
 	bgCache *image.RGBA
 	bgColor color.RGBA
@@ -162,47 +223,38 @@ func isInside(x, y int, rect image.Rectangle) bool {
 	return x >= rect.Min.X && x <= rect.Max.X && y >= rect.Min.Y && y <= rect.Max.Y
 }
 
-// DrawLine draws a straight line from pixel p1 to p2.
+// DrawLine draws a straight line from pixel p1 to p2
+// using Bresenham's line algorithm.
 // Only the line fragment inside the image rectangle defined by
 // starting (upper left) and ending (lower right) pixel is drawn.
 func (plot Plot) DrawLine(p1, p2 image.Point, rect image.Rectangle, col color.RGBA) {
-	if p1.X == p2.X { // vertical line
-		for i := min(p1.Y, p2.Y); i <= max(p1.Y, p2.Y); i++ {
-			plot.SetRGBA(p1.X, i, col)
-		}
-		return
+	dx, dy := abs(p2.X-p1.X), -abs(p2.Y-p1.Y)
+	sx, sy := 1, 1
+	if p1.X > p2.X {
+		sx = -1
 	}
-
-	// Calculating the parameters of the equation
-	// of the straight line (in the form y=a*x+b)
-	// passing through p1 and p2.
-
-	// slope of the line
-	a := float64(p1.Y-p2.Y) / float64(p1.X-p2.X)
-	// intercept of the line
-	b := float64(p1.Y) - float64(p1.X)*a
-
-	// To avoid visual "gaps" between the pixels we switch on,
-	// we draw the line in one of two ways.
-	if abs(p1.X-p2.X) >= abs(p1.Y-p2.Y) {
-		// If the line is more horizontal than vertical,
-		// for every pixel column between p1 and p2
-		// we find and switch on the pixel closest to y=a*x+b
-		for i := min(p1.X, p2.X); i <= max(p1.X, p2.X); i++ {
-			y := round(a*float64(i) + b)
-			if isInside(i, y, rect) {
-				plot.SetRGBA(i, y, col)
-			}
+	if p1.Y > p2.Y {
+		sy = -1
+	}
+	// err is the accumulated error of the next pixel, scaled by 2*dx*dy
+	// to keep the computations in integers.
+	err := dx + dy
+	x, y := p1.X, p1.Y
+	for {
+		if isInside(x, y, rect) {
+			plot.SetRGBA(x, y, col)
+		}
+		if x == p2.X && y == p2.Y {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x += sx
 		}
-	} else {
-		// If the line is more vertical than horizontal,
-		// for every pixel row between p1 and p2
-		// we find and switch on the pixel closest to y=a*x+b
-		for i := min(p1.Y, p2.Y); i <= max(p1.Y, p2.Y); i++ {
-			x := round((float64(i) - b) / a)
-			if isInside(x, i, rect) {
-				plot.SetRGBA(x, i, col)
-			}
+		if e2 <= dx {
+			err += dx
+			y += sy
 		}
 	}
 }
@@ -217,13 +269,25 @@ func (plot Plot) DrawSamples(samples []scope.Voltage, traceParams scope.TracePar
 		}
 		samples = interpSamples
 	}
-	points := samplesToPoints(samples, traceParams, rect)
-	for i := 1; i < len(points); i++ {
-		plot.DrawLine(points[i-1], points[i], rect, col)
+	switch plot.down {
+	case DownsampleMinMax:
+		plot.drawEnvelope(samplesToSpans(samples, traceParams, rect), rect, col)
+		return nil
+	case DownsampleLTTB:
+		points := indexedSamplesToPoints(samples, lttb(samples, rect.Dx()), traceParams, rect)
+		plot.drawPolyline(points, rect, col)
+		return nil
 	}
+	plot.drawPolyline(samplesToPoints(samples, traceParams, rect), rect, col)
 	return nil
 }
 
+func (plot Plot) drawPolyline(points []image.Point, rect image.Rectangle, col color.RGBA) {
+	for i := 1; i < len(points); i++ {
+		plot.drawTraceLine(points[i-1], points[i], rect, col)
+	}
+}
+
 // DrawAll draws samples from all the channels in the plot.
 func (plot Plot) DrawAll(data []scope.ChannelData, traceParams map[scope.ChanID]scope.TraceParams, cols map[scope.ChanID]color.RGBA) error {
 	b := plot.Bounds()
@@ -244,21 +308,57 @@ func (plot Plot) DrawAll(data []scope.ChannelData, traceParams map[scope.ChanID]
 	return nil
 }
 
+// channelMarker returns the marker of the channel ch with its trace params
+// and color, or the defaults if they are missing in traceParams and cols.
+func channelMarker(ch scope.ChanID, traceParams map[scope.ChanID]scope.TraceParams, cols map[scope.ChanID]color.RGBA) ChannelMarker {
+	m := ChannelMarker{
+		ID:     ch,
+		Params: scope.TraceParams{Zero: defaultZero, PerDiv: defaultVoltsPerDiv},
+		Color:  ColorBlack,
+	}
+	if p, ok := traceParams[ch]; ok {
+		m.Params = p
+	}
+	if c, ok := cols[ch]; ok {
+		m.Color = c
+	}
+	return m
+}
+
 // DrawFromDevice draws samples from the device in the plot.
 func (plot Plot) DrawFromDevice(dev scope.Device, traceParams map[scope.ChanID]scope.TraceParams, cols map[scope.ChanID]color.RGBA) error {
 	rec := &compat.Recorder{TB: scope.Millisecond}
 	dev.Attach(rec)
 	dev.Start()
 	defer dev.Stop()
-	samples := (<-rec.Data).Channels
-	return plot.DrawAll(samples, traceParams, cols)
+	data := <-rec.Data
+	if err := plot.DrawAll(data.Channels, traceParams, cols); err != nil {
+		return err
+	}
+	if plot.graticule {
+		g := Graticule{
+			Rect:     plot.Bounds(),
+			TimeBase: scope.Duration(data.Num) * data.Interval,
+			Interval: data.Interval,
+			State:    "Stop",
+		}
+		for _, ch := range data.Channels {
+			g.Channels = append(g.Channels, channelMarker(ch.ID, traceParams, cols))
+		}
+		plot.DrawGraticule(g)
+	}
+	return nil
 }
 
 // CreatePlot plots samples from the device.
 func CreatePlot(dev scope.Device, width, height int, traceParams map[scope.ChanID]scope.TraceParams, cols map[scope.ChanID]color.RGBA) (Plot, error) {
 	plot := Plot{
-		RGBA:   image.NewRGBA(image.Rect(0, 0, width, height)),
-		interp: SincInterpolator,
+		RGBA:      image.NewRGBA(image.Rect(0, 0, width, height)),
+		interp:    SincInterpolator,
+		down:      downsampler(),
+		line:      lineDrawing(),
+		width:     *lineWidth,
+		graticule: *showGraticule,
 	}
 	err := plot.DrawFromDevice(dev, traceParams, cols)
 	return plot, err
@@ -23,6 +23,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/zagrodzki/goscope/dummy"
@@ -231,8 +232,8 @@ func TestPlot(t *testing.T) {
 		}
 
 		testPlot := Plot{
-			image.NewRGBA(image.Rect(0, 0, 800, 600)),
-			tc.interp,
+			RGBA:   image.NewRGBA(image.Rect(0, 0, 800, 600)),
+			interp: tc.interp,
 		}
 		testPlot.Fill(ColorWhite)
 		b := testPlot.Bounds()
@@ -244,6 +245,273 @@ func TestPlot(t *testing.T) {
 	}
 }
 
+func TestDownsampling(t *testing.T) {
+	samples := make([]scope.Voltage, 100000)
+	samples[30000] = 1
+	samples[70000] = -1
+	file, err := os.Open("spikes-gp.png")
+	if err != nil {
+		t.Fatalf("Cannot open file spikes-gp.png: %v", err)
+	}
+	refPlot, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("Cannot decode file spikes-gp.png: %v", err)
+	}
+	for _, tc := range []struct {
+		desc string
+		down Downsampler
+	}{
+		{"minmax", DownsampleMinMax},
+		{"lttb", DownsampleLTTB},
+	} {
+		testPlot := Plot{
+			RGBA:   image.NewRGBA(image.Rect(0, 0, 800, 600)),
+			interp: LinearInterpolator,
+			down:   tc.down,
+		}
+		testPlot.Fill(ColorWhite)
+		b := testPlot.Bounds()
+		testPlot.DrawSamples(samples, scope.TraceParams{0.5, 0.25}, b, ColorBlack)
+		if err := evaluatePlot(refPlot, testPlot, 1000); err != nil {
+			t.Errorf("error in evaluating plot %v against spikes-gp.png: %v", tc.desc, err)
+		}
+		// the spikes reach the top and the bottom edge of the plot.
+		for _, p := range []image.Point{{240, 0}, {559, 599}} {
+			if !isOn(testPlot, p.X, p.Y) {
+				t.Errorf("%s: spike point %v is not marked on the plot", tc.desc, p)
+			}
+		}
+	}
+}
+
+func TestLTTB(t *testing.T) {
+	samples := []scope.Voltage{0, 0, 0, 5, 0, 0, 0, -5, 0, 0, 0}
+	if got, want := lttb(samples, 4), []int{0, 3, 7, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("lttb(%v, 4): got %v, want %v", samples, got, want)
+	}
+	if got, want := lttb(samples[:3], 4), []int{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("lttb(%v, 4): got %v, want %v", samples[:3], got, want)
+	}
+}
+
+func onPixels(img *image.RGBA) map[image.Point]bool {
+	ret := make(map[image.Point]bool)
+	b := img.Bounds()
+	for x := b.Min.X; x < b.Max.X; x++ {
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			if isOn(img, x, y) {
+				ret[image.Point{x, y}] = true
+			}
+		}
+	}
+	return ret
+}
+
+func TestDrawLine(t *testing.T) {
+	for _, tc := range []struct {
+		desc   string
+		p1, p2 image.Point
+		rect   image.Rectangle
+		want   []image.Point
+	}{
+		{
+			desc: "horizontal",
+			p1:   image.Point{1, 2},
+			p2:   image.Point{4, 2},
+			want: []image.Point{{1, 2}, {2, 2}, {3, 2}, {4, 2}},
+		},
+		{
+			desc: "vertical, upwards",
+			p1:   image.Point{3, 4},
+			p2:   image.Point{3, 1},
+			want: []image.Point{{3, 1}, {3, 2}, {3, 3}, {3, 4}},
+		},
+		{
+			desc: "diagonal",
+			p1:   image.Point{0, 0},
+			p2:   image.Point{3, 3},
+			want: []image.Point{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+		},
+		{
+			desc: "shallow",
+			p1:   image.Point{0, 0},
+			p2:   image.Point{6, 2},
+			want: []image.Point{{0, 0}, {1, 0}, {2, 1}, {3, 1}, {4, 1}, {5, 2}, {6, 2}},
+		},
+		{
+			desc: "steep, right to left",
+			p1:   image.Point{2, 0},
+			p2:   image.Point{0, 6},
+			want: []image.Point{{2, 0}, {2, 1}, {1, 2}, {1, 3}, {1, 4}, {0, 5}, {0, 6}},
+		},
+		{
+			desc: "clipped to the rectangle",
+			p1:   image.Point{0, 5},
+			p2:   image.Point{9, 5},
+			rect: image.Rect(3, 0, 5, 9),
+			want: []image.Point{{3, 5}, {4, 5}, {5, 5}},
+		},
+	} {
+		plot := Plot{RGBA: image.NewRGBA(image.Rect(0, 0, 10, 10))}
+		plot.Fill(ColorWhite)
+		rect := tc.rect
+		if rect.Empty() {
+			rect = plot.Bounds()
+		}
+		plot.DrawLine(tc.p1, tc.p2, rect, ColorBlack)
+		want := make(map[image.Point]bool)
+		for _, p := range tc.want {
+			want[p] = true
+		}
+		if got := onPixels(plot.RGBA); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: DrawLine(%v, %v): got pixels %v, want %v", tc.desc, tc.p1, tc.p2, got, want)
+		}
+	}
+}
+
+func TestDrawLineNoGaps(t *testing.T) {
+	plot := Plot{RGBA: image.NewRGBA(image.Rect(0, 0, 100, 100))}
+	for _, p2 := range []image.Point{{99, 0}, {99, 37}, {99, 99}, {61, 99}, {0, 99}, {13, 2}} {
+		plot.Fill(ColorWhite)
+		plot.DrawLine(image.Point{0, 0}, p2, plot.Bounds(), ColorBlack)
+		got := onPixels(plot.RGBA)
+		if want := max(p2.X, p2.Y) + 1; len(got) != want {
+			t.Errorf("DrawLine((0,0), %v): got %d pixels, want %d", p2, len(got), want)
+		}
+		for _, p := range []image.Point{{0, 0}, p2} {
+			if !got[p] {
+				t.Errorf("DrawLine((0,0), %v): end point %v is not marked", p2, p)
+			}
+		}
+		for p := range got {
+			if p == p2 {
+				continue
+			}
+			// every pixel except the last one has a neighbour closer to the end.
+			found := false
+			for dx := 0; dx <= 1; dx++ {
+				for dy := 0; dy <= 1; dy++ {
+					found = found || (dx+dy > 0 && got[image.Point{p.X + dx, p.Y + dy}])
+				}
+			}
+			if !found {
+				t.Errorf("DrawLine((0,0), %v): gap after pixel %v", p2, p)
+			}
+		}
+	}
+}
+
+func TestDrawLineAA(t *testing.T) {
+	half := color.RGBA{128, 128, 128, 255}
+	for _, tc := range []struct {
+		desc   string
+		p1, p2 image.Point
+		width  float64
+		bg     color.RGBA
+		col    color.RGBA
+		want   map[image.Point]color.RGBA
+	}{
+		{
+			desc:  "horizontal, width 1",
+			p1:    image.Point{2, 5},
+			p2:    image.Point{4, 5},
+			width: 1,
+			bg:    ColorWhite,
+			col:   ColorBlack,
+			want: map[image.Point]color.RGBA{
+				{2, 4}: ColorWhite, {2, 5}: ColorBlack, {2, 6}: ColorWhite,
+				{3, 5}: ColorBlack, {4, 5}: ColorBlack, {5, 5}: ColorWhite,
+			},
+		},
+		{
+			desc:  "vertical, width 3",
+			p1:    image.Point{5, 2},
+			p2:    image.Point{5, 4},
+			width: 3,
+			bg:    ColorWhite,
+			col:   ColorBlack,
+			want: map[image.Point]color.RGBA{
+				{3, 3}: ColorWhite, {4, 3}: ColorBlack, {5, 3}: ColorBlack, {6, 3}: ColorBlack, {7, 3}: ColorWhite,
+				{5, 1}: ColorWhite, {5, 5}: ColorWhite,
+			},
+		},
+		{
+			desc:  "horizontal, width 2 covers half of the edge pixels",
+			p1:    image.Point{2, 5},
+			p2:    image.Point{4, 5},
+			width: 2,
+			bg:    ColorWhite,
+			col:   ColorBlack,
+			want: map[image.Point]color.RGBA{
+				{3, 3}: ColorWhite, {3, 4}: half, {3, 5}: ColorBlack, {3, 6}: half, {3, 7}: ColorWhite,
+			},
+		},
+		{
+			desc:  "blended with the background",
+			p1:    image.Point{2, 5},
+			p2:    image.Point{4, 5},
+			width: 2,
+			bg:    ColorRed,
+			col:   ColorBlue,
+			want: map[image.Point]color.RGBA{
+				{3, 4}: {128, 0, 128, 255}, {3, 5}: ColorBlue,
+			},
+		},
+		{
+			desc:  "diagonal, exact pixel centers",
+			p1:    image.Point{0, 0},
+			p2:    image.Point{3, 3},
+			width: 1,
+			bg:    ColorWhite,
+			col:   ColorBlack,
+			want: map[image.Point]color.RGBA{
+				{0, 0}: ColorBlack, {1, 1}: ColorBlack, {2, 2}: ColorBlack, {3, 3}: ColorBlack,
+				{1, 3}: ColorWhite, {3, 1}: ColorWhite,
+			},
+		},
+	} {
+		plot := Plot{RGBA: image.NewRGBA(image.Rect(0, 0, 10, 10))}
+		plot.Fill(tc.bg)
+		plot.DrawLineAA(tc.p1, tc.p2, plot.Bounds(), tc.col, tc.width)
+		for p, want := range tc.want {
+			if got := plot.RGBAAt(p.X, p.Y); got != want {
+				t.Errorf("%s: pixel %v: got %v, want %v", tc.desc, p, got, want)
+			}
+		}
+	}
+
+	// a line passing between pixel centers splits the intensity evenly.
+	plot := Plot{RGBA: image.NewRGBA(image.Rect(0, 0, 20, 20))}
+	plot.Fill(ColorWhite)
+	plot.DrawLineAA(image.Point{0, 0}, image.Point{10, 5}, plot.Bounds(), ColorBlack, 1)
+	c1, c2 := plot.RGBAAt(1, 0), plot.RGBAAt(1, 1)
+	if c1 != c2 || c1 == ColorWhite || c1 == ColorBlack {
+		t.Errorf("DrawLineAA((0,0), (10,5)): got pixels (1,0)=%v and (1,1)=%v, want equal shades of grey", c1, c2)
+	}
+}
+
+func BenchmarkDrawLine(b *testing.B) {
+	plot := Plot{RGBA: image.NewRGBA(image.Rect(0, 0, 800, 600))}
+	for _, bc := range []struct {
+		name  string
+		line  LineDrawing
+		width float64
+	}{
+		{"bresenham", LineBresenham, 1},
+		{"wu", LineAntiAliased, 1},
+		{"wu-3px", LineAntiAliased, 3},
+	} {
+		p := plot.WithLineDrawing(bc.line, bc.width)
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for x := 0; x < 800; x += 20 {
+					p.drawTraceLine(image.Point{x, 0}, image.Point{799 - x, 599}, p.Bounds(), ColorBlack)
+				}
+			}
+		})
+	}
+}
+
 func TestPlotToPng(t *testing.T) {
 	dev, err := dummy.Open("")
 	if err != nil {
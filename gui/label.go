@@ -0,0 +1,86 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"image"
+	"image/color"
+	"log"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	labelFontSize   = 20
+	readoutFontSize = 14
+)
+
+var (
+	fontMu    sync.Mutex
+	labelFont *truetype.Font
+	faces     = make(map[float64]font.Face)
+)
+
+// fontFace returns the face of the label font of given size.
+// Faces are cached and are not safe for concurrent use,
+// fontMu must be held while using a face.
+func fontFace(size float64) font.Face {
+	if f, ok := faces[size]; ok {
+		return f
+	}
+	if labelFont == nil {
+		f, err := truetype.Parse(goregular.TTF)
+		if err != nil {
+			log.Fatalf("truetype.Parse(goregular): %v", err)
+		}
+		labelFont = f
+	}
+	f := truetype.NewFace(labelFont, &truetype.Options{
+		Size: size,
+	})
+	faces[size] = f
+	return f
+}
+
+// drawText draws text of given size with the baseline starting at origin
+// and returns the width of the text in pixels.
+func drawText(img *image.RGBA, origin image.Point, text string, col color.RGBA, size float64) int {
+	fontMu.Lock()
+	defer fontMu.Unlock()
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(col),
+		Face: fontFace(size),
+		Dot:  fixed.P(origin.X, origin.Y),
+	}
+	d.DrawString(text)
+	return (d.Dot.X - fixed.I(origin.X)).Ceil()
+}
+
+// textWidth returns the width in pixels of text of given size.
+func textWidth(text string, size float64) int {
+	fontMu.Lock()
+	defer fontMu.Unlock()
+	return font.MeasureString(fontFace(size), text).Ceil()
+}
+
+// DrawLabel draws the text label with the baseline starting at origin.
+func DrawLabel(img *image.RGBA, origin image.Point, label string, col color.RGBA) {
+	drawText(img, origin, label, col, labelFontSize)
+}
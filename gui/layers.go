@@ -0,0 +1,397 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"image"
+	"image/color"
+	"sync"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// layer holds the state shared by the display layers: the timebase,
+// the display params of the channels and the image, which is redrawn
+// only after the layer changed.
+type layer struct {
+	mu    sync.Mutex
+	tb    scope.Duration
+	tp    map[scope.ChanID]scope.TraceParams
+	rect  image.Rectangle
+	plot  Plot
+	dirty bool
+}
+
+func (l *layer) init(size image.Point, rect image.Rectangle) {
+	l.tp = make(map[scope.ChanID]scope.TraceParams)
+	l.rect = rect
+	l.plot = NewPlot(size)
+	l.dirty = true
+}
+
+// TimeBase returns the length of the displayed sweeps.
+func (l *layer) TimeBase() scope.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tb
+}
+
+// SetTimeBase sets the length of the displayed sweeps.
+func (l *layer) SetTimeBase(d scope.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tb = d
+	l.dirty = true
+}
+
+// SetChannel configures the display parameters for channel ch.
+func (l *layer) SetChannel(ch scope.ChanID, screenPos float32, perDiv scope.Voltage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tp[ch] = scope.TraceParams{Zero: float64(screenPos), PerDiv: float64(perDiv)}
+	l.dirty = true
+}
+
+// traceParams returns the display params of channel ch.
+// Must be called with l.mu held.
+func (l *layer) traceParams(ch scope.ChanID) scope.TraceParams {
+	if tp, ok := l.tp[ch]; ok {
+		return tp
+	}
+	return scope.TraceParams{Zero: defaultZero, PerDiv: defaultVoltsPerDiv}
+}
+
+// render clears the image and calls draw to redraw it, if the layer
+// changed since the last call. Otherwise it returns nil.
+func (l *layer) render(draw func()) *image.RGBA {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.dirty {
+		return nil
+	}
+	l.dirty = false
+	for i := range l.plot.Pix {
+		l.plot.Pix[i] = 0
+	}
+	draw()
+	return l.plot.RGBA
+}
+
+// GridLayer draws the division lines.
+// GridLayer implements scope.DisplayLayer.
+type GridLayer struct {
+	layer
+}
+
+// NewGridLayer returns a GridLayer rendering images of given size,
+// with the grid covering rect.
+func NewGridLayer(size image.Point, rect image.Rectangle) *GridLayer {
+	g := &GridLayer{}
+	g.init(size, rect)
+	return g
+}
+
+// Render returns the image of the grid, or nil if it did not change
+// since the last Render.
+func (g *GridLayer) Render() *image.RGBA {
+	return g.render(func() {
+		r := g.rect
+		for i := 1; i < DivRows; i++ {
+			y := r.Min.Y + i*r.Dy()/DivRows
+			g.plot.DrawLine(image.Point{r.Min.X, y}, image.Point{r.Max.X, y}, g.plot.Bounds(), ColorGrey)
+		}
+		for i := 1; i < DivCols; i++ {
+			x := r.Min.X + i*r.Dx()/DivCols
+			g.plot.DrawLine(image.Point{x, r.Min.Y}, image.Point{x, r.Max.Y}, g.plot.Bounds(), ColorGrey)
+		}
+	})
+}
+
+// TraceLayer draws the most recent sweep of every channel.
+// TraceLayer implements scope.DisplayLayer.
+type TraceLayer struct {
+	layer
+	cols map[scope.ChanID]color.RGBA
+	data []scope.ChannelData
+	// length is the number of samples of a full sweep,
+	// 0 if the sweeps are always full.
+	length int
+}
+
+// NewTraceLayer returns a TraceLayer rendering images of given size,
+// with the traces drawn within rect.
+func NewTraceLayer(size image.Point, rect image.Rectangle) *TraceLayer {
+	t := &TraceLayer{
+		cols: make(map[scope.ChanID]color.RGBA),
+	}
+	t.init(size, rect)
+	return t
+}
+
+// SetColor sets the color of the trace of channel ch.
+func (t *TraceLayer) SetColor(ch scope.ChanID, col color.RGBA) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cols[ch] = col
+	t.dirty = true
+}
+
+// SetSweepLength sets the number of samples of a full sweep. Shorter
+// sweeps are drawn at the right edge of the rectangle, occupying
+// the part of it proportional to their length, as in the roll mode.
+// 0 means that every sweep is drawn across the whole rectangle.
+func (t *TraceLayer) SetSweepLength(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.length = n
+	t.dirty = true
+}
+
+// sweepRect returns the part of the rectangle occupied by a sweep
+// of n samples.
+// Must be called with t.mu held.
+func (t *TraceLayer) sweepRect(n int) image.Rectangle {
+	r := t.rect
+	if t.length <= 1 || n >= t.length {
+		return r
+	}
+	w := round(float64(r.Dx()-1) * float64(n-1) / float64(t.length-1))
+	r.Min.X = r.Max.X - 1 - w
+	return r
+}
+
+// Add replaces the displayed sweep with data. The samples are copied.
+func (t *TraceLayer) Add(data []scope.ChannelData) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.data = t.data[:0]
+	for _, d := range data {
+		t.data = append(t.data, scope.ChannelData{
+			ID:      d.ID,
+			Samples: append([]scope.Voltage(nil), d.Samples...),
+		})
+	}
+	t.dirty = true
+}
+
+// Render returns the image of the traces, or nil if it did not change
+// since the last Render.
+func (t *TraceLayer) Render() *image.RGBA {
+	return t.render(func() {
+		for _, d := range t.data {
+			if len(d.Samples) == 0 {
+				continue
+			}
+			r := t.sweepRect(len(d.Samples))
+			if r.Dx() < 2 {
+				continue
+			}
+			col, ok := t.cols[d.ID]
+			if !ok {
+				col = ColorBlack
+			}
+			t.plot.DrawSamples(d.Samples, t.traceParams(d.ID), r, col)
+		}
+	})
+}
+
+// cursorDash is the length of the dashes of the cursor lines, in pixels.
+const cursorDash = 4
+
+// CursorLayer draws the time cursors as dashed vertical lines and
+// the voltage cursors of the channels as dashed horizontal lines.
+// CursorLayer implements scope.DisplayLayer.
+type CursorLayer struct {
+	layer
+	col   color.RGBA
+	times []scope.Duration
+	volts map[scope.ChanID][]scope.Voltage
+}
+
+// NewCursorLayer returns a CursorLayer rendering images of given size,
+// with the cursors drawn within rect.
+func NewCursorLayer(size image.Point, rect image.Rectangle) *CursorLayer {
+	c := &CursorLayer{
+		col:   ColorPurple,
+		volts: make(map[scope.ChanID][]scope.Voltage),
+	}
+	c.init(size, rect)
+	return c
+}
+
+// SetColor sets the color of the cursors.
+func (c *CursorLayer) SetColor(col color.RGBA) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.col = col
+	c.dirty = true
+}
+
+// SetTimeCursors sets the positions of the time cursors,
+// relative to the beginning of the sweep.
+func (c *CursorLayer) SetTimeCursors(ts ...scope.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.times = append([]scope.Duration(nil), ts...)
+	c.dirty = true
+}
+
+// SetVoltageCursors sets the positions of the voltage cursors of channel ch.
+func (c *CursorLayer) SetVoltageCursors(ch scope.ChanID, vs ...scope.Voltage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.volts[ch] = append([]scope.Voltage(nil), vs...)
+	c.dirty = true
+}
+
+// Render returns the image of the cursors, or nil if it did not change
+// since the last Render.
+func (c *CursorLayer) Render() *image.RGBA {
+	return c.render(func() {
+		r := c.rect
+		if c.tb > 0 {
+			for _, t := range c.times {
+				x := r.Min.X + round(float64(t)/float64(c.tb)*float64(r.Dx()-1))
+				for y := r.Min.Y; y < r.Max.Y; y++ {
+					if (y-r.Min.Y)/cursorDash%2 == 0 && isInside(x, y, r) {
+						c.plot.SetRGBA(x, y, c.col)
+					}
+				}
+			}
+		}
+		for ch, vs := range c.volts {
+			m := newPointMapper(2, c.traceParams(ch), r)
+			for _, v := range vs {
+				y := round(m.y(v))
+				for x := r.Min.X; x < r.Max.X; x++ {
+					if (x-r.Min.X)/cursorDash%2 == 0 && isInside(x, y, r) {
+						c.plot.SetRGBA(x, y, c.col)
+					}
+				}
+			}
+		}
+	})
+}
+
+// Label is a text drawn on the display.
+type Label struct {
+	// Pos is the beginning of the baseline of the text.
+	Pos   image.Point
+	Text  string
+	Color color.RGBA
+}
+
+// AnnotationLayer draws text labels.
+// AnnotationLayer implements scope.DisplayLayer.
+type AnnotationLayer struct {
+	layer
+	labels []Label
+}
+
+// NewAnnotationLayer returns an AnnotationLayer rendering images of given size.
+func NewAnnotationLayer(size image.Point) *AnnotationLayer {
+	a := &AnnotationLayer{}
+	a.init(size, image.Rectangle{Max: size})
+	return a
+}
+
+// SetLabels replaces the displayed labels.
+func (a *AnnotationLayer) SetLabels(labels ...Label) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.labels = append([]Label(nil), labels...)
+	a.dirty = true
+}
+
+// Render returns the image of the labels, or nil if it did not change
+// since the last Render.
+func (a *AnnotationLayer) Render() *image.RGBA {
+	return a.render(func() {
+		for _, l := range a.labels {
+			DrawLabel(a.plot.RGBA, l.Pos, l.Text, l.Color)
+		}
+	})
+}
+
+// Compositor draws a stack of display layers over a background, in order.
+// The images of the layers are cached, a layer returning a nil image
+// is not redrawn and the composited image is updated only if at least
+// one of the layers changed.
+// Compositor implements scope.DisplayLayer, passing the timebase and
+// the channel params to all the layers.
+type Compositor struct {
+	tb     scope.Duration
+	bg     *image.RGBA
+	out    *image.RGBA
+	layers []scope.DisplayLayer
+	images []*image.RGBA
+}
+
+// NewCompositor returns a Compositor rendering images of given size,
+// drawing the layers over a background of color bg.
+// A transparent bg results in a transparent image where none
+// of the layers draws.
+func NewCompositor(size image.Point, bg color.RGBA, layers ...scope.DisplayLayer) *Compositor {
+	r := image.Rectangle{Max: size}
+	return &Compositor{
+		bg:     background(r, bg),
+		out:    image.NewRGBA(r),
+		layers: layers,
+		images: make([]*image.RGBA, len(layers)),
+	}
+}
+
+// TimeBase returns the length of the displayed sweeps.
+func (c *Compositor) TimeBase() scope.Duration {
+	return c.tb
+}
+
+// SetTimeBase sets the length of the displayed sweeps in all the layers.
+func (c *Compositor) SetTimeBase(d scope.Duration) {
+	c.tb = d
+	for _, l := range c.layers {
+		l.SetTimeBase(d)
+	}
+}
+
+// SetChannel configures the display parameters for channel ch in all the layers.
+func (c *Compositor) SetChannel(ch scope.ChanID, screenPos float32, perDiv scope.Voltage) {
+	for _, l := range c.layers {
+		l.SetChannel(ch, screenPos, perDiv)
+	}
+}
+
+// Render returns the composited image of all the layers, or nil if none
+// of the layers changed since the last Render.
+// The returned image is reused by the following calls to Render.
+func (c *Compositor) Render() *image.RGBA {
+	dirty := false
+	for i, l := range c.layers {
+		if img := l.Render(); img != nil {
+			c.images[i] = img
+			dirty = true
+		}
+	}
+	if !dirty {
+		return nil
+	}
+	copy(c.out.Pix, c.bg.Pix)
+	for _, img := range c.images {
+		if img != nil {
+			DrawOver(c.out, img)
+		}
+	}
+	return c.out
+}
@@ -0,0 +1,208 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+var (
+	layerSize = image.Point{100, 81}
+	layerRect = image.Rectangle{Max: layerSize}
+)
+
+func TestGridLayer(t *testing.T) {
+	g := NewGridLayer(layerSize, layerRect)
+	img := g.Render()
+	if img == nil {
+		t.Fatalf("first Render(): got nil, want the grid")
+	}
+	for _, tc := range []struct {
+		p    image.Point
+		want color.RGBA
+	}{
+		{image.Point{5, 10}, ColorGrey},
+		{image.Point{10, 5}, ColorGrey},
+		{image.Point{5, 5}, color.RGBA{}},
+		{image.Point{0, 0}, color.RGBA{}},
+	} {
+		if got := img.RGBAAt(tc.p.X, tc.p.Y); got != tc.want {
+			t.Errorf("pixel %v: got %v, want %v", tc.p, got, tc.want)
+		}
+	}
+	if g.Render() != nil {
+		t.Errorf("second Render(): got an image, want nil")
+	}
+}
+
+func TestTraceLayer(t *testing.T) {
+	tl := NewTraceLayer(layerSize, layerRect)
+	tl.SetChannel("signal", 0.5, 0.25)
+	tl.SetColor("signal", ColorRed)
+	data := constSweep(0)
+	tl.Add(data)
+	// modifying the data after Add doesn't change the layer.
+	data[0].Samples[50] = 1
+	img := tl.Render()
+	if img == nil {
+		t.Fatalf("Render() after Add: got nil, want the traces")
+	}
+	if got := img.RGBAAt(50, 40); got != ColorRed {
+		t.Errorf("pixel (50,40): got %v, want %v", got, ColorRed)
+	}
+	if got := img.RGBAAt(50, 0); got != (color.RGBA{}) {
+		t.Errorf("pixel (50,0): got %v, want transparent", got)
+	}
+	if tl.Render() != nil {
+		t.Errorf("second Render(): got an image, want nil")
+	}
+	tl.SetChannel("signal", 0.75, 0.25)
+	img = tl.Render()
+	if img == nil {
+		t.Fatalf("Render() after SetChannel: got nil, want the traces")
+	}
+	if got := img.RGBAAt(50, 20); got != ColorRed {
+		t.Errorf("pixel (50,20) after moving the trace up: got %v, want %v", got, ColorRed)
+	}
+	if got := img.RGBAAt(50, 40); got != (color.RGBA{}) {
+		t.Errorf("pixel (50,40) after moving the trace up: got %v, want transparent", got)
+	}
+}
+
+func TestTraceLayerSweepLength(t *testing.T) {
+	tl := NewTraceLayer(layerSize, layerRect)
+	tl.SetChannel("signal", 0.5, 0.25)
+	tl.SetColor("signal", ColorRed)
+	tl.SetSweepLength(200)
+	// half of the sweep is drawn in the right half of the rectangle.
+	tl.Add(constSweep(0))
+	img := tl.Render()
+	if img == nil {
+		t.Fatalf("Render(): got nil, want the traces")
+	}
+	if !hasColor(img, image.Rect(55, 40, 99, 41), ColorRed) {
+		t.Errorf("Render(): want the trace in the right half of row 40")
+	}
+	if hasColor(img, image.Rect(0, 0, 45, 81), ColorRed) {
+		t.Errorf("Render(): got the trace in the left half, want none")
+	}
+}
+
+func TestCursorLayer(t *testing.T) {
+	c := NewCursorLayer(layerSize, layerRect)
+	c.SetTimeBase(99 * scope.Millisecond)
+	c.SetChannel("signal", 0.5, 0.25)
+	c.SetTimeCursors(33 * scope.Millisecond)
+	c.SetVoltageCursors("signal", 0.5)
+	img := c.Render()
+	if img == nil {
+		t.Fatalf("Render(): got nil, want the cursors")
+	}
+	for _, tc := range []struct {
+		p    image.Point
+		want color.RGBA
+	}{
+		// time cursor, dashed.
+		{image.Point{33, 0}, ColorPurple},
+		{image.Point{33, 4}, color.RGBA{}},
+		{image.Point{33, 8}, ColorPurple},
+		// voltage cursor, dashed.
+		{image.Point{0, 20}, ColorPurple},
+		{image.Point{5, 20}, color.RGBA{}},
+		{image.Point{34, 21}, color.RGBA{}},
+	} {
+		if got := img.RGBAAt(tc.p.X, tc.p.Y); got != tc.want {
+			t.Errorf("pixel %v: got %v, want %v", tc.p, got, tc.want)
+		}
+	}
+}
+
+func TestAnnotationLayer(t *testing.T) {
+	a := NewAnnotationLayer(layerSize)
+	a.SetLabels(Label{Pos: image.Point{5, 30}, Text: "CH1", Color: ColorBlack})
+	img := a.Render()
+	if img == nil {
+		t.Fatalf("Render(): got nil, want the labels")
+	}
+	drawn := 0
+	for x := 0; x < layerSize.X; x++ {
+		for y := 0; y < layerSize.Y; y++ {
+			if img.RGBAAt(x, y).A != 0 {
+				if x < 5 || y > 35 {
+					t.Fatalf("pixel (%d,%d) of the label outside of the expected area", x, y)
+				}
+				drawn++
+			}
+		}
+	}
+	if drawn == 0 {
+		t.Errorf("no pixels of the label drawn")
+	}
+}
+
+// countingLayer wraps a DisplayLayer and counts the non-nil images returned by Render.
+type countingLayer struct {
+	scope.DisplayLayer
+	renders int
+}
+
+func (c *countingLayer) Render() *image.RGBA {
+	img := c.DisplayLayer.Render()
+	if img != nil {
+		c.renders++
+	}
+	return img
+}
+
+func TestCompositor(t *testing.T) {
+	grid := &countingLayer{DisplayLayer: NewGridLayer(layerSize, layerRect)}
+	traces := NewTraceLayer(layerSize, layerRect)
+	c := NewCompositor(layerSize, ColorWhite, grid, traces)
+	c.SetTimeBase(scope.Millisecond)
+	c.SetChannel("signal", 0.5, 0.25)
+	if c.TimeBase() != scope.Millisecond {
+		t.Errorf("TimeBase(): got %v, want %v", c.TimeBase(), scope.Millisecond)
+	}
+	if c.Render() == nil {
+		t.Fatalf("first Render(): got nil, want an image")
+	}
+	if c.Render() != nil {
+		t.Errorf("Render() without changes: got an image, want nil")
+	}
+	traces.Add(constSweep(0.5))
+	img := c.Render()
+	if img == nil {
+		t.Fatalf("Render() after Add: got nil, want an image")
+	}
+	if grid.renders != 1 {
+		t.Errorf("grid rendered %d times, want 1", grid.renders)
+	}
+	for _, tc := range []struct {
+		p    image.Point
+		want color.RGBA
+	}{
+		{image.Point{50, 20}, ColorBlack},
+		{image.Point{5, 10}, ColorGrey},
+		{image.Point{5, 5}, ColorWhite},
+	} {
+		if got := img.RGBAAt(tc.p.X, tc.p.Y); got != tc.want {
+			t.Errorf("pixel %v: got %v, want %v", tc.p, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"log"
+	"math"
+)
+
+// LineDrawing selects the algorithm used to draw the traces.
+type LineDrawing int
+
+const (
+	// LineBresenham draws one pixel wide aliased lines with integer
+	// Bresenham's algorithm. It's the fastest.
+	LineBresenham LineDrawing = iota
+	// LineAntiAliased draws anti-aliased lines of configurable width with
+	// Xiaolin Wu's algorithm, blending the pixels with the background.
+	LineAntiAliased
+)
+
+var (
+	lineType  = flag.String("line_drawing", "bresenham", "line drawing algorithm: one of bresenham, wu (anti-aliased)")
+	lineWidth = flag.Float64("line_width", 1, "width of the traces in pixels, used with -line_drawing=wu")
+)
+
+func lineDrawing() LineDrawing {
+	switch *lineType {
+	case "bresenham":
+		return LineBresenham
+	case "wu":
+		return LineAntiAliased
+	}
+	log.Fatalf("Invalid value %q for flag \"line_drawing\", want one of: bresenham, wu", *lineType)
+	return LineBresenham
+}
+
+// drawTraceLine draws a segment of a trace, with the algorithm
+// configured for the plot.
+func (plot Plot) drawTraceLine(p1, p2 image.Point, rect image.Rectangle, col color.RGBA) {
+	if plot.line == LineAntiAliased {
+		plot.DrawLineAA(p1, p2, rect, col, plot.width)
+		return
+	}
+	plot.DrawLine(p1, p2, rect, col)
+}
+
+// DrawLineAA draws an anti-aliased straight line of given width
+// from pixel p1 to p2, using Xiaolin Wu's algorithm extended to
+// thick lines: in every column (or row, for steep lines) the pixels
+// are blended with col proportionally to the part of the pixel covered
+// by the line.
+// Only the line fragment inside the image rectangle defined by
+// starting (upper left) and ending (lower right) pixel is drawn.
+func (plot Plot) DrawLineAA(p1, p2 image.Point, rect image.Rectangle, col color.RGBA, width float64) {
+	x0, y0, x1, y1 := p1.X, p1.Y, p2.X, p2.Y
+	steep := abs(y1-y0) > abs(x1-x0)
+	if steep {
+		x0, y0, x1, y1 = y0, x0, y1, x1
+	}
+	if x0 > x1 {
+		x0, y0, x1, y1 = x1, y1, x0, y0
+	}
+	var grad float64
+	if x1 != x0 {
+		grad = float64(y1-y0) / float64(x1-x0)
+	}
+	// half of the line extent along the minor axis.
+	half := width * math.Sqrt(1+grad*grad) / 2
+	for x := x0; x <= x1; x++ {
+		yc := float64(y0) + grad*float64(x-x0)
+		lo, hi := yc-half, yc+half
+		// pixel y covers the range [y-0.5, y+0.5).
+		for y := int(math.Floor(lo + 0.5)); float64(y)-0.5 < hi; y++ {
+			cover := math.Min(hi, float64(y)+0.5) - math.Max(lo, float64(y)-0.5)
+			if steep {
+				plot.blend(y, x, rect, col, cover)
+			} else {
+				plot.blend(x, y, rect, col, cover)
+			}
+		}
+	}
+}
+
+// blend mixes the color of pixel x,y with col, with col weighted by a.
+func (plot Plot) blend(x, y int, rect image.Rectangle, col color.RGBA, a float64) {
+	if a <= 0 || !isInside(x, y, rect) || !(image.Point{x, y}).In(plot.Bounds()) {
+		return
+	}
+	if a >= 1 {
+		plot.SetRGBA(x, y, col)
+		return
+	}
+	bg := plot.RGBAAt(x, y)
+	mix := func(b, f uint8) uint8 {
+		return uint8(float64(b)*(1-a) + float64(f)*a + 0.5)
+	}
+	plot.SetRGBA(x, y, color.RGBA{mix(bg.R, col.R), mix(bg.G, col.G), mix(bg.B, col.B), mix(bg.A, col.A)})
+}
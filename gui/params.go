@@ -0,0 +1,221 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// StepParam changes the param to the next (up is true) or the previous
+// setting and returns the new value. RangeParams are changed through
+// Inc and Dec, SelectParams are set to the neighbouring entry of Values,
+// stopping at the first and the last entry.
+func StepParam(p scope.Param, up bool) (string, error) {
+	switch v := p.(type) {
+	case scope.RangeParam:
+		if up {
+			return v.Inc(), nil
+		}
+		return v.Dec(), nil
+	case scope.SelectParam:
+		vals := v.Values()
+		cur := -1
+		for i, s := range vals {
+			if s == v.Value() {
+				cur = i
+			}
+		}
+		next := cur - 1
+		if up {
+			next = cur + 1
+		}
+		if next < 0 || next >= len(vals) {
+			return v.Value(), nil
+		}
+		if err := v.Set(vals[next]); err != nil {
+			return v.Value(), err
+		}
+		return v.Value(), nil
+	}
+	return p.Value(), fmt.Errorf("param %s can't be changed step by step", p.Name())
+}
+
+// CycleParam sets the param to the entry of Values following (forward is true)
+// or preceding the current one, wrapping around at the first and the last
+// entry, and returns the new value.
+func CycleParam(p scope.SelectParam, forward bool) (string, error) {
+	vals := p.Values()
+	if len(vals) == 0 {
+		return p.Value(), fmt.Errorf("param %s has no values", p.Name())
+	}
+	step := len(vals) - 1
+	if forward {
+		step = 1
+	}
+	next := 0
+	for i, s := range vals {
+		if s == p.Value() {
+			next = (i + step) % len(vals)
+		}
+	}
+	if err := p.Set(vals[next]); err != nil {
+		return p.Value(), err
+	}
+	return p.Value(), nil
+}
+
+// ScaleParam is a param selecting a value from the 1-2-5 sequence,
+// like the time/div and volts/div knobs of an oscilloscope.
+// ScaleParam implements both scope.SelectParam and scope.RangeParam.
+type ScaleParam struct {
+	name string
+	unit string
+	vals []float64
+	idx  int
+}
+
+// NewScaleParam returns a ScaleParam with values of the 1-2-5 sequence
+// between min and max, set to the value closest to v.
+// The values are displayed with the unit.
+func NewScaleParam(name, unit string, min, max, v float64) *ScaleParam {
+	p := &ScaleParam{name: name, unit: unit}
+	for e := math.Floor(math.Log10(min)); ; e++ {
+		for _, m := range []float64{1, 2, 5} {
+			x := m * math.Pow(10, e)
+			if x > max*(1+1e-9) {
+				p.setFloat(v)
+				return p
+			}
+			if x >= min*(1-1e-9) {
+				p.vals = append(p.vals, x)
+			}
+		}
+	}
+}
+
+func (p *ScaleParam) setFloat(v float64) {
+	best := math.Inf(1)
+	for i, x := range p.vals {
+		if d := math.Abs(math.Log(x / v)); d < best {
+			best, p.idx = d, i
+		}
+	}
+}
+
+// Name returns the name of the param.
+func (p *ScaleParam) Name() string { return p.name }
+
+// Value returns the current value with a metric prefix and the unit.
+func (p *ScaleParam) Value() string { return formatSI(p.vals[p.idx], p.unit) }
+
+// Float returns the current value.
+func (p *ScaleParam) Float() float64 { return p.vals[p.idx] }
+
+// Values returns all the values of the param.
+func (p *ScaleParam) Values() []string {
+	ret := make([]string, len(p.vals))
+	for i, x := range p.vals {
+		ret[i] = formatSI(x, p.unit)
+	}
+	return ret
+}
+
+// Set sets the value of the param, either one of Values
+// or a number, which is rounded to the closest of Values.
+func (p *ScaleParam) Set(v string) error {
+	for i, s := range p.Values() {
+		if s == v {
+			p.idx = i
+			return nil
+		}
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f <= 0 {
+		return fmt.Errorf("invalid %s %q, must be a positive number or one of %v", p.name, v, p.Values())
+	}
+	p.setFloat(f)
+	return nil
+}
+
+// Inc selects the next larger value and returns it.
+func (p *ScaleParam) Inc() string {
+	if p.idx < len(p.vals)-1 {
+		p.idx++
+	}
+	return p.Value()
+}
+
+// Dec selects the next smaller value and returns it.
+func (p *ScaleParam) Dec() string {
+	if p.idx > 0 {
+		p.idx--
+	}
+	return p.Value()
+}
+
+// PositionParam is the vertical position of the channel zero on the screen,
+// displayed in divisions from the center of the screen.
+// PositionParam implements scope.RangeParam.
+type PositionParam struct {
+	name string
+	// pos is the position in the range 0 (bottom) to 1 (top of the screen).
+	pos float64
+}
+
+// positionStep is the change of PositionParam by Inc or Dec, one minor tick.
+const positionStep = 1.0 / (DivRows * ticksPerDiv)
+
+// NewPositionParam returns a PositionParam set to pos, where 0 is the bottom
+// and 1 is the top of the screen.
+func NewPositionParam(name string, pos float64) *PositionParam {
+	return &PositionParam{name: name, pos: pos}
+}
+
+// Name returns the name of the param.
+func (p *PositionParam) Name() string { return p.name }
+
+// Value returns the position in divisions from the center of the screen.
+func (p *PositionParam) Value() string {
+	return fmt.Sprintf("%.1fdiv", (p.pos-0.5)*DivRows)
+}
+
+// Position returns the position in the range 0 (bottom) to 1 (top of the screen).
+func (p *PositionParam) Position() float64 { return p.pos }
+
+// Set sets the position, in divisions from the center of the screen.
+func (p *PositionParam) Set(v string) error {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %v", p.name, v, err)
+	}
+	p.pos = math.Max(0, math.Min(1, f/DivRows+0.5))
+	return nil
+}
+
+// Inc moves the position up by one minor tick and returns the new value.
+func (p *PositionParam) Inc() string {
+	p.pos = math.Min(1, p.pos+positionStep)
+	return p.Value()
+}
+
+// Dec moves the position down by one minor tick and returns the new value.
+func (p *PositionParam) Dec() string {
+	p.pos = math.Max(0, p.pos-positionStep)
+	return p.Value()
+}
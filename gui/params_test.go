@@ -0,0 +1,123 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScaleParam(t *testing.T) {
+	p := NewScaleParam("volts/div", "V", 0.01, 1, 0.3)
+	if got, want := p.Values(), []string{"10mV", "20mV", "50mV", "100mV", "200mV", "500mV", "1V"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Values(): got %v, want %v", got, want)
+	}
+	if got, want := p.Value(), "200mV"; got != want {
+		t.Errorf("Value() after NewScaleParam(0.3): got %q, want %q", got, want)
+	}
+	for _, tc := range []struct {
+		up   bool
+		want string
+	}{
+		{true, "500mV"},
+		{true, "1V"},
+		{true, "1V"},
+		{false, "500mV"},
+	} {
+		got, err := StepParam(p, tc.up)
+		if err != nil {
+			t.Fatalf("StepParam(%v): %v", tc.up, err)
+		}
+		if got != tc.want {
+			t.Errorf("StepParam(%v): got %q, want %q", tc.up, got, tc.want)
+		}
+	}
+	if got, want := p.Float(), 0.5; got != want {
+		t.Errorf("Float(): got %v, want %v", got, want)
+	}
+	if err := p.Set("20mV"); err != nil || p.Float() != 0.02 {
+		t.Errorf("Set(20mV): got %v, err %v, want 0.02", p.Float(), err)
+	}
+	if err := p.Set("0.09"); err != nil || p.Float() != 0.1 {
+		t.Errorf("Set(0.09): got %v, err %v, want 0.1", p.Float(), err)
+	}
+	if err := p.Set("foo"); err == nil {
+		t.Errorf("Set(foo): got nil error, want non-nil")
+	}
+}
+
+func TestPositionParam(t *testing.T) {
+	p := NewPositionParam("offset", 0.5)
+	if got, want := p.Value(), "0.0div"; got != want {
+		t.Errorf("Value(): got %q, want %q", got, want)
+	}
+	for i := 0; i < 5; i++ {
+		p.Inc()
+	}
+	if got, want := p.Value(), "1.0div"; got != want {
+		t.Errorf("Value() after 5x Inc(): got %q, want %q", got, want)
+	}
+	if err := p.Set("-10"); err != nil {
+		t.Fatalf("Set(-10): %v", err)
+	}
+	if got, want := p.Position(), 0.0; got != want {
+		t.Errorf("Position() after Set(-10): got %v, want %v", got, want)
+	}
+	if got, want := p.Dec(), "-4.0div"; got != want {
+		t.Errorf("Dec() at the bottom: got %q, want %q", got, want)
+	}
+}
+
+type fakeSelect struct {
+	v    string
+	vals []string
+}
+
+func (f *fakeSelect) Name() string       { return "fake" }
+func (f *fakeSelect) Value() string      { return f.v }
+func (f *fakeSelect) Values() []string   { return f.vals }
+func (f *fakeSelect) Set(v string) error { f.v = v; return nil }
+
+func TestStepSelectParam(t *testing.T) {
+	p := &fakeSelect{v: "b", vals: []string{"a", "b", "c"}}
+	for _, tc := range []struct {
+		up   bool
+		want string
+	}{
+		{true, "c"},
+		{true, "c"},
+		{false, "b"},
+		{false, "a"},
+		{false, "a"},
+	} {
+		if got, _ := StepParam(p, tc.up); got != tc.want {
+			t.Errorf("StepParam(%v): got %q, want %q", tc.up, got, tc.want)
+		}
+	}
+}
+
+func TestCycleParam(t *testing.T) {
+	p := &fakeSelect{v: "b", vals: []string{"a", "b", "c"}}
+	for _, want := range []string{"c", "a", "b"} {
+		if got, _ := CycleParam(p, true); got != want {
+			t.Errorf("CycleParam(true): got %q, want %q", got, want)
+		}
+	}
+	for _, want := range []string{"a", "c", "b"} {
+		if got, _ := CycleParam(p, false); got != want {
+			t.Errorf("CycleParam(false): got %q, want %q", got, want)
+		}
+	}
+}
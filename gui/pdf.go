@@ -0,0 +1,117 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// pdfColor returns the PDF color components of col.
+func pdfColor(col color.RGBA) string {
+	return fmt.Sprintf("%.3f %.3f %.3f", float64(col.R)/255, float64(col.G)/255, float64(col.B)/255)
+}
+
+// pdfString returns the text as a PDF string literal in the WinAnsi
+// encoding of the standard Helvetica font. Characters missing in
+// the encoding are replaced with '?'.
+func pdfString(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r < 0x7f:
+			b.WriteRune(r)
+		case r == 'µ' || r == '°':
+			// the same code in WinAnsi as in Latin-1.
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// pdfContent returns the content stream drawing the shapes. PDF places
+// the origin in the bottom left corner, the y coordinates are flipped.
+func (v *VectorPlot) pdfContent() []byte {
+	var b bytes.Buffer
+	h := v.Size.Y
+	fmt.Fprintf(&b, "%s rg 0 0 %d %d re f\n", pdfColor(ColorWhite), v.Size.X, h)
+	b.WriteString("1 J 1 j\n")
+	for _, s := range v.shapes {
+		switch {
+		case s.text != "":
+			fmt.Fprintf(&b, "BT /F1 %g Tf %s rg %s %s Td %s Tj ET\n", s.size, pdfColor(s.col), formatCoord(s.points[0].X), formatCoord(float64(h)-s.points[0].Y), pdfString(s.text))
+		default:
+			if !s.clip.Empty() {
+				// the clipping path applies until the graphics state is restored.
+				r := s.clip
+				fmt.Fprintf(&b, "q %d %d %d %d re W n\n", r.Min.X, h-r.Max.Y, r.Dx(), r.Dy())
+			}
+			for i, p := range s.points {
+				op := "l"
+				if i == 0 {
+					op = "m"
+				}
+				fmt.Fprintf(&b, "%s %s %s\n", formatCoord(p.X), formatCoord(float64(h)-p.Y), op)
+			}
+			if s.fill {
+				fmt.Fprintf(&b, "%s rg h f\n", pdfColor(s.col))
+			} else {
+				fmt.Fprintf(&b, "%s RG %g w S\n", pdfColor(s.col), s.width)
+			}
+			if !s.clip.Empty() {
+				b.WriteString("Q\n")
+			}
+		}
+	}
+	return b.Bytes()
+}
+
+// WritePDF writes the plot as a single page PDF document, with the page
+// of the size of the plot, one point per pixel.
+func (v *VectorPlot) WritePDF(w io.Writer) error {
+	content := v.pdfContent()
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>", v.Size.X, v.Size.Y),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content),
+	}
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, o := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,332 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Decay configures how long the sweeps remain visible on a persistence display.
+type Decay time.Duration
+
+const (
+	// DecayOff shows only the most recent sweep.
+	DecayOff Decay = 0
+	// DecayInfinite accumulates all the sweeps, until the display is cleared.
+	DecayInfinite Decay = -1
+)
+
+// ParseDecay parses "off", "infinite" or a duration like "2s" into a Decay.
+func ParseDecay(s string) (Decay, error) {
+	switch s {
+	case "off":
+		return DecayOff, nil
+	case "infinite":
+		return DecayInfinite, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid persistence %q, want off, infinite or a positive duration", s)
+	}
+	return Decay(d), nil
+}
+
+func (d Decay) String() string {
+	switch d {
+	case DecayOff:
+		return "off"
+	case DecayInfinite:
+		return "infinite"
+	}
+	return time.Duration(d).String()
+}
+
+// ColorMap maps the intensity in the range (0, 1] to a color.
+type ColorMap func(float64) color.RGBA
+
+// TemperatureColorMap maps the low intensity to blue and the high intensity
+// to red, through cyan, green and yellow.
+func TemperatureColorMap(v float64) color.RGBA {
+	stops := []color.RGBA{
+		{0, 0, 255, 255},
+		{0, 255, 255, 255},
+		{0, 255, 0, 255},
+		{255, 255, 0, 255},
+		{255, 0, 0, 255},
+	}
+	v = math.Max(0, math.Min(1, v)) * float64(len(stops)-1)
+	i := int(v)
+	if i == len(stops)-1 {
+		return stops[i]
+	}
+	f := v - float64(i)
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a)*(1-f) + float64(b)*f + 0.5)
+	}
+	a, b := stops[i], stops[i+1]
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
+
+// minHits is the decayed number of hits below which a pixel is cleared.
+const minHits = 0.05
+
+// Persistence is a display layer that accumulates the sweeps in a per-pixel
+// hit count buffer and renders the number of hits through a color map,
+// like the intensity graded display of a digital phosphor oscilloscope.
+// Rare events stay visible and the more often the trace passes through
+// a pixel, the higher the intensity of that pixel.
+// Persistence implements scope.DisplayLayer.
+type Persistence struct {
+	// ColorMap is used to render the intensity of the pixels.
+	ColorMap ColorMap
+
+	mu    sync.Mutex
+	tb    scope.Duration
+	rect  image.Rectangle
+	decay Decay
+	tp    map[scope.ChanID]scope.TraceParams
+	hits  map[scope.ChanID][]float32
+	// paused is true while the sweeps are not accumulated,
+	// live then holds the hits of the last sweep only.
+	paused    bool
+	live      map[scope.ChanID][]float32
+	order     []scope.ChanID
+	scratch   Plot
+	img       *image.RGBA
+	changed   bool
+	lastDecay time.Time
+	// now returns the current time, used in tests.
+	now func() time.Time
+}
+
+var _ scope.DisplayLayer = &Persistence{}
+
+// NewPersistence returns a persistence layer rendering images of given size,
+// with the sweeps displayed within rect.
+func NewPersistence(size image.Point, rect image.Rectangle, decay Decay) *Persistence {
+	return &Persistence{
+		ColorMap: TemperatureColorMap,
+		rect:     rect,
+		decay:    decay,
+		tp:       make(map[scope.ChanID]scope.TraceParams),
+		hits:     make(map[scope.ChanID][]float32),
+		live:     make(map[scope.ChanID][]float32),
+		scratch:  NewPlot(size),
+		img:      image.NewRGBA(image.Rectangle{Max: size}),
+		now:      time.Now,
+	}
+}
+
+// TimeBase returns the length of the displayed sweeps.
+func (p *Persistence) TimeBase() scope.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.tb
+}
+
+// SetTimeBase sets the length of the displayed sweeps and clears the display.
+func (p *Persistence) SetTimeBase(d scope.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tb = d
+	p.clearLocked()
+}
+
+// Pause stops (if paused is true) or resumes accumulating the sweeps.
+// While paused, e.g. when the sweeps are magnified or incomplete,
+// only the last sweep passed to Add is displayed and the sweeps
+// accumulated so far are kept, without decaying, until resumed.
+func (p *Persistence) Pause(paused bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.paused == paused {
+		return
+	}
+	p.paused = paused
+	p.live = make(map[scope.ChanID][]float32)
+	// the decay continues from the moment of resuming.
+	p.lastDecay = time.Time{}
+	p.changed = true
+}
+
+// SetChannel configures the display parameters for channel ch
+// and clears the accumulated sweeps of that channel.
+func (p *Persistence) SetChannel(ch scope.ChanID, screenPos float32, perDiv scope.Voltage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tp[ch] = scope.TraceParams{Zero: float64(screenPos), PerDiv: float64(perDiv)}
+	delete(p.hits, ch)
+	delete(p.live, ch)
+	p.changed = true
+}
+
+// Clear removes all the accumulated sweeps.
+func (p *Persistence) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clearLocked()
+}
+
+func (p *Persistence) clearLocked() {
+	p.hits = make(map[scope.ChanID][]float32)
+	p.live = make(map[scope.ChanID][]float32)
+	p.changed = true
+}
+
+// shown returns the hit counts displayed: the accumulated sweeps,
+// or the last sweep while paused.
+func (p *Persistence) shown() map[scope.ChanID][]float32 {
+	if p.paused {
+		return p.live
+	}
+	return p.hits
+}
+
+// Add accumulates a sweep of samples.
+func (p *Persistence) Add(data []scope.ChannelData) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.applyDecay()
+	w := p.rect.Dx()
+	shown := p.shown()
+	for _, d := range data {
+		hits, ok := shown[d.ID]
+		if !ok {
+			hits = make([]float32, w*p.rect.Dy())
+			shown[d.ID] = hits
+			if !p.known(d.ID) {
+				p.order = append(p.order, d.ID)
+			}
+		}
+		if p.decay == DecayOff || p.paused {
+			for i := range hits {
+				hits[i] = 0
+			}
+		}
+		tp, ok := p.tp[d.ID]
+		if !ok {
+			tp = scope.TraceParams{Zero: defaultZero, PerDiv: defaultVoltsPerDiv}
+		}
+		p.clearScratch()
+		if err := p.scratch.DrawSamples(d.Samples, tp, p.rect, ColorWhite); err != nil {
+			continue
+		}
+		for y := p.rect.Min.Y; y < p.rect.Max.Y; y++ {
+			off := p.scratch.PixOffset(p.rect.Min.X, y)
+			row := hits[(y-p.rect.Min.Y)*w:]
+			for x := 0; x < w; x++ {
+				if p.scratch.Pix[off+4*x+3] != 0 {
+					row[x]++
+				}
+			}
+		}
+	}
+	p.changed = true
+}
+
+func (p *Persistence) known(ch scope.ChanID) bool {
+	for _, c := range p.order {
+		if c == ch {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Persistence) clearScratch() {
+	for y := p.rect.Min.Y; y < p.rect.Max.Y; y++ {
+		off := p.scratch.PixOffset(p.rect.Min.X, y)
+		row := p.scratch.Pix[off : off+4*p.rect.Dx()]
+		for i := range row {
+			row[i] = 0
+		}
+	}
+}
+
+// applyDecay reduces the hit counts exponentially, with the time constant
+// equal to the configured decay.
+func (p *Persistence) applyDecay() {
+	now := p.now()
+	last := p.lastDecay
+	p.lastDecay = now
+	if p.decay <= 0 || last.IsZero() || p.paused {
+		return
+	}
+	f := float32(math.Exp(-float64(now.Sub(last)) / float64(p.decay)))
+	if f >= 1 {
+		return
+	}
+	for _, hits := range p.hits {
+		for i, h := range hits {
+			if h == 0 {
+				continue
+			}
+			h *= f
+			if h < minHits {
+				h = 0
+			}
+			hits[i] = h
+			p.changed = true
+		}
+	}
+}
+
+// Render returns the image of the accumulated sweeps, transparent where
+// no sweep passed. Channels are drawn in the order they were first added.
+// A nil image means the image did not change since the last Render.
+// The returned image is reused by the following calls to Render.
+func (p *Persistence) Render() *image.RGBA {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.applyDecay()
+	if !p.changed {
+		return nil
+	}
+	p.changed = false
+	for i := range p.img.Pix {
+		p.img.Pix[i] = 0
+	}
+	w := p.rect.Dx()
+	shown := p.shown()
+	for _, ch := range p.order {
+		hits := shown[ch]
+		var maxHits float32
+		for _, h := range hits {
+			if h > maxHits {
+				maxHits = h
+			}
+		}
+		if maxHits == 0 {
+			continue
+		}
+		// logarithmic scale keeps the rare events visible next to
+		// the frequent ones.
+		scale := 1 / math.Log1p(float64(maxHits))
+		for i, h := range hits {
+			if h == 0 {
+				continue
+			}
+			p.img.SetRGBA(p.rect.Min.X+i%w, p.rect.Min.Y+i/w, p.ColorMap(math.Log1p(float64(h))*scale))
+		}
+	}
+	return p.img
+}
@@ -0,0 +1,183 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+func constSweep(v scope.Voltage) []scope.ChannelData {
+	samples := make([]scope.Voltage, 100)
+	for i := range samples {
+		samples[i] = v
+	}
+	return []scope.ChannelData{{ID: "signal", Samples: samples}}
+}
+
+func TestPersistence(t *testing.T) {
+	size := image.Point{100, 81}
+	// 0V in the middle row (40), 0.5V at row 20.
+	zeroPt, onePt := image.Point{50, 40}, image.Point{50, 20}
+	for _, tc := range []struct {
+		desc    string
+		decay   Decay
+		sweeps  []scope.Voltage
+		elapsed time.Duration
+		want    map[image.Point]color.RGBA
+	}{
+		{
+			desc:   "infinite, frequent trace is hotter than a rare one",
+			decay:  DecayInfinite,
+			sweeps: []scope.Voltage{0, 0, 0, 0.5, 0, 0, 0},
+			want: map[image.Point]color.RGBA{
+				zeroPt:            TemperatureColorMap(1),
+				onePt:             TemperatureColorMap(math.Log(2) / math.Log(7)),
+				image.Point{0, 0}: {},
+			},
+		},
+		{
+			desc:   "off, only the last sweep is visible",
+			decay:  DecayOff,
+			sweeps: []scope.Voltage{0, 0, 0, 0.5},
+			want: map[image.Point]color.RGBA{
+				zeroPt: {},
+				onePt:  TemperatureColorMap(1),
+			},
+		},
+		{
+			desc:    "decayed after a long time",
+			decay:   Decay(time.Second),
+			sweeps:  []scope.Voltage{0},
+			elapsed: 10 * time.Second,
+			want: map[image.Point]color.RGBA{
+				zeroPt: {},
+			},
+		},
+		{
+			desc:    "still visible after a short time",
+			decay:   Decay(time.Second),
+			sweeps:  []scope.Voltage{0},
+			elapsed: 100 * time.Millisecond,
+			want: map[image.Point]color.RGBA{
+				zeroPt: TemperatureColorMap(1),
+			},
+		},
+	} {
+		now := time.Unix(1000, 0)
+		p := NewPersistence(size, image.Rectangle{Max: size}, tc.decay)
+		p.now = func() time.Time { return now }
+		p.SetTimeBase(scope.Millisecond)
+		p.SetChannel("signal", 0.5, 0.25)
+		for _, v := range tc.sweeps {
+			p.Add(constSweep(v))
+		}
+		now = now.Add(tc.elapsed)
+		img := p.Render()
+		if img == nil {
+			t.Fatalf("%s: Render() returned nil, want an image", tc.desc)
+		}
+		for pt, want := range tc.want {
+			if got := img.RGBAAt(pt.X, pt.Y); got != want {
+				t.Errorf("%s: pixel %v: got %v, want %v", tc.desc, pt, got, want)
+			}
+		}
+		if tc.decay == DecayInfinite && p.Render() != nil {
+			t.Errorf("%s: second Render() returned an image, want nil", tc.desc)
+		}
+	}
+}
+
+func TestPersistencePause(t *testing.T) {
+	size := image.Point{100, 81}
+	zeroPt, onePt := image.Point{50, 40}, image.Point{50, 20}
+	now := time.Unix(1000, 0)
+	p := NewPersistence(size, image.Rectangle{Max: size}, Decay(time.Second))
+	p.now = func() time.Time { return now }
+	p.SetChannel("signal", 0.5, 0.25)
+	p.Add(constSweep(0))
+
+	pixels := func(desc string, want map[image.Point]color.RGBA) {
+		t.Helper()
+		img := p.Render()
+		if img == nil {
+			t.Fatalf("%s: Render() returned nil, want an image", desc)
+		}
+		for pt, w := range want {
+			if got := img.RGBAAt(pt.X, pt.Y); got != w {
+				t.Errorf("%s: pixel %v: got %v, want %v", desc, pt, got, w)
+			}
+		}
+	}
+	p.Pause(true)
+	p.Add(constSweep(0.5))
+	now = now.Add(10 * time.Second)
+	pixels("paused", map[image.Point]color.RGBA{
+		zeroPt: {},
+		onePt:  TemperatureColorMap(1),
+	})
+	p.Pause(false)
+	pixels("resumed, not decayed while paused", map[image.Point]color.RGBA{
+		zeroPt: TemperatureColorMap(1),
+		onePt:  {},
+	})
+}
+
+func TestParseDecay(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		want    Decay
+		wantErr bool
+	}{
+		{in: "off", want: DecayOff},
+		{in: "infinite", want: DecayInfinite},
+		{in: "2s", want: Decay(2 * time.Second)},
+		{in: "0s", wantErr: true},
+		{in: "forever", wantErr: true},
+	} {
+		got, err := ParseDecay(tc.in)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("ParseDecay(%q): got error %v, want error: %v", tc.in, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseDecay(%q): got %v, want %v", tc.in, got, tc.want)
+		}
+		if !tc.wantErr && got.String() != tc.in {
+			t.Errorf("ParseDecay(%q).String(): got %q", tc.in, got.String())
+		}
+	}
+}
+
+func TestTemperatureColorMap(t *testing.T) {
+	for _, tc := range []struct {
+		v    float64
+		want color.RGBA
+	}{
+		{0, color.RGBA{0, 0, 255, 255}},
+		{0.5, color.RGBA{0, 255, 0, 255}},
+		{0.625, color.RGBA{128, 255, 0, 255}},
+		{1, color.RGBA{255, 0, 0, 255}},
+	} {
+		if got := TemperatureColorMap(tc.v); got != tc.want {
+			t.Errorf("TemperatureColorMap(%v): got %v, want %v", tc.v, got, tc.want)
+		}
+	}
+}
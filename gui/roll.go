@@ -0,0 +1,77 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import "github.com/zagrodzki/goscope/scope"
+
+// Roll keeps the most recent samples of every channel, up to the length
+// of the sweep, for the roll mode display, where the trace scrolls from
+// right to left as the samples arrive instead of being redrawn once
+// per sweep.
+type Roll struct {
+	n    int
+	data []scope.ChannelData
+}
+
+// NewRoll returns a Roll keeping up to n samples of every channel.
+func NewRoll(n int) *Roll {
+	return &Roll{n: n}
+}
+
+// Add appends a chunk of samples, dropping the samples older
+// than the length of the sweep.
+func (r *Roll) Add(data []scope.ChannelData) {
+	for _, d := range data {
+		i := r.index(d.ID)
+		s := append(r.data[i].Samples, d.Samples...)
+		if len(s) > r.n {
+			// move the retained samples to the beginning, so that
+			// the buffer doesn't grow.
+			s = append(s[:0], s[len(s)-r.n:]...)
+		}
+		r.data[i].Samples = s
+	}
+}
+
+func (r *Roll) index(ch scope.ChanID) int {
+	for i, d := range r.data {
+		if d.ID == ch {
+			return i
+		}
+	}
+	r.data = append(r.data, scope.ChannelData{ID: ch, Samples: make([]scope.Voltage, 0, 2*r.n)})
+	return len(r.data) - 1
+}
+
+// Sweep returns the retained samples, oldest first. Until a full sweep
+// arrived, the returned sweep is shorter than n.
+// The returned data shares the samples with r and is valid until
+// the next Add.
+func (r *Roll) Sweep() []scope.ChannelData {
+	return r.data
+}
+
+// Full returns true if r holds a full sweep of every channel.
+func (r *Roll) Full() bool {
+	if len(r.data) == 0 {
+		return false
+	}
+	for _, d := range r.data {
+		if len(d.Samples) < r.n {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+func TestRoll(t *testing.T) {
+	r := NewRoll(4)
+	if r.Full() {
+		t.Errorf("Full() of an empty Roll: got true, want false")
+	}
+	for _, tc := range []struct {
+		add  []scope.Voltage
+		want []scope.Voltage
+		full bool
+	}{
+		{[]scope.Voltage{1, 2}, []scope.Voltage{1, 2}, false},
+		{[]scope.Voltage{3}, []scope.Voltage{1, 2, 3}, false},
+		{[]scope.Voltage{4, 5}, []scope.Voltage{2, 3, 4, 5}, true},
+		{[]scope.Voltage{6, 7, 8, 9, 10}, []scope.Voltage{7, 8, 9, 10}, true},
+	} {
+		r.Add([]scope.ChannelData{{ID: "a", Samples: tc.add}})
+		want := []scope.ChannelData{{ID: "a", Samples: tc.want}}
+		if got := r.Sweep(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Sweep() after Add(%v): got %v, want %v", tc.add, got, want)
+		}
+		if got := r.Full(); got != tc.full {
+			t.Errorf("Full() after Add(%v): got %v, want %v", tc.add, got, tc.full)
+		}
+	}
+}
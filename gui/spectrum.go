@@ -0,0 +1,92 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/zagrodzki/goscope/spectrum"
+)
+
+// SpectrumParams configures how a spectrum is drawn.
+type SpectrumParams struct {
+	// Scale selects the linear (volts) or logarithmic (dBV) vertical axis.
+	Scale spectrum.Scale
+	// Ref is the magnitude at the top edge of the plot, in volts or dBV.
+	Ref float64
+	// PerDiv is the magnitude per vertical division, in volts or dB.
+	PerDiv float64
+	// MaxFreq is the frequency at the right edge of the plot, in Hz.
+	// 0 means the Nyquist frequency of the spectrum.
+	MaxFreq float64
+}
+
+// FreqPerDiv returns the frequency span of a single horizontal division
+// for spectrum s, in Hz.
+func (p SpectrumParams) FreqPerDiv(s *spectrum.Spectrum) float64 {
+	return p.maxFreq(s) / DivCols
+}
+
+func (p SpectrumParams) maxFreq(s *spectrum.Spectrum) float64 {
+	if p.MaxFreq > 0 {
+		return p.MaxFreq
+	}
+	return s.Frequency(len(s.Magnitude) - 1)
+}
+
+// spectrumToPoints maps the spectrum to one point per pixel column of rect.
+// If a column spans multiple bins, the highest magnitude is used, so that
+// narrow peaks are not lost.
+func spectrumToPoints(s *spectrum.Spectrum, p SpectrumParams, rect image.Rectangle) []image.Point {
+	if len(s.Magnitude) == 0 || rect.Dx() < 2 {
+		return nil
+	}
+	values := s.Values(p.Scale)
+	binsPerPixel := p.maxFreq(s) / s.BinWidth / float64(rect.Dx()-1)
+	pixelsPerDiv := float64(rect.Dy()-1) / DivRows
+	points := make([]image.Point, 0, rect.Dx())
+	for x := 0; x < rect.Dx(); x++ {
+		lo := int(math.Floor((float64(x)-0.5)*binsPerPixel + 0.5))
+		hi := int(math.Floor((float64(x)+0.5)*binsPerPixel + 0.5))
+		if lo < 0 {
+			lo = 0
+		}
+		if hi <= lo {
+			hi = lo + 1
+		}
+		if lo >= len(values) {
+			break
+		}
+		if hi > len(values) {
+			hi = len(values)
+		}
+		v := values[lo]
+		for _, b := range values[lo+1 : hi] {
+			v = math.Max(v, b)
+		}
+		y := (p.Ref - v) / p.PerDiv * pixelsPerDiv
+		y = math.Max(0, math.Min(y, float64(rect.Dy()-1)))
+		points = append(points, image.Point{rect.Min.X + x, rect.Min.Y + round(y)})
+	}
+	return points
+}
+
+// DrawSpectrum draws the spectrum s within rect, with frequency on
+// the horizontal axis starting from DC at the left edge.
+func (plot Plot) DrawSpectrum(s *spectrum.Spectrum, p SpectrumParams, rect image.Rectangle, col color.RGBA) {
+	plot.drawPolyline(spectrumToPoints(s, p, rect), rect, col)
+}
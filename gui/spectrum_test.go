@@ -0,0 +1,74 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/spectrum"
+)
+
+func TestDrawSpectrum(t *testing.T) {
+	// 1V amplitude sine at 100kHz, 1000 samples taken every microsecond,
+	// i.e. at bin 100 of the spectrum.
+	samples := make([]scope.Voltage, 1000)
+	for i := range samples {
+		samples[i] = scope.Voltage(math.Sin(2 * math.Pi * 100 * float64(i) / 1000))
+	}
+	s := spectrum.Compute(samples, scope.Microsecond, spectrum.FlatTop)
+	for _, tc := range []struct {
+		desc   string
+		params SpectrumParams
+		size   image.Point
+		// peakX and peakY is the expected position of the top of the peak.
+		peakX, peakY int
+	}{
+		{
+			// one pixel per bin, 100 pixels per 10dB div, peak at -3dBV.
+			desc:   "dBV, full range",
+			params: SpectrumParams{Scale: spectrum.DBV, Ref: 0, PerDiv: 10},
+			size:   image.Point{501, 801},
+			peakX:  100,
+			peakY:  30,
+		},
+		{
+			// 2 bins per pixel, 0.1V per div, 0.707V RMS.
+			desc:   "linear, zoomed in",
+			params: SpectrumParams{Scale: spectrum.Linear, Ref: 0.8, PerDiv: 0.1, MaxFreq: 200e3},
+			size:   image.Point{101, 801},
+			peakX:  50,
+			peakY:  93,
+		},
+	} {
+		plot := Plot{RGBA: image.NewRGBA(image.Rectangle{Max: tc.size})}
+		plot.Fill(ColorWhite)
+		plot.DrawSpectrum(s, tc.params, plot.Bounds(), ColorBlack)
+		topX, topY := -1, tc.size.Y
+		for x := 0; x < tc.size.X; x++ {
+			for y := 0; y < topY; y++ {
+				if isOn(plot, x, y) {
+					topX, topY = x, y
+					break
+				}
+			}
+		}
+		if topX != tc.peakX || topY != tc.peakY {
+			t.Errorf("%s: top of the peak at (%d, %d), want (%d, %d)", tc.desc, topX, topY, tc.peakX, tc.peakY)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// svgColor returns the SVG representation of col.
+func svgColor(col color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", col.R, col.G, col.B)
+}
+
+// WriteSVG writes the plot as an SVG image on white background.
+func (v *VectorPlot) WriteSVG(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", v.Size.X, v.Size.Y, v.Size.X, v.Size.Y)
+	fmt.Fprintf(bw, "<rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", v.Size.X, v.Size.Y, svgColor(ColorWhite))
+	// the clip paths are defined once for every distinct rectangle.
+	clips := make(map[image.Rectangle]string)
+	for _, s := range v.shapes {
+		if s.clip.Empty() || clips[s.clip] != "" {
+			continue
+		}
+		id := fmt.Sprintf("clip%d", len(clips))
+		clips[s.clip] = id
+		r := s.clip
+		fmt.Fprintf(bw, "<clipPath id=\"%s\"><rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"/></clipPath>\n", id, r.Min.X, r.Min.Y, r.Dx(), r.Dy())
+	}
+	for _, s := range v.shapes {
+		clip := ""
+		if id := clips[s.clip]; id != "" {
+			clip = fmt.Sprintf(" clip-path=\"url(#%s)\"", id)
+		}
+		switch {
+		case s.text != "":
+			fmt.Fprintf(bw, "<text x=\"%s\" y=\"%s\" font-family=\"sans-serif\" font-size=\"%g\" fill=\"%s\">", formatCoord(s.points[0].X), formatCoord(s.points[0].Y), s.size, svgColor(s.col))
+			if err := xml.EscapeText(bw, []byte(s.text)); err != nil {
+				return err
+			}
+			fmt.Fprintf(bw, "</text>\n")
+		case s.fill:
+			fmt.Fprintf(bw, "<polygon points=\"%s\" fill=\"%s\"%s/>\n", svgPoints(s), svgColor(s.col), clip)
+		default:
+			fmt.Fprintf(bw, "<polyline points=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"%g\" stroke-linejoin=\"round\"%s/>\n", svgPoints(s), svgColor(s.col), s.width, clip)
+		}
+	}
+	fmt.Fprintf(bw, "</svg>\n")
+	return bw.Flush()
+}
+
+// svgPoints returns the points of the shape as the SVG points attribute.
+func svgPoints(s shape) string {
+	ps := make([]string, len(s.points))
+	for i, p := range s.points {
+		ps[i] = formatCoord(p.X) + "," + formatCoord(p.Y)
+	}
+	return strings.Join(ps, " ")
+}
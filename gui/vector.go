@@ -0,0 +1,303 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zagrodzki/goscope/compat"
+	"github.com/zagrodzki/goscope/measurements"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// vectorLineWidth is the width of the traces in the vector plots.
+const vectorLineWidth = 1.5
+
+// vectorCharWidth is the approximate width of a character
+// relative to the font size, used to lay out the text.
+const vectorCharWidth = 0.6
+
+// tableMeasurements are the measurements shown in the measurement table.
+var tableMeasurements = []measurements.Measurement{
+	measurements.PeakToPeak,
+	measurements.Min,
+	measurements.Max,
+	measurements.Mean,
+	measurements.RMS,
+	measurements.Frequency,
+	measurements.Period,
+}
+
+// vectorPoint is a point of a VectorPlot. Unlike the pixels of a Plot,
+// the points aren't limited to whole numbers.
+type vectorPoint struct {
+	X, Y float64
+}
+
+// vectorPoints converts the points to vectorPoints.
+func vectorPoints(points []image.Point) []vectorPoint {
+	ret := make([]vectorPoint, len(points))
+	for i, p := range points {
+		ret[i] = vectorPoint{float64(p.X), float64(p.Y)}
+	}
+	return ret
+}
+
+// shape is an element of a VectorPlot: a polyline, a filled polygon
+// or a text, depending on which fields are set.
+type shape struct {
+	points []vectorPoint
+	// fill is true for a filled polygon.
+	fill  bool
+	col   color.RGBA
+	width float64
+	// clip is the area outside of which the shape is not drawn,
+	// the shape is not clipped if clip is empty.
+	clip image.Rectangle
+	// text is drawn with the baseline starting at points[0].
+	text string
+	size float64
+}
+
+// VectorPlot is a plot made of lines and texts, which can be saved
+// in a vector format (SVG or PDF) and scaled without loss of quality.
+// The coordinates are the same as the pixels of a Plot of the same size,
+// with (0, 0) in the top left corner.
+type VectorPlot struct {
+	Size   image.Point
+	shapes []shape
+}
+
+// NewVectorPlot returns an empty VectorPlot of given size.
+func NewVectorPlot(size image.Point) *VectorPlot {
+	return &VectorPlot{Size: size}
+}
+
+// DrawLine draws a line from p1 to p2.
+func (v *VectorPlot) DrawLine(p1, p2 image.Point, col color.RGBA) {
+	v.DrawPolyline([]image.Point{p1, p2}, col, 1)
+}
+
+// DrawPolyline draws the lines connecting the consecutive points.
+func (v *VectorPlot) DrawPolyline(points []image.Point, col color.RGBA, width float64) {
+	if len(points) < 2 {
+		return
+	}
+	v.shapes = append(v.shapes, shape{points: vectorPoints(points), col: col, width: width})
+}
+
+// DrawPolygon draws a polygon with the vertices at points, filled with col.
+func (v *VectorPlot) DrawPolygon(points []image.Point, col color.RGBA) {
+	v.shapes = append(v.shapes, shape{points: vectorPoints(points), col: col, fill: true})
+}
+
+// DrawText draws the text with the baseline starting at origin.
+func (v *VectorPlot) DrawText(origin image.Point, text string, col color.RGBA, size float64) {
+	v.shapes = append(v.shapes, shape{points: vectorPoints([]image.Point{origin}), text: text, col: col, size: size})
+}
+
+// textWidth returns the approximate width of the text.
+func (v *VectorPlot) textWidth(text string, size float64) int {
+	return round(float64(utf8.RuneCountInString(text)) * size * vectorCharWidth)
+}
+
+// DrawSamples draws the samples as a polyline clipped to rect, mapping
+// the samples to the same columns as Plot.DrawSamples before
+// the interpolation, without rounding the voltages to whole pixels.
+func (v *VectorPlot) DrawSamples(samples []scope.Voltage, traceParams scope.TraceParams, rect image.Rectangle, col color.RGBA) {
+	var points []vectorPoint
+	mapSamples(samples, traceParams, rect, func(x int, y float64) {
+		points = append(points, vectorPoint{float64(x), y})
+	})
+	if len(points) < 2 {
+		return
+	}
+	v.shapes = append(v.shapes, shape{points: points, col: col, width: vectorLineWidth, clip: rect})
+}
+
+// formatCoord returns the coordinate c rounded to hundredths of a unit,
+// without an exponent, which PDF doesn't allow.
+func formatCoord(c float64) string {
+	return strconv.FormatFloat(math.Round(c*100)/100, 'f', -1, 64)
+}
+
+// vectorMarker returns the vertices of a marker triangle with the base
+// centered at p, pointing in direction dir, as drawn by Plot.drawMarker.
+func vectorMarker(p, dir image.Point) []image.Point {
+	perp := image.Point{dir.Y, dir.X}
+	h := markerSize - 1
+	return []image.Point{p.Sub(perp.Mul(h)), p.Add(perp.Mul(h)), p.Add(dir.Mul(h))}
+}
+
+// DrawGraticule draws the graticule g, with the same elements
+// as Plot.DrawGraticule.
+func (v *VectorPlot) DrawGraticule(g Graticule) {
+	r := g.Rect
+	for i := 1; i < DivRows; i++ {
+		y := r.Min.Y + i*r.Dy()/DivRows
+		v.DrawLine(image.Point{r.Min.X, y}, image.Point{r.Max.X, y}, ColorGrey)
+	}
+	for i := 1; i < DivCols; i++ {
+		x := r.Min.X + i*r.Dx()/DivCols
+		v.DrawLine(image.Point{x, r.Min.Y}, image.Point{x, r.Max.Y}, ColorGrey)
+	}
+	tick := color.RGBA{tickColor, tickColor, tickColor, 255}
+	cx, cy := r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2
+	for i := 1; i < DivCols*ticksPerDiv; i++ {
+		x := r.Min.X + i*r.Dx()/(DivCols*ticksPerDiv)
+		v.DrawLine(image.Point{x, cy - 2}, image.Point{x, cy + 2}, tick)
+	}
+	for i := 1; i < DivRows*ticksPerDiv; i++ {
+		y := r.Min.Y + i*r.Dy()/(DivRows*ticksPerDiv)
+		v.DrawLine(image.Point{cx - 2, y}, image.Point{cx + 2, y}, tick)
+	}
+	// a frame around the graticule, separating it from the measurement table.
+	v.DrawPolyline([]image.Point{r.Min, {r.Max.X, r.Min.Y}, r.Max, {r.Min.X, r.Max.Y}, r.Min}, ColorBlack, 1)
+
+	for _, ch := range g.Channels {
+		v.DrawPolygon(vectorMarker(image.Point{r.Min.X, channelY(ch.Params, 0, r)}, image.Point{1, 0}), ch.Color)
+	}
+	if t := g.Trigger; t != nil {
+		col := ColorBlack
+		params := scope.TraceParams{Zero: defaultZero, PerDiv: defaultVoltsPerDiv}
+		for _, ch := range g.Channels {
+			if ch.ID == t.Source {
+				col, params = ch.Color, ch.Params
+			}
+		}
+		v.DrawPolygon(vectorMarker(image.Point{r.Max.X - 1, channelY(params, t.Level, r)}, image.Point{-1, 0}), col)
+		x := r.Min.X + round(math.Max(0, math.Min(1, t.Position))*float64(r.Dx()-1))
+		v.DrawPolygon(vectorMarker(image.Point{x, r.Min.Y}, image.Point{0, 1}), col)
+	}
+
+	top := []string{fmt.Sprintf("%s/div", g.TimeBase/DivCols)}
+	if g.Interval > 0 {
+		top = append(top, formatSI(float64(scope.Second)/float64(g.Interval), "S/s"))
+	}
+	pad := 2 * markerSize
+	v.DrawText(image.Point{r.Min.X + pad, r.Min.Y + pad + readoutFontSize}, strings.Join(top, "  "), ColorBlack, readoutFontSize)
+	if g.State != "" {
+		x := r.Max.X - pad - v.textWidth(g.State, readoutFontSize)
+		v.DrawText(image.Point{x, r.Min.Y + pad + readoutFontSize}, g.State, ColorBlack, readoutFontSize)
+	}
+	x := r.Min.X + pad
+	for _, ch := range g.Channels {
+		text := fmt.Sprintf("%s %s/div", ch.ID, formatSI(ch.Params.PerDiv, "V"))
+		v.DrawText(image.Point{x, r.Max.Y - pad}, text, ch.Color, readoutFontSize)
+		x += v.textWidth(text, readoutFontSize) + pad
+	}
+}
+
+// tableRowHeight is the height of a row of the measurement table.
+const tableRowHeight = 2 * readoutFontSize
+
+// MeasurementTableHeight returns the height of the measurement table
+// of n channels.
+func MeasurementTableHeight(n int) int {
+	return (n + 1) * tableRowHeight
+}
+
+// DrawMeasurements draws the table of the measurements of every channel
+// of the sweep data, sampled every interval, within rect. The first row
+// names the measurements, the following rows are the values for
+// the channels, in the channel colors.
+func (v *VectorPlot) DrawMeasurements(data []scope.ChannelData, interval scope.Duration, rect image.Rectangle, cols map[scope.ChanID]color.RGBA) {
+	colWidth := rect.Dx() / (len(tableMeasurements) + 1)
+	row := func(i int, cells []string, col color.RGBA) {
+		y := rect.Min.Y + (i+1)*tableRowHeight - readoutFontSize/2
+		for j, c := range cells {
+			v.DrawText(image.Point{rect.Min.X + j*colWidth + markerSize, y}, c, col, readoutFontSize)
+		}
+	}
+	header := []string{"Channel"}
+	for _, m := range tableMeasurements {
+		header = append(header, m.String())
+	}
+	row(0, header, ColorBlack)
+	v.DrawLine(image.Point{rect.Min.X, rect.Min.Y + tableRowHeight}, image.Point{rect.Max.X, rect.Min.Y + tableRowHeight}, ColorGrey)
+	for i, d := range data {
+		r := measurements.Measure(d.Samples, interval)
+		cells := []string{string(d.ID)}
+		for _, m := range tableMeasurements {
+			cells = append(cells, r.Format(m))
+		}
+		col, ok := cols[d.ID]
+		if !ok {
+			col = ColorBlack
+		}
+		row(i+1, cells, col)
+	}
+}
+
+// CreateVectorPlot plots a sweep from the device with the graticule, within
+// a rectangle of width x height, and the table of the measurements below.
+func CreateVectorPlot(dev scope.Device, width, height int, traceParams map[scope.ChanID]scope.TraceParams, cols map[scope.ChanID]color.RGBA) (*VectorPlot, error) {
+	rec := &compat.Recorder{TB: scope.Millisecond}
+	dev.Attach(rec)
+	dev.Start()
+	data := <-rec.Data
+	dev.Stop()
+	if data.Error != nil {
+		return nil, data.Error
+	}
+	rect := image.Rect(0, 0, width, height)
+	v := NewVectorPlot(image.Point{width, height + MeasurementTableHeight(len(data.Channels))})
+	g := Graticule{
+		Rect:     rect,
+		TimeBase: scope.Duration(data.Num) * data.Interval,
+		Interval: data.Interval,
+		State:    "Stop",
+	}
+	for _, ch := range data.Channels {
+		m := channelMarker(ch.ID, traceParams, cols)
+		g.Channels = append(g.Channels, m)
+		v.DrawSamples(ch.Samples, m.Params, rect, m.Color)
+	}
+	v.DrawGraticule(g)
+	v.DrawMeasurements(data.Channels, data.Interval, image.Rect(0, height, width, v.Size.Y), cols)
+	return v, nil
+}
+
+// PlotToVector creates a vector plot of the samples from the device
+// and saves it in the format, "svg" or "pdf".
+func PlotToVector(dev scope.Device, width, height int, traceParams map[scope.ChanID]scope.TraceParams, cols map[scope.ChanID]color.RGBA, format, outputFile string) error {
+	if format != "svg" && format != "pdf" {
+		return fmt.Errorf("unknown vector format %q, want svg or pdf", format)
+	}
+	v, err := CreateVectorPlot(dev, width, height, traceParams, cols)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	if format == "svg" {
+		err = v.WriteSVG(f)
+	} else {
+		err = v.WritePDF(f)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,196 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zagrodzki/goscope/dummy"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+func TestVectorPlotDrawSamples(t *testing.T) {
+	v := NewVectorPlot(image.Point{100, 81})
+	// the last sample is below the rectangle.
+	samples := []scope.Voltage{-1, 0.01, 1, 0, -2}
+	tp := scope.TraceParams{Zero: 0.5, PerDiv: 0.25}
+	r := image.Rect(0, 0, 100, 81)
+	v.DrawSamples(samples, tp, r, ColorRed)
+	if len(v.shapes) != 1 {
+		t.Fatalf("DrawSamples(): got %d shapes, want 1", len(v.shapes))
+	}
+	s := v.shapes[0]
+	want := []vectorPoint{{0, 80}, {25, 39.6}, {50, 0}, {74, 40}, {99, 120}}
+	if len(s.points) != len(want) {
+		t.Fatalf("DrawSamples(): got points %v, want %v", s.points, want)
+	}
+	for i, p := range s.points {
+		if math.Abs(p.X-want[i].X) > 1e-9 || math.Abs(p.Y-want[i].Y) > 1e-9 {
+			t.Errorf("DrawSamples(): got points %v, want %v", s.points, want)
+			break
+		}
+	}
+	if s.clip != r {
+		t.Errorf("DrawSamples(): got clip %v, want %v", s.clip, r)
+	}
+}
+
+func TestVectorClip(t *testing.T) {
+	v := NewVectorPlot(image.Point{100, 50})
+	v.DrawSamples([]scope.Voltage{0, 0.01, 5}, scope.TraceParams{Zero: 0.5, PerDiv: 0.1}, image.Rect(10, 5, 90, 45), ColorRed)
+	v.DrawLine(image.Point{0, 0}, image.Point{10, 20}, ColorBlue)
+	var svg, pdf bytes.Buffer
+	if err := v.WriteSVG(&svg); err != nil {
+		t.Fatalf("WriteSVG: %v", err)
+	}
+	if err := v.WritePDF(&pdf); err != nil {
+		t.Fatalf("WritePDF: %v", err)
+	}
+	for _, want := range []string{
+		`<clipPath id="clip0"><rect x="10" y="5" width="80" height="40"/></clipPath>`,
+		`points="10,24.5 50,24.01 89,-219.25" fill="none" stroke="#ff0000" stroke-width="1.5" stroke-linejoin="round" clip-path="url(#clip0)"/>`,
+		`points="0,0 10,20" fill="none" stroke="#0000ff" stroke-width="1" stroke-linejoin="round"/>`,
+	} {
+		if !strings.Contains(svg.String(), want) {
+			t.Errorf("SVG %s: want %s", svg.String(), want)
+		}
+	}
+	for _, want := range []string{
+		"q 10 5 80 40 re W n\n10 25.5 m\n50 25.99 l\n89 269.25 l\n1.000 0.000 0.000 RG 1.5 w S\nQ\n0 50 m\n",
+	} {
+		if !strings.Contains(pdf.String(), want) {
+			t.Errorf("PDF %s: want %q", pdf.String(), want)
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	v := NewVectorPlot(image.Point{100, 50})
+	v.DrawLine(image.Point{0, 0}, image.Point{10, 20}, ColorRed)
+	v.DrawPolygon([]image.Point{{0, 0}, {5, 5}, {0, 10}}, ColorBlue)
+	v.DrawText(image.Point{5, 40}, "a < b & 5µV", ColorBlack, 14)
+	var b bytes.Buffer
+	if err := v.WriteSVG(&b); err != nil {
+		t.Fatalf("WriteSVG: %v", err)
+	}
+	// the output is well formed XML.
+	d := xml.NewDecoder(bytes.NewReader(b.Bytes()))
+	var elems, texts []string
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("parsing the SVG: %v\n%s", err, b.String())
+		}
+		switch e := tok.(type) {
+		case xml.StartElement:
+			elems = append(elems, e.Name.Local)
+		case xml.CharData:
+			if s := strings.TrimSpace(string(e)); s != "" {
+				texts = append(texts, s)
+			}
+		}
+	}
+	if got, want := elems, []string{"svg", "rect", "polyline", "polygon", "text"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SVG elements: got %v, want %v", got, want)
+	}
+	if got, want := texts, []string{"a < b & 5µV"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SVG texts: got %q, want %q", got, want)
+	}
+	for _, want := range []string{`points="0,0 10,20" fill="none" stroke="#ff0000"`, `fill="#0000ff"`} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("SVG %s: want %s", b.String(), want)
+		}
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	v := NewVectorPlot(image.Point{100, 50})
+	v.DrawLine(image.Point{0, 0}, image.Point{10, 20}, ColorRed)
+	v.DrawText(image.Point{5, 40}, "(5µV)", ColorBlack, 14)
+	var b bytes.Buffer
+	if err := v.WritePDF(&b); err != nil {
+		t.Fatalf("WritePDF: %v", err)
+	}
+	pdf := b.String()
+	if !strings.HasPrefix(pdf, "%PDF-1.4\n") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Errorf("PDF: want the header and the trailer, got\n%s", pdf)
+	}
+	for _, want := range []string{"0 50 m\n10 30 l\n1.000 0.000 0.000 RG", `5 10 Td (\(5\265V\)) Tj`, "/MediaBox [0 0 100 50]"} {
+		if !strings.Contains(pdf, want) {
+			t.Errorf("PDF: want %q, got\n%s", want, pdf)
+		}
+	}
+	// the cross-reference table points at the objects.
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	if m == nil {
+		t.Fatalf("PDF: startxref not found")
+	}
+	xref, _ := strconv.Atoi(m[1])
+	if !strings.HasPrefix(pdf[xref:], "xref\n0 6\n") {
+		t.Fatalf("PDF: startxref %d does not point at the xref table", xref)
+	}
+	entries := strings.Split(pdf[xref:], "\n")[3:8]
+	for i, e := range entries {
+		off, _ := strconv.Atoi(e[:10])
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !strings.HasPrefix(pdf[off:], want) {
+			t.Errorf("PDF: xref entry %d points at %q, want %q", i+1, pdf[off:off+len(want)], want)
+		}
+	}
+}
+
+func TestCreateVectorPlot(t *testing.T) {
+	dev, err := dummy.Open("sin,square")
+	if err != nil {
+		t.Fatalf("dummy.Open: %v", err)
+	}
+	v, err := CreateVectorPlot(dev, 400, 300, nil, map[scope.ChanID]color.RGBA{"sin": ColorRed})
+	if err != nil {
+		t.Fatalf("CreateVectorPlot: %v", err)
+	}
+	if got, want := v.Size, (image.Point{400, 300 + MeasurementTableHeight(2)}); got != want {
+		t.Errorf("Size: got %v, want %v", got, want)
+	}
+	var traces int
+	var texts []string
+	for _, s := range v.shapes {
+		if s.width == vectorLineWidth {
+			traces++
+		}
+		texts = append(texts, s.text)
+	}
+	if traces != 2 {
+		t.Errorf("traces: got %d, want 2", traces)
+	}
+	all := strings.Join(texts, "|")
+	for _, want := range []string{"100ms/div", "sin 500mV/div", "|Vpp|", "|sin|", "|square|"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("texts %s: want %s", all, want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/zagrodzki/goscope/compat"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// ParseXY parses the X and Y channels of an XY plot given as
+// "xChanID,yChanID" and checks that they are channels of chans.
+func ParseXY(value string, chans []scope.ChanID) (x, y scope.ChanID, err error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid XY channels %q, want \"xChanID,yChanID\"", value)
+	}
+	for _, id := range parts {
+		found := false
+		for _, c := range chans {
+			found = found || c == scope.ChanID(id)
+		}
+		if !found {
+			return "", "", fmt.Errorf("unknown channel %q, available channels: %v", id, chans)
+		}
+	}
+	return scope.ChanID(parts[0]), scope.ChanID(parts[1]), nil
+}
+
+// xyMapper maps the voltages of the X and Y channels to the pixels
+// of the plot rectangle. The Zero of the X channel params is
+// the position of X=0 counting from the left edge of the rectangle.
+type xyMapper struct {
+	rect   image.Rectangle
+	xp, yp scope.TraceParams
+}
+
+// xyMaxPos limits the positions of the points to a rectangle width
+// or height beyond the edges of the rectangle, so that the lines to
+// the points far outside are not drawn pixel by pixel.
+const xyMaxPos = 1
+
+func limitPos(pos float64) float64 {
+	return math.Max(-xyMaxPos, math.Min(1+xyMaxPos, pos))
+}
+
+func (m xyMapper) x(v scope.Voltage) int {
+	pos := limitPos(float64(v)/(DivCols*m.xp.PerDiv) + m.xp.Zero)
+	return m.rect.Min.X + round(pos*float64(m.rect.Dx()-1))
+}
+
+func (m xyMapper) y(v scope.Voltage) int {
+	pos := limitPos(float64(v)/(DivRows*m.yp.PerDiv) + m.yp.Zero)
+	return m.rect.Max.Y - 1 - round(pos*float64(m.rect.Dy()-1))
+}
+
+// DrawXY draws the samples of the Y channel against the samples
+// of the X channel, sampled at the same time, connecting the consecutive
+// points. xp and yp are the display params of the channels, the Zero of xp
+// is the position of X=0 counting from the left edge of rect.
+func (plot Plot) DrawXY(x, y []scope.Voltage, xp, yp scope.TraceParams, rect image.Rectangle, col color.RGBA) {
+	m := xyMapper{rect: rect, xp: xp, yp: yp}
+	n := min(len(x), len(y))
+	var prev image.Point
+	havePrev := false
+	for i := 0; i < n; i++ {
+		if math.IsNaN(float64(x[i])) || math.IsNaN(float64(y[i])) {
+			havePrev = false
+			continue
+		}
+		p := image.Point{m.x(x[i]), m.y(y[i])}
+		if havePrev {
+			plot.drawTraceLine(prev, p, rect, col)
+		}
+		prev, havePrev = p, true
+	}
+}
+
+// XYGraticule describes the graticule of the XY display.
+type XYGraticule struct {
+	Rect image.Rectangle
+	// X and Y are the channels plotted on the horizontal and vertical axis.
+	X, Y ChannelMarker
+}
+
+// DrawXYGraticule draws the division lines, markers of X=0 on the bottom
+// edge and Y=0 on the left edge and the volts per division readouts
+// of both axes.
+func (plot Plot) DrawXYGraticule(g XYGraticule) {
+	r := g.Rect
+	for i := 1; i < DivRows; i++ {
+		y := r.Min.Y + i*r.Dy()/DivRows
+		plot.DrawLine(image.Point{r.Min.X, y}, image.Point{r.Max.X, y}, r, ColorGrey)
+	}
+	for i := 1; i < DivCols; i++ {
+		x := r.Min.X + i*r.Dx()/DivCols
+		plot.DrawLine(image.Point{x, r.Min.Y}, image.Point{x, r.Max.Y}, r, ColorGrey)
+	}
+	m := xyMapper{rect: r, xp: g.X.Params, yp: g.Y.Params}
+	x := max(r.Min.X, min(r.Max.X-1, m.x(0)))
+	plot.drawMarker(image.Point{x, r.Max.Y - 1}, image.Point{0, -1}, r, g.X.Color)
+	plot.drawMarker(image.Point{r.Min.X, channelY(g.Y.Params, 0, r)}, image.Point{1, 0}, r, g.Y.Color)
+
+	pad := 2 * markerSize
+	x = r.Min.X + pad
+	for _, a := range []struct {
+		axis string
+		ch   ChannelMarker
+	}{{"X", g.X}, {"Y", g.Y}} {
+		text := fmt.Sprintf("%s: %s %s/div", a.axis, a.ch.ID, formatSI(a.ch.Params.PerDiv, "V"))
+		x += drawText(plot.RGBA, image.Point{x, r.Max.Y - pad}, text, a.ch.Color, readoutFontSize) + pad
+	}
+}
+
+// XYLayer draws the most recent sweep of one channel against another,
+// over the XY graticule.
+// XYLayer implements scope.DisplayLayer, the channel params set with
+// SetChannel define the scale and the offset of the axes.
+type XYLayer struct {
+	layer
+	x, y scope.ChanID
+	cols map[scope.ChanID]color.RGBA
+	xs   []scope.Voltage
+	ys   []scope.Voltage
+}
+
+// NewXYLayer returns an XYLayer rendering images of given size,
+// plotting channel y against channel x within rect.
+func NewXYLayer(size image.Point, rect image.Rectangle, x, y scope.ChanID) *XYLayer {
+	l := &XYLayer{
+		x:    x,
+		y:    y,
+		cols: make(map[scope.ChanID]color.RGBA),
+	}
+	l.init(size, rect)
+	return l
+}
+
+// SetColor sets the color of the readouts of channel ch.
+// The trace is drawn in the color of the Y channel.
+func (l *XYLayer) SetColor(ch scope.ChanID, col color.RGBA) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cols[ch] = col
+	l.dirty = true
+}
+
+// Add replaces the displayed sweep with the samples of the X and Y
+// channels found in data. The samples are copied.
+func (l *XYLayer) Add(data []scope.ChannelData) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, d := range data {
+		switch d.ID {
+		case l.x:
+			l.xs = append(l.xs[:0], d.Samples...)
+		case l.y:
+			l.ys = append(l.ys[:0], d.Samples...)
+		}
+	}
+	l.dirty = true
+}
+
+// color returns the color of channel ch.
+// Must be called with l.mu held.
+func (l *XYLayer) color(ch scope.ChanID) color.RGBA {
+	if col, ok := l.cols[ch]; ok {
+		return col
+	}
+	return ColorBlack
+}
+
+// Render returns the image of the XY display, or nil if it did not change
+// since the last Render.
+func (l *XYLayer) Render() *image.RGBA {
+	return l.render(func() {
+		x := ChannelMarker{ID: l.x, Params: l.traceParams(l.x), Color: l.color(l.x)}
+		y := ChannelMarker{ID: l.y, Params: l.traceParams(l.y), Color: l.color(l.y)}
+		l.plot.DrawXYGraticule(XYGraticule{Rect: l.rect, X: x, Y: y})
+		l.plot.DrawXY(l.xs, l.ys, x.Params, y.Params, l.rect, y.Color)
+	})
+}
+
+// DrawXYFromDevice draws the samples of channel y against channel x
+// from the device in the plot.
+func (plot Plot) DrawXYFromDevice(dev scope.Device, x, y scope.ChanID, traceParams map[scope.ChanID]scope.TraceParams, cols map[scope.ChanID]color.RGBA) error {
+	rec := &compat.Recorder{TB: scope.Millisecond}
+	dev.Attach(rec)
+	dev.Start()
+	defer dev.Stop()
+	data := <-rec.Data
+	var xs, ys []scope.Voltage
+	foundX, foundY := false, false
+	for _, ch := range data.Channels {
+		if ch.ID == x {
+			xs, foundX = ch.Samples, true
+		}
+		if ch.ID == y {
+			ys, foundY = ch.Samples, true
+		}
+	}
+	if !foundX || !foundY {
+		return fmt.Errorf("device %s does not have channels %s and %s", dev, x, y)
+	}
+	g := XYGraticule{Rect: plot.Bounds(), X: channelMarker(x, traceParams, cols), Y: channelMarker(y, traceParams, cols)}
+	plot.DrawXY(xs, ys, g.X.Params, g.Y.Params, g.Rect, g.Y.Color)
+	if plot.graticule {
+		plot.DrawXYGraticule(g)
+	}
+	return nil
+}
+
+// XYPlotToPng creates a plot of the samples of channel y against
+// channel x from the device and saves it as PNG.
+func XYPlotToPng(dev scope.Device, width, height int, x, y scope.ChanID, traceParams map[scope.ChanID]scope.TraceParams, cols map[scope.ChanID]color.RGBA, outputFile string) error {
+	plot := Plot{
+		RGBA:      image.NewRGBA(image.Rect(0, 0, width, height)),
+		interp:    SincInterpolator,
+		down:      downsampler(),
+		line:      lineDrawing(),
+		width:     *lineWidth,
+		graticule: *showGraticule,
+	}
+	if err := plot.DrawXYFromDevice(dev, x, y, traceParams, cols); err != nil {
+		return err
+	}
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, plot)
+}
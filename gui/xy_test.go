@@ -0,0 +1,96 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+func TestDrawXY(t *testing.T) {
+	plot := Plot{RGBA: image.NewRGBA(image.Rect(0, 0, 101, 81))}
+	// X from -0.5V (left edge) to 0.5V (right edge),
+	// Y from -0.5V (bottom) to 0.5V (top).
+	xp := scope.TraceParams{Zero: 0.5, PerDiv: 0.1}
+	yp := scope.TraceParams{Zero: 0.5, PerDiv: 0.125}
+	// a diagonal from the bottom left to the top right corner
+	// and a line to a point far outside of the plot.
+	x := []scope.Voltage{-0.5, 0.5, 1e12}
+	y := []scope.Voltage{-0.5, 0.5, 0.5}
+	plot.DrawXY(x, y, xp, yp, plot.Bounds(), ColorRed)
+	for _, tc := range []struct {
+		p    image.Point
+		want color.RGBA
+	}{
+		{image.Point{0, 80}, ColorRed},
+		{image.Point{50, 40}, ColorRed},
+		{image.Point{100, 0}, ColorRed},
+		{image.Point{50, 0}, color.RGBA{}},
+		{image.Point{0, 0}, color.RGBA{}},
+	} {
+		if got := plot.RGBAAt(tc.p.X, tc.p.Y); got != tc.want {
+			t.Errorf("pixel %v: got %v, want %v", tc.p, got, tc.want)
+		}
+	}
+}
+
+func TestXYLayer(t *testing.T) {
+	l := NewXYLayer(layerSize, layerRect, "x", "y")
+	l.SetChannel("x", 0.5, 0.1)
+	l.SetChannel("y", 0.5, 0.125)
+	l.SetColor("y", ColorBlue)
+	// the samples of other channels are ignored.
+	l.Add([]scope.ChannelData{
+		{ID: "x", Samples: []scope.Voltage{-0.3, 0.3}},
+		{ID: "other", Samples: []scope.Voltage{1, 1}},
+		{ID: "y", Samples: []scope.Voltage{0, 0}},
+	})
+	img := l.Render()
+	if img == nil {
+		t.Fatalf("Render(): got nil, want the XY display")
+	}
+	// a horizontal line through the center, from X=-0.3V to X=0.3V.
+	if !hasColor(img, image.Rect(20, 40, 79, 41), ColorBlue) {
+		t.Errorf("Render(): want a blue horizontal line in row 40")
+	}
+	if hasColor(img, image.Rect(0, 0, 99, 30), ColorBlue) {
+		t.Errorf("Render(): got blue pixels above row 30, want none")
+	}
+	if l.Render() != nil {
+		t.Errorf("second Render(): got an image, want nil")
+	}
+}
+
+func TestParseXY(t *testing.T) {
+	chans := []scope.ChanID{"sin", "triangle", "diff"}
+	for _, tc := range []struct {
+		in      string
+		x, y    scope.ChanID
+		wantErr bool
+	}{
+		{in: "sin,diff", x: "sin", y: "diff"},
+		{in: "sin", wantErr: true},
+		{in: "sin,triangle,diff", wantErr: true},
+		{in: "sin,cos", wantErr: true},
+	} {
+		x, y, err := ParseXY(tc.in, chans)
+		if gotErr := err != nil; gotErr != tc.wantErr || x != tc.x || y != tc.y {
+			t.Errorf("ParseXY(%q): got %q, %q, error %v, want %q, %q, error: %v", tc.in, x, y, err, tc.x, tc.y, tc.wantErr)
+		}
+	}
+}
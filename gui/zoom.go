@@ -0,0 +1,63 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import "github.com/zagrodzki/goscope/scope"
+
+// Zoom selects the part of a sweep displayed magnified.
+// The zero value selects the whole sweep.
+type Zoom struct {
+	// Window is the length of the displayed part of the sweep,
+	// 0 means the whole sweep.
+	Window scope.Duration
+	// Center is the time of the center of the displayed part,
+	// relative to the beginning of the sweep.
+	Center scope.Duration
+}
+
+// Range returns the indices [begin, end) of the samples within the zoom
+// window, in a sweep of n samples taken every interval. The window is
+// moved to fit in the sweep and it spans at least two samples.
+func (z Zoom) Range(n int, interval scope.Duration) (begin, end int) {
+	if z.Window == 0 || interval == 0 {
+		return 0, n
+	}
+	w := int(z.Window / interval)
+	if w < 2 {
+		w = 2
+	}
+	if w > n {
+		w = n
+	}
+	begin = int(z.Center/interval) - w/2
+	if begin > n-w {
+		begin = n - w
+	}
+	if begin < 0 {
+		begin = 0
+	}
+	return begin, begin + w
+}
+
+// Apply returns the samples of data, taken every interval, within the zoom
+// window. The returned data shares the samples with data.
+func (z Zoom) Apply(data []scope.ChannelData, interval scope.Duration) []scope.ChannelData {
+	ret := make([]scope.ChannelData, len(data))
+	for i, d := range data {
+		begin, end := z.Range(len(d.Samples), interval)
+		ret[i] = scope.ChannelData{ID: d.ID, Samples: d.Samples[begin:end]}
+	}
+	return ret
+}
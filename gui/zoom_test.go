@@ -0,0 +1,57 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package gui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+func TestZoomRange(t *testing.T) {
+	for _, tc := range []struct {
+		desc       string
+		z          Zoom
+		begin, end int
+	}{
+		{"no zoom", Zoom{}, 0, 100},
+		{"centered", Zoom{Window: 10 * scope.Millisecond, Center: 50 * scope.Millisecond}, 45, 55},
+		{"at the beginning", Zoom{Window: 10 * scope.Millisecond, Center: 2 * scope.Millisecond}, 0, 10},
+		{"at the end", Zoom{Window: 20 * scope.Millisecond, Center: 99 * scope.Millisecond}, 80, 100},
+		{"wider than sweep", Zoom{Window: scope.Second, Center: 10 * scope.Millisecond}, 0, 100},
+		{"narrower than sample", Zoom{Window: scope.Microsecond, Center: 30 * scope.Millisecond}, 29, 31},
+	} {
+		begin, end := tc.z.Range(100, scope.Millisecond)
+		if begin != tc.begin || end != tc.end {
+			t.Errorf("%s: Range(): got [%d, %d), want [%d, %d)", tc.desc, begin, end, tc.begin, tc.end)
+		}
+	}
+}
+
+func TestZoomApply(t *testing.T) {
+	data := []scope.ChannelData{
+		{ID: "a", Samples: []scope.Voltage{0, 1, 2, 3, 4, 5, 6, 7}},
+		{ID: "b", Samples: []scope.Voltage{7, 6, 5, 4, 3, 2, 1, 0}},
+	}
+	z := Zoom{Window: 3 * scope.Millisecond, Center: 4 * scope.Millisecond}
+	want := []scope.ChannelData{
+		{ID: "a", Samples: []scope.Voltage{3, 4, 5}},
+		{ID: "b", Samples: []scope.Voltage{4, 3, 2}},
+	}
+	if got := z.Apply(data, scope.Millisecond); !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply(): got %v, want %v", got, want)
+	}
+}
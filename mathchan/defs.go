@@ -0,0 +1,72 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package mathchan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Defs are the definitions of math channels, as "name=expression",
+// e.g. "diff=(CH1-CH2)*10". Defs implements flag.Value, every Set
+// adds a definition, so that a repeated command line flag defines
+// several channels.
+type Defs []string
+
+// String returns the definitions separated by spaces.
+func (d *Defs) String() string { return strings.Join(*d, " ") }
+
+// Set adds the definition s.
+func (d *Defs) Set(s string) error {
+	if _, _, err := parseDef(s); err != nil {
+		return err
+	}
+	*d = append(*d, s)
+	return nil
+}
+
+// parseDef parses a single definition.
+func parseDef(s string) (scope.ChanID, *Expr, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", nil, fmt.Errorf("math channel %q: want name=expression", s)
+	}
+	e, err := Compile(parts[1])
+	if err != nil {
+		return "", nil, fmt.Errorf("math channel %s: %v", parts[0], err)
+	}
+	return scope.ChanID(parts[0]), e, nil
+}
+
+// Wrap returns dev with the math channels added, or dev itself
+// if there are no definitions.
+func (d Defs) Wrap(dev scope.Device) (scope.Device, error) {
+	if len(d) == 0 {
+		return dev, nil
+	}
+	md := New(dev)
+	for _, s := range d {
+		id, e, err := parseDef(s)
+		if err != nil {
+			return nil, err
+		}
+		if err := md.Add(id, e); err != nil {
+			return nil, err
+		}
+	}
+	return md, nil
+}
@@ -0,0 +1,330 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package mathchan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Expr is a Func computing the samples from an arithmetic expression,
+// e.g. "(CH1 - CH2) * 10" or "lowpass(CH1, 10kHz)".
+//
+// Expressions consist of:
+//   - channel names, referring to the samples of the channels,
+//   - numbers, optionally followed by an SI prefix and a unit,
+//     e.g. 10k, 1.5mV or 10kHz,
+//   - operators +, -, * and /, with the usual precedence, and parentheses,
+//   - functions:
+//     abs(x) - absolute value,
+//     integrate(x) - integral of x over time, in unit·seconds,
+//     deriv(x) - derivative of x over time, in units per second,
+//     lowpass(x, f) - first order low pass filter with cutoff frequency f,
+//     highpass(x, f) - first order high pass filter with cutoff frequency f.
+//
+// Division by 0 yields 0, like Div.
+type Expr struct {
+	src  string
+	root node
+	// chans are the names of the channels used in the expression,
+	// in the order of the first appearance.
+	chans []scope.ChanID
+	buf   []float64
+}
+
+// Compile parses the expression src.
+func Compile(src string) (*Expr, error) {
+	p := &parser{src: src, chanIdx: make(map[scope.ChanID]int)}
+	p.next()
+	root, err := p.parseExpr()
+	if err == nil && p.tok.kind != tokEOF {
+		err = p.errorf("unexpected %s", p.tok)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{src: src, root: root, chans: p.chans}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string { return e.src }
+
+// Sources returns the channels used in the expression.
+func (e *Expr) Sources() []scope.ChanID { return e.chans }
+
+// Reset resets the state of the functions in the expression,
+// e.g. the sum accumulated by integrate.
+func (e *Expr) Reset(interval scope.Duration) {
+	e.root.reset(float64(interval) / float64(scope.Second))
+}
+
+// Compute evaluates the expression for every sample of the sources.
+func (e *Expr) Compute(out []scope.Voltage, src [][]scope.Voltage) {
+	if cap(e.buf) < len(out) {
+		e.buf = make([]float64, len(out))
+	}
+	buf := e.buf[:len(out)]
+	e.root.eval(src, buf)
+	for i, v := range buf {
+		out[i] = scope.Voltage(v)
+	}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	pos  int
+	text string
+	num  float64
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+var siPrefixes = map[string]float64{
+	"p": 1e-12,
+	"n": 1e-9,
+	"u": 1e-6,
+	"µ": 1e-6,
+	"m": 1e-3,
+	"k": 1e3,
+	"M": 1e6,
+	"G": 1e9,
+}
+
+var units = []string{"Hz", "V", "A", "s"}
+
+type parser struct {
+	src     string
+	pos     int
+	tok     token
+	chans   []scope.ChanID
+	chanIdx map[scope.ChanID]int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("expression %q, position %d: %s", p.src, p.tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+// next reads the next token from the source.
+func (p *parser) next() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos == len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+	rest := p.src[p.pos:]
+	c := rest[0]
+	r, size := utf8.DecodeRuneInString(rest)
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		n := strings.IndexFunc(rest, func(r rune) bool {
+			return !unicode.IsDigit(r) && r != '.' && r != 'e' && r != 'E'
+		})
+		if n < 0 {
+			n = len(rest)
+		}
+		// exponent sign
+		for n < len(rest) && (rest[n] == '+' || rest[n] == '-') && (rest[n-1] == 'e' || rest[n-1] == 'E') {
+			n++
+			for n < len(rest) && rest[n] >= '0' && rest[n] <= '9' {
+				n++
+			}
+		}
+		num, err := strconv.ParseFloat(rest[:n], 64)
+		if err != nil {
+			// reported by the parser as an unexpected token.
+			p.tok = token{kind: tokOp, pos: start, text: rest[:n]}
+			p.pos += n
+			return
+		}
+		for pfx, mul := range siPrefixes {
+			if strings.HasPrefix(rest[n:], pfx) {
+				num *= mul
+				n += len(pfx)
+				break
+			}
+		}
+		for _, u := range units {
+			if strings.HasPrefix(rest[n:], u) {
+				n += len(u)
+				break
+			}
+		}
+		p.tok = token{kind: tokNumber, pos: start, text: rest[:n], num: num}
+		p.pos += n
+	case c == '_' || unicode.IsLetter(r):
+		n := strings.IndexFunc(rest, func(r rune) bool {
+			return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if n < 0 {
+			n = len(rest)
+		}
+		p.tok = token{kind: tokIdent, pos: start, text: rest[:n]}
+		p.pos += n
+	default:
+		p.tok = token{kind: tokOp, pos: start, text: rest[:size]}
+		p.pos += size
+	}
+}
+
+func (p *parser) isOp(ops ...string) bool {
+	if p.tok.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if p.tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOp(op) {
+		return p.errorf("expected %q, got %s", op, p.tok)
+	}
+	p.next()
+	return nil
+}
+
+// parseExpr parses a sum of terms.
+func (p *parser) parseExpr() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+", "-") {
+		op := p.tok.text
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = newBinary(op, left, right)
+	}
+	return left, nil
+}
+
+// parseTerm parses a product of factors.
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*", "/") {
+		op := p.tok.text
+		p.next()
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = newBinary(op, left, right)
+	}
+	return left, nil
+}
+
+// parseFactor parses a number, a channel, a function call,
+// a negation or an expression in parentheses.
+func (p *parser) parseFactor() (node, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokNumber:
+		p.next()
+		return constant(tok.num), nil
+	case p.isOp("-"):
+		p.next()
+		x, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return newBinary("*", constant(-1), x), nil
+	case p.isOp("("):
+		p.next()
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+	case tok.kind == tokIdent:
+		p.next()
+		if p.isOp("(") {
+			return p.parseCall(tok)
+		}
+		id := scope.ChanID(tok.text)
+		idx, ok := p.chanIdx[id]
+		if !ok {
+			idx = len(p.chans)
+			p.chanIdx[id] = idx
+			p.chans = append(p.chans, id)
+		}
+		return &channelNode{idx: idx}, nil
+	}
+	return nil, p.errorf("unexpected %s", tok)
+}
+
+// parseCall parses the arguments of a call to function fn.
+func (p *parser) parseCall(fn token) (node, error) {
+	f, ok := functions[fn.text]
+	if !ok {
+		p.tok = fn
+		return nil, p.errorf("unknown function %s", fn.text)
+	}
+	p.next()
+	var args []node
+	for !p.isOp(")") {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if len(args) != f.args {
+		p.tok = fn
+		return nil, p.errorf("%s takes %d arguments, got %d", fn.text, f.args, len(args))
+	}
+	n, err := f.new(args)
+	if err != nil {
+		p.tok = fn
+		return nil, p.errorf("%s: %v", fn.text, err)
+	}
+	p.next()
+	return n, nil
+}
@@ -0,0 +1,136 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package mathchan
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+func TestCompileErrors(t *testing.T) {
+	for _, tc := range []struct {
+		src, wantErr string
+	}{
+		{"", "position 1: unexpected end of expression"},
+		{"CH1 +", "position 6: unexpected end of expression"},
+		{"(CH1", `position 5: expected ")", got end of expression`},
+		{"CH1 CH2", `position 5: unexpected "CH2"`},
+		{"1.2.3", `position 1: unexpected "1.2.3"`},
+		{"sqrt(CH1)", "position 1: unknown function sqrt"},
+		{"2 * abs(CH1, CH2)", "position 5: abs takes 1 arguments, got 2"},
+		{"lowpass(CH1, CH2)", "cutoff frequency must be a constant"},
+		{"lowpass(CH1, -1kHz)", "cutoff frequency must be positive"},
+		{"CH1 % 2", `position 5: unexpected "%"`},
+	} {
+		_, err := Compile(tc.src)
+		if err == nil {
+			t.Errorf("Compile(%q): no error, want %q", tc.src, tc.wantErr)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("Compile(%q): got error %q, want %q", tc.src, err, tc.wantErr)
+		}
+	}
+}
+
+func TestExpr(t *testing.T) {
+	v := func(s ...scope.Voltage) []scope.Voltage { return s }
+	chans := map[scope.ChanID][]scope.Voltage{
+		"CH1": v(1, 2, 3, 4),
+		"CH2": v(0.5, 0.5, -1, 0),
+		"sin": v(0, 1, 0, -1),
+	}
+	for _, tc := range []struct {
+		src      string
+		wantSrcs []scope.ChanID
+		want     []scope.Voltage
+	}{
+		{"(CH1 - CH2) * 10", []scope.ChanID{"CH1", "CH2"}, v(5, 15, 40, 40)},
+		{"CH1 - CH2 * 10", []scope.ChanID{"CH1", "CH2"}, v(-4, -3, 13, 4)},
+		{"-CH1 + 2*-CH2", []scope.ChanID{"CH1", "CH2"}, v(-2, -3, -1, -4)},
+		{"CH1 / CH2", []scope.ChanID{"CH1", "CH2"}, v(2, 4, -3, 0)},
+		{"CH1 / 2 / 2", []scope.ChanID{"CH1"}, v(0.25, 0.5, 0.75, 1)},
+		{"abs(CH2) + abs(sin) - CH2", []scope.ChanID{"CH2", "sin"}, v(0, 1, 2, 1)},
+		{"CH1 * 1.5k - 1e3*CH1 + 500m", []scope.ChanID{"CH1"}, v(500.5, 1000.5, 1500.5, 2000.5)},
+		// samples taken every millisecond.
+		{"integrate(CH1)", []scope.ChanID{"CH1"}, v(0, 1.5e-3, 4e-3, 7.5e-3)},
+		{"deriv(sin)", []scope.ChanID{"sin"}, v(0, 1000, -1000, -1000)},
+		{"deriv(integrate(CH1))", []scope.ChanID{"CH1"}, v(0, 1.5, 2.5, 3.5)},
+		{"2.5", nil, v(2.5, 2.5, 2.5, 2.5)},
+	} {
+		e, err := Compile(tc.src)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tc.src, err)
+			continue
+		}
+		if got := e.Sources(); !reflect.DeepEqual(got, tc.wantSrcs) {
+			t.Errorf("Compile(%q).Sources(): got %v, want %v", tc.src, got, tc.wantSrcs)
+		}
+		var src [][]scope.Voltage
+		for _, id := range e.Sources() {
+			src = append(src, chans[id])
+		}
+		e.Reset(scope.Millisecond)
+		got := make([]scope.Voltage, 4)
+		e.Compute(got, src)
+		for i := range got {
+			if math.Abs(float64(got[i]-tc.want[i])) > 1e-9 {
+				t.Errorf("%s: got %v, want %v", tc.src, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestLowpass(t *testing.T) {
+	// a step from 0 to 1V, sampled every microsecond.
+	step := make([]scope.Voltage, 2000)
+	for i := 1000; i < len(step); i++ {
+		step[i] = 1
+	}
+	for _, tc := range []struct {
+		src string
+		// value one time constant (1/(2π·1kHz) ≈ 159µs) after the step.
+		want scope.Voltage
+	}{
+		{"lowpass(CH1, 1kHz)", 1 - 1/math.E},
+		{"highpass(CH1, 1kHz)", 1 / math.E},
+	} {
+		e, err := Compile(tc.src)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", tc.src, err)
+		}
+		e.Reset(scope.Microsecond)
+		// the filter state is kept between chunks.
+		got := make([]scope.Voltage, len(step))
+		for i := 0; i < len(step); i += 300 {
+			end := i + 300
+			if end > len(step) {
+				end = len(step)
+			}
+			e.Compute(got[i:end], [][]scope.Voltage{step[i:end]})
+		}
+		if v := got[999]; math.Abs(float64(v)) > 1e-9 {
+			t.Errorf("%s: got %v before the step, want 0", tc.src, v)
+		}
+		if v := got[1000+159]; math.Abs(float64(v-tc.want)) > 0.01 {
+			t.Errorf("%s: got %v after one time constant, want %v", tc.src, v, tc.want)
+		}
+	}
+}
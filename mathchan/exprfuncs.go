@@ -0,0 +1,222 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package mathchan
+
+import (
+	"errors"
+	"math"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// node is a compiled part of an expression.
+type node interface {
+	// reset resets the state of the node, for samples taken
+	// every dt seconds.
+	reset(dt float64)
+	// eval writes to out the values computed from the source channels.
+	eval(src [][]scope.Voltage, out []float64)
+}
+
+type constant float64
+
+func (constant) reset(float64) {}
+func (c constant) eval(_ [][]scope.Voltage, out []float64) {
+	for i := range out {
+		out[i] = float64(c)
+	}
+}
+
+type channelNode struct {
+	idx int
+}
+
+func (*channelNode) reset(float64) {}
+func (c *channelNode) eval(src [][]scope.Voltage, out []float64) {
+	for i, v := range src[c.idx] {
+		out[i] = float64(v)
+	}
+}
+
+type binaryNode struct {
+	op   func(a, b float64) float64
+	a, b node
+	buf  []float64
+}
+
+var binaryOps = map[string]func(a, b float64) float64{
+	"+": func(a, b float64) float64 { return a + b },
+	"-": func(a, b float64) float64 { return a - b },
+	"*": func(a, b float64) float64 { return a * b },
+	"/": func(a, b float64) float64 {
+		if b == 0 {
+			return 0
+		}
+		return a / b
+	},
+}
+
+// newBinary returns a node applying op to a and b. Operations on
+// constants are evaluated immediately.
+func newBinary(op string, a, b node) node {
+	f := binaryOps[op]
+	ca, aConst := a.(constant)
+	cb, bConst := b.(constant)
+	if aConst && bConst {
+		return constant(f(float64(ca), float64(cb)))
+	}
+	return &binaryNode{op: f, a: a, b: b}
+}
+
+func (n *binaryNode) reset(dt float64) {
+	n.a.reset(dt)
+	n.b.reset(dt)
+}
+
+func (n *binaryNode) eval(src [][]scope.Voltage, out []float64) {
+	n.a.eval(src, out)
+	if cap(n.buf) < len(out) {
+		n.buf = make([]float64, len(out))
+	}
+	b := n.buf[:len(out)]
+	n.b.eval(src, b)
+	for i := range out {
+		out[i] = n.op(out[i], b[i])
+	}
+}
+
+type absNode struct {
+	x node
+}
+
+func (n *absNode) reset(dt float64) { n.x.reset(dt) }
+func (n *absNode) eval(src [][]scope.Voltage, out []float64) {
+	n.x.eval(src, out)
+	for i, v := range out {
+		out[i] = math.Abs(v)
+	}
+}
+
+// integrateNode integrates x using the trapezoidal rule.
+type integrateNode struct {
+	x    node
+	dt   float64
+	sum  float64
+	prev float64
+	init bool
+}
+
+func (n *integrateNode) reset(dt float64) {
+	n.x.reset(dt)
+	n.dt = dt
+	n.sum, n.prev, n.init = 0, 0, false
+}
+
+func (n *integrateNode) eval(src [][]scope.Voltage, out []float64) {
+	n.x.eval(src, out)
+	for i, v := range out {
+		if n.init {
+			n.sum += (n.prev + v) / 2 * n.dt
+		}
+		n.prev, n.init = v, true
+		out[i] = n.sum
+	}
+}
+
+// derivNode computes the backward difference of x.
+// The derivative at the first sample after reset is 0.
+type derivNode struct {
+	x    node
+	dt   float64
+	prev float64
+	init bool
+}
+
+func (n *derivNode) reset(dt float64) {
+	n.x.reset(dt)
+	n.dt = dt
+	n.prev, n.init = 0, false
+}
+
+func (n *derivNode) eval(src [][]scope.Voltage, out []float64) {
+	n.x.eval(src, out)
+	for i, v := range out {
+		d := 0.0
+		if n.init {
+			d = (v - n.prev) / n.dt
+		}
+		n.prev, n.init = v, true
+		out[i] = d
+	}
+}
+
+// lowpassNode is a first order IIR low pass filter, the discrete
+// equivalent of an RC filter. If high is set, the node returns
+// the input with the low pass filtered signal subtracted.
+type lowpassNode struct {
+	x     node
+	fc    float64
+	high  bool
+	alpha float64
+	y     float64
+	init  bool
+}
+
+func (n *lowpassNode) reset(dt float64) {
+	n.x.reset(dt)
+	rc := 1 / (2 * math.Pi * n.fc)
+	n.alpha = dt / (rc + dt)
+	n.y, n.init = 0, false
+}
+
+func (n *lowpassNode) eval(src [][]scope.Voltage, out []float64) {
+	n.x.eval(src, out)
+	for i, v := range out {
+		if !n.init {
+			// start from a steady state to avoid the initial transient.
+			n.y, n.init = v, true
+		}
+		n.y += n.alpha * (v - n.y)
+		if n.high {
+			out[i] = v - n.y
+		} else {
+			out[i] = n.y
+		}
+	}
+}
+
+func newFilter(high bool) func([]node) (node, error) {
+	return func(args []node) (node, error) {
+		fc, ok := args[1].(constant)
+		if !ok {
+			return nil, errors.New("cutoff frequency must be a constant")
+		}
+		if fc <= 0 {
+			return nil, errors.New("cutoff frequency must be positive")
+		}
+		return &lowpassNode{x: args[0], fc: float64(fc), high: high}, nil
+	}
+}
+
+var functions = map[string]struct {
+	args int
+	new  func([]node) (node, error)
+}{
+	"abs":       {1, func(a []node) (node, error) { return &absNode{x: a[0]}, nil }},
+	"integrate": {1, func(a []node) (node, error) { return &integrateNode{x: a[0]}, nil }},
+	"deriv":     {1, func(a []node) (node, error) { return &derivNode{x: a[0]}, nil }},
+	"lowpass":   {2, newFilter(false)},
+	"highpass":  {2, newFilter(true)},
+}
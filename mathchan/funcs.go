@@ -0,0 +1,82 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package mathchan
+
+import "github.com/zagrodzki/goscope/scope"
+
+// stateless is a Func computing every output sample only from
+// the source samples at the same position.
+type stateless struct {
+	src []scope.ChanID
+	op  func(v []scope.Voltage) scope.Voltage
+}
+
+func (f stateless) Sources() []scope.ChanID { return f.src }
+func (stateless) Reset(scope.Duration)      {}
+func (f stateless) Compute(out []scope.Voltage, src [][]scope.Voltage) {
+	v := make([]scope.Voltage, len(src))
+	for i := range out {
+		for j := range src {
+			v[j] = src[j][i]
+		}
+		out[i] = f.op(v)
+	}
+}
+
+func binary(a, b scope.ChanID, op func(a, b scope.Voltage) scope.Voltage) Func {
+	return stateless{
+		src: []scope.ChanID{a, b},
+		op:  func(v []scope.Voltage) scope.Voltage { return op(v[0], v[1]) },
+	}
+}
+
+// Add returns a function computing a+b.
+func Add(a, b scope.ChanID) Func {
+	return binary(a, b, func(a, b scope.Voltage) scope.Voltage { return a + b })
+}
+
+// Sub returns a function computing a-b.
+func Sub(a, b scope.ChanID) Func {
+	return binary(a, b, func(a, b scope.Voltage) scope.Voltage { return a - b })
+}
+
+// Mul returns a function computing a×b.
+func Mul(a, b scope.ChanID) Func {
+	return binary(a, b, func(a, b scope.Voltage) scope.Voltage { return a * b })
+}
+
+// Div returns a function computing a/b. Samples where b is 0 are set to 0.
+func Div(a, b scope.ChanID) Func {
+	return binary(a, b, func(a, b scope.Voltage) scope.Voltage {
+		if b == 0 {
+			return 0
+		}
+		return a / b
+	})
+}
+
+// Neg returns a function computing -a.
+func Neg(a scope.ChanID) Func {
+	return Scale(a, -1, 0)
+}
+
+// Scale returns a function computing a×gain+offset, e.g. to account
+// for the probe attenuation.
+func Scale(a scope.ChanID, gain float64, offset scope.Voltage) Func {
+	return stateless{
+		src: []scope.ChanID{a},
+		op:  func(v []scope.Voltage) scope.Voltage { return v[0]*scope.Voltage(gain) + offset },
+	}
+}
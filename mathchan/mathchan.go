@@ -0,0 +1,186 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package mathchan implements virtual channels computed from the samples
+// of other channels, e.g. a difference of two channels. Math channels are
+// delivered to the recorders as ordinary channel data, so they can be
+// triggered on, measured or drawn like the channels of the device.
+package mathchan
+
+import (
+	"fmt"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Func computes the samples of a math channel.
+type Func interface {
+	// Sources returns the IDs of the channels used by the function.
+	Sources() []scope.ChanID
+
+	// Reset prepares the function for a new stream of samples,
+	// taken every interval.
+	Reset(interval scope.Duration)
+
+	// Compute writes to out the samples computed from the samples
+	// of the sources, in the order returned by Sources.
+	// out and all the sources have the same length.
+	Compute(out []scope.Voltage, src [][]scope.Voltage)
+}
+
+type channel struct {
+	id scope.ChanID
+	f  Func
+}
+
+// Device adds math channels to the channels of the underlying device.
+// Device implements both scope.Device interface (used by UI)
+// and scope.DataRecorder interface (used by underlying device).
+//
+// To trigger on a math channel, create the trigger on top of Device,
+// after adding the channels.
+type Device struct {
+	scope.Device
+	chans []channel
+	rec   scope.DataRecorder
+	// done is closed when the run started by the last Reset finishes.
+	done chan struct{}
+}
+
+// New returns a Device without any math channels, passing through
+// the data of dev.
+func New(dev scope.Device) *Device {
+	return &Device{
+		Device: dev,
+	}
+}
+
+// Add adds a math channel id, computed by f. The sources of f have to
+// be channels of the underlying device or math channels added earlier.
+// Add should not be called while the device is running.
+func (d *Device) Add(id scope.ChanID, f Func) error {
+	known := make(map[scope.ChanID]bool)
+	for _, ch := range d.Channels() {
+		known[ch] = true
+	}
+	if known[id] {
+		return fmt.Errorf("channel %s already exists", id)
+	}
+	for _, src := range f.Sources() {
+		if !known[src] {
+			return fmt.Errorf("math channel %s: unknown source channel %s", id, src)
+		}
+	}
+	d.chans = append(d.chans, channel{id, f})
+	return nil
+}
+
+// Channels returns the channels of the underlying device,
+// followed by the math channels.
+func (d *Device) Channels() []scope.ChanID {
+	ret := append([]scope.ChanID(nil), d.Device.Channels()...)
+	for _, ch := range d.chans {
+		ret = append(ret, ch.id)
+	}
+	return ret
+}
+
+// Attach configures the device to pass the data to rec.
+func (d *Device) Attach(rec scope.DataRecorder) {
+	d.rec = rec
+	d.Device.Attach(d)
+}
+
+// TimeBase returns the timebase of the underlying recorder.
+func (d *Device) TimeBase() scope.Duration {
+	return d.rec.TimeBase()
+}
+
+// Reset initializes the recording. Reset waits for the previous
+// recording to finish, i.e. for the device to close its data channel.
+func (d *Device) Reset(i scope.Duration, ch <-chan []scope.ChannelData) {
+	if len(d.chans) == 0 {
+		d.rec.Reset(i, ch)
+		return
+	}
+	if d.done != nil {
+		// the functions are used by the previous run until it finishes.
+		<-d.done
+	}
+	for _, c := range d.chans {
+		c.f.Reset(i)
+	}
+	out := make(chan []scope.ChannelData, 2)
+	d.rec.Reset(i, out)
+	done := make(chan struct{})
+	d.done = done
+	go d.run(ch, out, done)
+}
+
+// Error passes the error down to the underlying recorder.
+func (d *Device) Error(err error) {
+	d.rec.Error(err)
+}
+
+func (d *Device) run(in <-chan []scope.ChannelData, out chan<- []scope.ChannelData, done chan<- struct{}) {
+	for data := range in {
+		out <- d.compute(data)
+	}
+	close(out)
+	close(done)
+}
+
+// compute returns data with the samples of the math channels appended.
+// A math channel is skipped if any of its sources is missing in data.
+func (d *Device) compute(data []scope.ChannelData) []scope.ChannelData {
+	if len(data) == 0 {
+		return data
+	}
+	ret := append(make([]scope.ChannelData, 0, len(data)+len(d.chans)), data...)
+	byID := make(map[scope.ChanID][]scope.Voltage, cap(ret))
+	for _, c := range data {
+		byID[c.ID] = c.Samples
+	}
+	for _, c := range d.chans {
+		srcIDs := c.f.Sources()
+		src := make([][]scope.Voltage, len(srcIDs))
+		n := -1
+		for i, id := range srcIDs {
+			s, ok := byID[id]
+			if !ok {
+				n = 0
+				break
+			}
+			if n < 0 || len(s) < n {
+				n = len(s)
+			}
+			src[i] = s
+		}
+		if n < 0 {
+			// a function without sources generates the samples itself.
+			n = len(data[0].Samples)
+		}
+		if n == 0 {
+			continue
+		}
+		for i := range src {
+			src[i] = src[i][:n]
+		}
+		samples := make([]scope.Voltage, n)
+		c.f.Compute(samples, src)
+		byID[c.id] = samples
+		ret = append(ret, scope.ChannelData{ID: c.id, Samples: samples})
+	}
+	return ret
+}
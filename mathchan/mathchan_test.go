@@ -0,0 +1,189 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package mathchan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zagrodzki/goscope/compat"
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/testutil"
+	"github.com/zagrodzki/goscope/triggers"
+)
+
+// fakeDev sends the chunks of data to the recorder on Start.
+type fakeDev struct {
+	rec    scope.DataRecorder
+	chunks [][]scope.ChannelData
+}
+
+func (*fakeDev) String() string                { return "fake" }
+func (*fakeDev) Channels() []scope.ChanID      { return []scope.ChanID{"a", "b"} }
+func (d *fakeDev) Attach(r scope.DataRecorder) { d.rec = r }
+func (*fakeDev) Stop()                         {}
+func (d *fakeDev) Start() {
+	ch := make(chan []scope.ChannelData)
+	d.rec.Reset(scope.Millisecond, ch)
+	go func() {
+		for _, c := range d.chunks {
+			ch <- c
+		}
+		close(ch)
+	}()
+}
+
+func TestAdd(t *testing.T) {
+	d := New(&fakeDev{})
+	for _, tc := range []struct {
+		id      scope.ChanID
+		f       Func
+		wantErr bool
+	}{
+		{"sum", Add("a", "b"), false},
+		{"a", Neg("b"), true},
+		{"sum", Neg("b"), true},
+		{"c", Neg("x"), true},
+		{"double sum", Scale("sum", 2, 0), false},
+	} {
+		err := d.Add(tc.id, tc.f)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("Add(%q): got error %v, want error: %v", tc.id, err, tc.wantErr)
+		}
+	}
+	if got, want := d.Channels(), []scope.ChanID{"a", "b", "sum", "double sum"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Channels(): got %v, want %v", got, want)
+	}
+	src := triggers.New(d).TriggerParams()[3]
+	if err := src.Set("double sum"); err != nil {
+		t.Errorf("trigger source: Set(math channel): %v", err)
+	}
+}
+
+func TestDevice(t *testing.T) {
+	v := func(s ...scope.Voltage) []scope.Voltage { return s }
+	dev := &fakeDev{
+		chunks: [][]scope.ChannelData{
+			{{ID: "a", Samples: v(1, 2, 3)}, {ID: "b", Samples: v(2, 0, -1)}},
+			// b missing, only the functions of a are computed.
+			{{ID: "a", Samples: v(4)}},
+		},
+	}
+	d := New(dev)
+	for _, c := range []struct {
+		id scope.ChanID
+		f  Func
+	}{
+		{"a+b", Add("a", "b")},
+		{"a-b", Sub("a", "b")},
+		{"a*b", Mul("a", "b")},
+		{"a/b", Div("a", "b")},
+		{"-a", Neg("a")},
+		{"10a+1", Scale("a", 10, 1)},
+		{"(a+b)*a", Mul("a+b", "a")},
+	} {
+		if err := d.Add(c.id, c.f); err != nil {
+			t.Fatalf("Add(%q): %v", c.id, err)
+		}
+	}
+	rec := &compat.Recorder{}
+	d.Attach(rec)
+	d.Start()
+	var got []map[scope.ChanID][]scope.Voltage
+	for data := range rec.Data {
+		chunk := make(map[scope.ChanID][]scope.Voltage)
+		for _, ch := range data.Channels {
+			chunk[ch.ID] = ch.Samples
+		}
+		got = append(got, chunk)
+	}
+	want := []map[scope.ChanID][]scope.Voltage{
+		{
+			"a":       v(1, 2, 3),
+			"b":       v(2, 0, -1),
+			"a+b":     v(3, 2, 2),
+			"a-b":     v(-1, 2, 4),
+			"a*b":     v(2, 0, -3),
+			"a/b":     v(0.5, 0, -3),
+			"-a":      v(-1, -2, -3),
+			"10a+1":   v(11, 21, 31),
+			"(a+b)*a": v(3, 4, 6),
+		},
+		{
+			"a":     v(4),
+			"-a":    v(-4),
+			"10a+1": v(41),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recorded data: got %v, want %v", got, want)
+	}
+}
+
+func TestRestart(t *testing.T) {
+	v := func(s ...scope.Voltage) []scope.Voltage { return s }
+	dev := &fakeDev{
+		chunks: [][]scope.ChannelData{
+			{{ID: "a", Samples: v(1, 2, 3)}, {ID: "b", Samples: v(2, 0, -1)}},
+			{{ID: "a", Samples: v(4, 5, 6)}, {ID: "b", Samples: v(1, 1, 1)}},
+		},
+	}
+	d := New(dev)
+	e, err := Compile("integrate(a-b)")
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if err := d.Add("int", e); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	rec := testutil.NewDiscardRecorder(scope.Millisecond)
+	d.Attach(rec)
+	// every Start resets the expression, while the previous run
+	// may still be computing it.
+	for i := 0; i < 3; i++ {
+		d.Start()
+	}
+	rec.Wait()
+}
+
+func TestDefs(t *testing.T) {
+	var d Defs
+	for _, s := range []string{"", "sum", "=a+b", "sum=a+"} {
+		if err := d.Set(s); err == nil {
+			t.Errorf("Set(%q): no error", s)
+		}
+	}
+	if dev, err := d.Wrap(&fakeDev{}); err != nil || dev.String() != "fake" {
+		t.Errorf("Wrap() without definitions: got %v, %v, want the device itself", dev, err)
+	}
+	for _, s := range []string{"sum=a+b", "double=sum*2"} {
+		if err := d.Set(s); err != nil {
+			t.Fatalf("Set(%q): %v", s, err)
+		}
+	}
+	dev, err := d.Wrap(&fakeDev{})
+	if err != nil {
+		t.Fatalf("Wrap(): %v", err)
+	}
+	if got, want := dev.Channels(), []scope.ChanID{"a", "b", "sum", "double"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Channels(): got %v, want %v", got, want)
+	}
+	if err := d.Set("x=c*2"); err != nil {
+		t.Fatalf("Set(x=c*2): %v", err)
+	}
+	if _, err := d.Wrap(&fakeDev{}); err == nil {
+		t.Errorf("Wrap() with an unknown source channel: no error")
+	}
+}
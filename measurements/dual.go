@@ -0,0 +1,229 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package measurements
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/mjibson/go-dsp/fft"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// DualResult holds the measurements comparing two channels sampled
+// at the same time, e.g. the input (A) and output (B) of a filter.
+type DualResult struct {
+	// A and B are the measurements of the individual channels.
+	A, B Result
+
+	// Delays is the number of rising edge pairs found in the sweep.
+	// Phase and Delay are only valid if Delays is positive.
+	Delays int
+	// Delay is the average time from a rising edge of A to the next
+	// rising edge of B.
+	Delay scope.Duration
+	// Phase is Delay relative to the period of A, in degrees,
+	// in the range (-180, 180]. Negative values mean B leads A.
+	Phase float64
+
+	// XCorrDelay is the absolute delay between A and B at the peak of
+	// their cross-correlation. XCorrLeads is set if B leads A.
+	XCorrDelay scope.Duration
+	XCorrLeads bool
+
+	// Ratio is the ratio of AC RMS voltages of B and A, Gain is
+	// the same ratio in decibels.
+	Ratio, Gain float64
+
+	acA, acB float64
+}
+
+// Valid returns true if the two channel measurement m could be
+// computed for the sweep.
+func (r DualResult) Valid(m Measurement) bool {
+	switch m {
+	case Phase:
+		return r.Delays > 0 && r.A.Cycles > 0
+	case Delay:
+		return r.Delays > 0
+	case XCorrDelay:
+		return r.acA > 0 && r.acB > 0
+	case Ratio:
+		return r.acA > 0
+	case Gain:
+		return r.acA > 0 && r.acB > 0
+	}
+	return false
+}
+
+// Value returns the value of the two channel measurement m in base units,
+// i.e. seconds, degrees, decibels or a plain ratio.
+// ok is false if the measurement could not be computed for the sweep.
+func (r DualResult) Value(m Measurement) (v float64, ok bool) {
+	if !r.Valid(m) {
+		return 0, false
+	}
+	switch m {
+	case Phase:
+		v = r.Phase
+	case Delay:
+		v = float64(r.Delay) / float64(scope.Second)
+	case XCorrDelay:
+		v = float64(r.XCorrDelay) / float64(scope.Second)
+		if r.XCorrLeads {
+			v = -v
+		}
+	case Ratio:
+		v = r.Ratio
+	case Gain:
+		v = r.Gain
+	}
+	return v, true
+}
+
+// Format returns the value of the two channel measurement m with its unit,
+// or "n/a" if the measurement could not be computed.
+func (r DualResult) Format(m Measurement) string {
+	v, ok := r.Value(m)
+	if !ok {
+		return "n/a"
+	}
+	return m.Format(v)
+}
+
+// MeasureDual computes the measurements of channels a and b, and the
+// measurements comparing them. Samples of both channels are taken
+// every interval, at the same time.
+func MeasureDual(a, b []scope.Voltage, interval scope.Duration) DualResult {
+	r := DualResult{
+		A: Measure(a, interval),
+		B: Measure(b, interval),
+	}
+	if len(b) < len(a) {
+		a = a[:len(b)]
+	}
+	if len(a) < len(b) {
+		b = b[:len(a)]
+	}
+	toDuration := func(samples float64) scope.Duration {
+		return scope.Duration(math.Round(samples * float64(interval)))
+	}
+
+	r.acA, r.acB = acRMS(r.A), acRMS(r.B)
+	if r.acA > 0 {
+		r.Ratio = r.acB / r.acA
+		if r.acB > 0 {
+			r.Gain = 20 * math.Log10(r.Ratio)
+		}
+	}
+	if r.acA > 0 && r.acB > 0 {
+		lag := xcorrLag(a, b)
+		r.XCorrLeads = lag < 0
+		r.XCorrDelay = toDuration(math.Abs(lag))
+	}
+
+	if r.A.Amplitude <= 0 || r.B.Amplitude <= 0 {
+		return r
+	}
+	ra := findEdges(a, r.A.Base, r.A.Amplitude).risingMids()
+	rb := findEdges(b, r.B.Base, r.B.Amplitude).risingMids()
+	var sum float64
+	j := 0
+	for _, p := range ra {
+		for j < len(rb) && rb[j] < p {
+			j++
+		}
+		if j == len(rb) {
+			break
+		}
+		sum += rb[j] - p
+		r.Delays++
+	}
+	if r.Delays == 0 {
+		return r
+	}
+	delay := sum / float64(r.Delays)
+	r.Delay = toDuration(delay)
+	if r.A.Cycles > 0 {
+		r.Phase = 360 * float64(r.Delay) / float64(r.A.Period)
+		for r.Phase > 180 {
+			r.Phase -= 360
+		}
+	}
+	return r
+}
+
+// acRMS returns the RMS voltage of the AC component of the signal,
+// over complete cycles if possible.
+func acRMS(r Result) float64 {
+	mean, rms := float64(r.Mean), float64(r.RMS)
+	if r.Cycles > 0 {
+		mean, rms = float64(r.CycleMean), float64(r.CycleRMS)
+	}
+	return math.Sqrt(math.Max(0, rms*rms-mean*mean))
+}
+
+// xcorrLag returns the lag of b relative to a, in samples, at the peak
+// of the cross-correlation of the AC components of a and b.
+// Lags up to half of the sweep length in either direction are considered.
+func xcorrLag(a, b []scope.Voltage) float64 {
+	n := len(a)
+	size := 1
+	for size < 2*n {
+		size <<= 1
+	}
+	fa := acSamples(a, size)
+	fb := acSamples(b, size)
+	for i := range fa {
+		fa[i] = cmplx.Conj(fa[i]) * fb[i]
+	}
+	corr := fft.IFFT(fa)
+	// at returns the correlation at lag k, positive if b lags behind a.
+	at := func(k int) float64 {
+		if k < 0 {
+			k += size
+		}
+		return real(corr[k])
+	}
+	maxLag := n / 2
+	best := 0
+	for k := -maxLag; k <= maxLag; k++ {
+		if at(k) > at(best) {
+			best = k
+		}
+	}
+	// parabolic interpolation of the peak between the neighbouring lags.
+	prev, cur, next := at(best-1), at(best), at(best+1)
+	lag := float64(best)
+	if d := prev - 2*cur + next; d < 0 && best > -maxLag && best < maxLag {
+		lag += 0.5 * (prev - next) / d
+	}
+	return lag
+}
+
+// acSamples returns the spectrum of the samples with the mean removed,
+// zero padded to size.
+func acSamples(samples []scope.Voltage, size int) []complex128 {
+	var mean float64
+	for _, v := range samples {
+		mean += float64(v)
+	}
+	mean /= float64(len(samples))
+	ret := make([]float64, size)
+	for i, v := range samples {
+		ret[i] = float64(v) - mean
+	}
+	return fft.FFTReal(ret)
+}
@@ -0,0 +1,127 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package measurements
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+func sine(n int, period, amplitude, phase float64) []scope.Voltage {
+	ret := make([]scope.Voltage, n)
+	for i := range ret {
+		ret[i] = scope.Voltage(amplitude * math.Sin(2*math.Pi*float64(i)/period-phase*math.Pi/180))
+	}
+	return ret
+}
+
+func square(n, period, delay int) []scope.Voltage {
+	ret := make([]scope.Voltage, n)
+	for i := range ret {
+		if (i+period-delay)%period < period/2 {
+			ret[i] = 1
+		}
+	}
+	return ret
+}
+
+// testInterval is the sampling interval of the test signals.
+const testInterval = scope.Microsecond
+
+// dualTolerance returns the allowed error of measurement m.
+// The delays are accurate to one sampling interval, the other
+// measurements to 2%.
+func dualTolerance(m Measurement, want float64) float64 {
+	switch m {
+	case Delay, XCorrDelay:
+		return float64(testInterval) / float64(scope.Second)
+	}
+	return 0.02 * math.Max(math.Abs(want), 0.1)
+}
+
+func TestDual(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		a, b []scope.Voltage
+		// want holds the expected values, NaN if not available.
+		want map[Measurement]float64
+	}{
+		{
+			desc: "low pass filter at cutoff",
+			a:    sine(1000, 100, 1, 0),
+			b:    sine(1000, 100, 1/math.Sqrt2, 45),
+			want: map[Measurement]float64{
+				Phase:      45,
+				Delay:      12.5e-6,
+				XCorrDelay: 12.5e-6,
+				Ratio:      1 / math.Sqrt2,
+				Gain:       -3.01,
+			},
+		},
+		{
+			desc: "B leads A",
+			a:    sine(1000, 100, 1, 0),
+			b:    sine(1000, 100, 2, -90),
+			want: map[Measurement]float64{
+				Phase:      -90,
+				Delay:      75e-6,
+				XCorrDelay: -25e-6,
+				Ratio:      2,
+				Gain:       6.02,
+			},
+		},
+		{
+			desc: "delayed square wave",
+			a:    square(1000, 40, 0),
+			b:    square(1000, 40, 5),
+			want: map[Measurement]float64{
+				Phase:      45,
+				Delay:      5e-6,
+				XCorrDelay: 5e-6,
+				Ratio:      1,
+				Gain:       0,
+			},
+		},
+		{
+			desc: "flat output",
+			a:    sine(1000, 100, 1, 0),
+			b:    make([]scope.Voltage, 1000),
+			want: map[Measurement]float64{
+				Phase:      math.NaN(),
+				Delay:      math.NaN(),
+				XCorrDelay: math.NaN(),
+				Ratio:      0,
+				Gain:       math.NaN(),
+			},
+		},
+	} {
+		r := MeasureDual(tc.a, tc.b, testInterval)
+		for _, m := range AllDual {
+			want := tc.want[m]
+			got, ok := r.Value(m)
+			switch {
+			case math.IsNaN(want) && ok:
+				t.Errorf("%s: %s: got %s, want n/a", tc.desc, m, m.Format(got))
+			case math.IsNaN(want):
+			case !ok:
+				t.Errorf("%s: %s: got n/a, want %s", tc.desc, m, m.Format(want))
+			case math.Abs(got-want) > dualTolerance(m, want):
+				t.Errorf("%s: %s: got %s, want %s", tc.desc, m, m.Format(got), m.Format(want))
+			}
+		}
+	}
+}
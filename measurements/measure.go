@@ -0,0 +1,223 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package measurements
+
+import (
+	"math"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+const (
+	// histBins is the number of bins in the histogram used to find
+	// the top and base levels of the signal.
+	histBins = 256
+	// a histogram mode is accepted as the signal level only if it holds
+	// at least modeFactor times the average count of the non-empty bins.
+	modeFactor = 2
+
+	lowRef  = 0.1
+	midRef  = 0.5
+	highRef = 0.9
+)
+
+// Measure computes the measurements of samples, taken every interval.
+func Measure(samples []scope.Voltage, interval scope.Duration) Result {
+	r := Result{numSamples: len(samples)}
+	if len(samples) == 0 {
+		return r
+	}
+	r.Max, r.Min = samples[0], samples[0]
+	for _, v := range samples {
+		if v > r.Max {
+			r.Max = v
+		}
+		if v < r.Min {
+			r.Min = v
+		}
+	}
+	r.PeakToPeak = r.Max - r.Min
+	r.Mean, r.RMS = meanRMS(samples)
+	r.Top, r.Base = topBase(samples, r.Min, r.Max)
+	r.Amplitude = r.Top - r.Base
+	if r.Amplitude <= 0 {
+		return r
+	}
+	r.Overshoot = float64(r.Max-r.Top) / float64(r.Amplitude)
+	r.Preshoot = float64(r.Base-r.Min) / float64(r.Amplitude)
+
+	toDuration := func(samples float64) scope.Duration {
+		return scope.Duration(math.Round(samples * float64(interval)))
+	}
+	e := findEdges(samples, r.Base, r.Amplitude)
+	if e.rising > 0 {
+		r.RisingEdges = e.rising
+		r.RiseTime = toDuration(e.riseSum / float64(e.rising))
+	}
+	if e.falling > 0 {
+		r.FallingEdges = e.falling
+		r.FallTime = toDuration(e.fallSum / float64(e.falling))
+	}
+
+	// edges alternate between rising and falling, a full cycle spans
+	// from one edge to the second next.
+	r.Cycles = (len(e.mids) - 1) / 2
+	if r.Cycles == 0 {
+		return r
+	}
+	first, last := e.mids[0], e.mids[2*r.Cycles]
+	period := (last - first) / float64(r.Cycles)
+	r.Period = toDuration(period)
+	r.Frequency = Hertz(float64(scope.Second) / (period * float64(interval)))
+	var high float64
+	for i := 0; i < 2*r.Cycles; i++ {
+		if e.firstRising == (i%2 == 0) {
+			high += e.mids[i+1] - e.mids[i]
+		}
+	}
+	r.DutyCycle = high / (last - first)
+	r.CycleMean, r.CycleRMS = meanRMS(samples[int(math.Ceil(first)):int(math.Ceil(last))])
+	return r
+}
+
+func meanRMS(samples []scope.Voltage) (mean, rms scope.Voltage) {
+	if len(samples) == 0 {
+		return 0, 0
+	}
+	var sum, sumSq float64
+	for _, v := range samples {
+		sum += float64(v)
+		sumSq += float64(v) * float64(v)
+	}
+	n := float64(len(samples))
+	return scope.Voltage(sum / n), scope.Voltage(math.Sqrt(sumSq / n))
+}
+
+// topBase finds the most common voltage levels in the upper and lower half
+// of the range of samples. If there's no dominant level in either half,
+// e.g. for a triangle wave, the extreme value is used instead.
+func topBase(samples []scope.Voltage, min, max scope.Voltage) (top, base scope.Voltage) {
+	if max == min {
+		return max, min
+	}
+	var count [histBins]int
+	var sum [histBins]float64
+	width := float64(max-min) / histBins
+	for _, v := range samples {
+		b := int(float64(v-min) / width)
+		if b >= histBins {
+			b = histBins - 1
+		}
+		count[b]++
+		sum[b] += float64(v)
+	}
+	mode := func(bins []int, sums []float64, fallback scope.Voltage) scope.Voltage {
+		var total, nonEmpty, best int
+		for i, c := range bins {
+			if c == 0 {
+				continue
+			}
+			total += c
+			nonEmpty++
+			if c > bins[best] {
+				best = i
+			}
+		}
+		if nonEmpty > 1 && bins[best]*nonEmpty < modeFactor*total {
+			return fallback
+		}
+		// average of the samples in the bin is more accurate than the bin center.
+		return scope.Voltage(sums[best] / float64(bins[best]))
+	}
+	return mode(count[histBins/2:], sum[histBins/2:], max), mode(count[:histBins/2], sum[:histBins/2], min)
+}
+
+type edges struct {
+	// mids are the positions of consecutive mid level crossings,
+	// in samples, alternating between rising and falling edges.
+	mids []float64
+	// firstRising is true if mids[0] is a rising edge.
+	firstRising bool
+
+	rising, falling  int
+	riseSum, fallSum float64
+}
+
+// risingMids returns the mid level crossings of the rising edges.
+func (e edges) risingMids() []float64 {
+	var ret []float64
+	for i, m := range e.mids {
+		if e.firstRising == (i%2 == 0) {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// findEdges finds the transitions between the low and high reference
+// levels of the signal, i.e. 10% and 90% of amplitude above base.
+func findEdges(samples []scope.Voltage, base, amplitude scope.Voltage) edges {
+	low := base + lowRef*amplitude
+	mid := base + midRef*amplitude
+	high := base + highRef*amplitude
+	// crossing returns the position at which the signal crosses
+	// level ref between samples i-1 and i, interpolated linearly.
+	crossing := func(i int, ref scope.Voltage) (float64, bool) {
+		prev, v := samples[i-1], samples[i]
+		if !(prev < ref && v >= ref) && !(prev > ref && v <= ref) {
+			return 0, false
+		}
+		return float64(i-1) + float64(ref-prev)/float64(v-prev), true
+	}
+	var e edges
+	// state is -1 below the low level, 1 above the high level,
+	// and 0 until the signal reaches either of them.
+	var state int
+	// positions of the last crossing of each level, in any direction.
+	var lastLow, lastMid, lastHigh float64
+	for i, v := range samples {
+		if i > 0 {
+			if p, ok := crossing(i, low); ok {
+				lastLow = p
+			}
+			if p, ok := crossing(i, mid); ok {
+				lastMid = p
+			}
+			if p, ok := crossing(i, high); ok {
+				lastHigh = p
+			}
+		}
+		switch {
+		case v >= high && state != 1:
+			if state == -1 {
+				if len(e.mids) == 0 {
+					e.firstRising = true
+				}
+				e.mids = append(e.mids, lastMid)
+				e.rising++
+				e.riseSum += lastHigh - lastLow
+			}
+			state = 1
+		case v <= low && state != -1:
+			if state == 1 {
+				e.mids = append(e.mids, lastMid)
+				e.falling++
+				e.fallSum += lastLow - lastHigh
+			}
+			state = -1
+		}
+	}
+	return e
+}
@@ -0,0 +1,290 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package measurements computes the automatic waveform measurements
+// commonly found in oscilloscopes, e.g. peak-to-peak voltage, frequency
+// or rise time, from a sweep of samples of a single channel.
+package measurements
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Measurement identifies a single measurement.
+type Measurement int
+
+// Measurements available in Result.
+const (
+	// PeakToPeak is the difference between the maximum and minimum voltage.
+	PeakToPeak Measurement = iota
+	// Max is the highest sampled voltage.
+	Max
+	// Min is the lowest sampled voltage.
+	Min
+	// Mean is the average voltage over the whole sweep.
+	Mean
+	// RMS is the root mean square voltage over the whole sweep.
+	RMS
+	// CycleMean is the average voltage over the complete cycles in the sweep.
+	CycleMean
+	// CycleRMS is the root mean square voltage over the complete cycles in the sweep.
+	CycleRMS
+	// Amplitude is the difference between Top and Base.
+	Amplitude
+	// Top is the most common voltage of the high level of the signal.
+	Top
+	// Base is the most common voltage of the low level of the signal.
+	Base
+	// Frequency is the number of signal cycles per second.
+	Frequency
+	// Period is the duration of a single signal cycle.
+	Period
+	// DutyCycle is the fraction of the period the signal spends above the mid level.
+	DutyCycle
+	// RiseTime is the average time of the transition from 10% to 90% of Amplitude.
+	RiseTime
+	// FallTime is the average time of the transition from 90% to 10% of Amplitude.
+	FallTime
+	// Overshoot is the distance between Max and Top, relative to Amplitude.
+	Overshoot
+	// Preshoot is the distance between Base and Min, relative to Amplitude.
+	Preshoot
+
+	// Phase is the phase shift of the second channel relative to the first,
+	// in degrees. Positive values mean the second channel lags behind.
+	Phase
+	// Delay is the average time from a rising edge of the first channel
+	// to the next rising edge of the second channel.
+	Delay
+	// XCorrDelay is the delay of the second channel relative to the first,
+	// estimated from the peak of their cross-correlation. Negative values
+	// mean the second channel leads.
+	XCorrDelay
+	// Ratio is the ratio of the AC RMS voltages of the second
+	// and the first channel.
+	Ratio
+	// Gain is Ratio expressed in decibels.
+	Gain
+)
+
+// All lists all available single channel measurements.
+var All = []Measurement{
+	PeakToPeak, Max, Min, Mean, RMS, CycleMean, CycleRMS, Amplitude, Top, Base,
+	Frequency, Period, DutyCycle, RiseTime, FallTime, Overshoot, Preshoot,
+}
+
+// AllDual lists all available measurements comparing two channels.
+var AllDual = []Measurement{Phase, Delay, XCorrDelay, Ratio, Gain}
+
+type unit int
+
+const (
+	unitVoltage unit = iota
+	unitDuration
+	unitFrequency
+	unitRatio
+	unitFactor
+	unitDegrees
+	unitDecibels
+)
+
+var measurementInfo = map[Measurement]struct {
+	name string
+	unit unit
+}{
+	PeakToPeak: {"Vpp", unitVoltage},
+	Max:        {"Vmax", unitVoltage},
+	Min:        {"Vmin", unitVoltage},
+	Mean:       {"Vavg", unitVoltage},
+	RMS:        {"Vrms", unitVoltage},
+	CycleMean:  {"Vavg(cycle)", unitVoltage},
+	CycleRMS:   {"Vrms(cycle)", unitVoltage},
+	Amplitude:  {"Vamp", unitVoltage},
+	Top:        {"Vtop", unitVoltage},
+	Base:       {"Vbase", unitVoltage},
+	Frequency:  {"Freq", unitFrequency},
+	Period:     {"Period", unitDuration},
+	DutyCycle:  {"Duty", unitRatio},
+	RiseTime:   {"Rise", unitDuration},
+	FallTime:   {"Fall", unitDuration},
+	Overshoot:  {"Overshoot", unitRatio},
+	Preshoot:   {"Preshoot", unitRatio},
+	Phase:      {"Phase", unitDegrees},
+	Delay:      {"Delay", unitDuration},
+	XCorrDelay: {"Delay(xcorr)", unitDuration},
+	Ratio:      {"Ratio", unitFactor},
+	Gain:       {"Gain", unitDecibels},
+}
+
+// String returns the short name of the measurement.
+func (m Measurement) String() string {
+	if i, ok := measurementInfo[m]; ok {
+		return i.name
+	}
+	return fmt.Sprintf("Measurement(%d)", int(m))
+}
+
+// Format returns a string representation of v, a value of measurement m
+// as returned by Result.Value, including the unit.
+func (m Measurement) Format(v float64) string {
+	switch measurementInfo[m].unit {
+	case unitDuration:
+		if v < 0 {
+			return "-" + m.Format(-v)
+		}
+		return scope.Duration(math.Round(v * float64(scope.Second))).String()
+	case unitFrequency:
+		return Hertz(v).String()
+	case unitRatio:
+		return fmt.Sprintf("%.2f%%", v*100)
+	case unitFactor:
+		return fmt.Sprintf("%.4fx", v)
+	case unitDegrees:
+		return fmt.Sprintf("%.2f°", v)
+	case unitDecibels:
+		return fmt.Sprintf("%.2fdB", v)
+	}
+	return fmt.Sprintf("%sV", scope.Voltage(v))
+}
+
+// Hertz is a frequency, in cycles per second.
+type Hertz float64
+
+// String returns a string representation of the frequency with a unit prefix.
+func (f Hertz) String() string {
+	v, sfx := float64(f), "Hz"
+	for _, p := range []struct {
+		mul float64
+		sfx string
+	}{{1e9, "GHz"}, {1e6, "MHz"}, {1e3, "kHz"}} {
+		if math.Abs(v) >= p.mul {
+			v, sfx = v/p.mul, p.sfx
+			break
+		}
+	}
+	return fmt.Sprintf("%s%s", trimZeros(fmt.Sprintf("%.3f", v)), sfx)
+}
+
+func trimZeros(s string) string {
+	for s[len(s)-1] == '0' {
+		s = s[:len(s)-1]
+	}
+	if s[len(s)-1] == '.' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// Result holds the measurements of a single sweep.
+type Result struct {
+	Max, Min, PeakToPeak scope.Voltage
+	Mean, RMS            scope.Voltage
+	Top, Base, Amplitude scope.Voltage
+	// Overshoot and Preshoot are fractions of Amplitude.
+	Overshoot, Preshoot float64
+
+	// Cycles is the number of complete signal cycles found in the sweep.
+	// Period, Frequency, DutyCycle, CycleMean and CycleRMS are only valid
+	// if Cycles is positive.
+	Cycles              int
+	Period              scope.Duration
+	Frequency           Hertz
+	DutyCycle           float64
+	CycleMean, CycleRMS scope.Voltage
+	RisingEdges         int
+	RiseTime            scope.Duration
+	FallingEdges        int
+	FallTime            scope.Duration
+
+	numSamples int
+}
+
+// Valid returns true if measurement m could be computed for the sweep.
+func (r Result) Valid(m Measurement) bool {
+	switch m {
+	case Frequency, Period, DutyCycle, CycleMean, CycleRMS:
+		return r.Cycles > 0
+	case RiseTime:
+		return r.RisingEdges > 0
+	case FallTime:
+		return r.FallingEdges > 0
+	case Overshoot, Preshoot:
+		return r.Amplitude > 0
+	case Phase, Delay, XCorrDelay, Ratio, Gain:
+		return false
+	}
+	return r.numSamples > 0
+}
+
+// Value returns the value of measurement m in base units, i.e. volts,
+// seconds, hertz or a fraction for relative measurements.
+// ok is false if the measurement could not be computed for the sweep.
+func (r Result) Value(m Measurement) (v float64, ok bool) {
+	if !r.Valid(m) {
+		return 0, false
+	}
+	sec := func(d scope.Duration) float64 { return float64(d) / float64(scope.Second) }
+	switch m {
+	case PeakToPeak:
+		v = float64(r.PeakToPeak)
+	case Max:
+		v = float64(r.Max)
+	case Min:
+		v = float64(r.Min)
+	case Mean:
+		v = float64(r.Mean)
+	case RMS:
+		v = float64(r.RMS)
+	case CycleMean:
+		v = float64(r.CycleMean)
+	case CycleRMS:
+		v = float64(r.CycleRMS)
+	case Amplitude:
+		v = float64(r.Amplitude)
+	case Top:
+		v = float64(r.Top)
+	case Base:
+		v = float64(r.Base)
+	case Frequency:
+		v = float64(r.Frequency)
+	case Period:
+		v = sec(r.Period)
+	case DutyCycle:
+		v = r.DutyCycle
+	case RiseTime:
+		v = sec(r.RiseTime)
+	case FallTime:
+		v = sec(r.FallTime)
+	case Overshoot:
+		v = r.Overshoot
+	case Preshoot:
+		v = r.Preshoot
+	default:
+		return 0, false
+	}
+	return v, true
+}
+
+// Format returns the value of measurement m with its unit,
+// or "n/a" if the measurement could not be computed.
+func (r Result) Format(m Measurement) string {
+	v, ok := r.Value(m)
+	if !ok {
+		return "n/a"
+	}
+	return m.Format(v)
+}
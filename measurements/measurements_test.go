@@ -0,0 +1,224 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package measurements
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zagrodzki/goscope/compat"
+	"github.com/zagrodzki/goscope/dummy"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// dummySweep returns a sweep of at least n samples of each dummy channel.
+func dummySweep(t *testing.T, n int) (map[scope.ChanID][]scope.Voltage, scope.Duration) {
+	dev, err := dummy.Open("sin,square,triangle")
+	if err != nil {
+		t.Fatalf("dummy.Open: %v", err)
+	}
+	rec := &compat.Recorder{TB: scope.Duration(n) * scope.Millisecond}
+	dev.Attach(rec)
+	dev.Start()
+	defer dev.Stop()
+	ret := make(map[scope.ChanID][]scope.Voltage)
+	for d := range rec.Data {
+		if d.Error != nil {
+			t.Fatalf("dummy device error: %v", d.Error)
+		}
+		for _, ch := range d.Channels {
+			ret[ch.ID] = append(ret[ch.ID], ch.Samples...)
+		}
+		if len(ret["sin"]) >= n {
+			return ret, d.Interval
+		}
+	}
+	t.Fatalf("dummy device stopped before returning %d samples", n)
+	return nil, 0
+}
+
+func TestDummy(t *testing.T) {
+	data, interval := dummySweep(t, 1000)
+	ms := func(v float64) float64 { return v * 1e-3 }
+	for _, tc := range []struct {
+		ch   scope.ChanID
+		want map[Measurement]float64
+		// tolerance, relative to the expected value or absolute for zero values.
+		tol float64
+	}{
+		{
+			ch: "sin",
+			want: map[Measurement]float64{
+				PeakToPeak: 2,
+				Max:        1,
+				Min:        -1,
+				Mean:       0,
+				RMS:        1 / math.Sqrt2,
+				CycleMean:  0,
+				CycleRMS:   1 / math.Sqrt2,
+				Top:        1,
+				Base:       -1,
+				Amplitude:  2,
+				Frequency:  30,
+				Period:     ms(100.0 / 3),
+				DutyCycle:  0.5,
+				// asin(0.8) on both sides of the zero crossing.
+				RiseTime:  2 * math.Asin(0.8) / (2 * math.Pi) * ms(100.0/3),
+				FallTime:  2 * math.Asin(0.8) / (2 * math.Pi) * ms(100.0/3),
+				Overshoot: 0,
+				Preshoot:  0,
+			},
+			tol: 0.02,
+		},
+		{
+			ch: "square",
+			want: map[Measurement]float64{
+				PeakToPeak: 2,
+				Max:        1,
+				Min:        -1,
+				RMS:        1,
+				CycleMean:  0,
+				CycleRMS:   1,
+				Top:        1,
+				Base:       -1,
+				Amplitude:  2,
+				Frequency:  25,
+				Period:     ms(40),
+				DutyCycle:  0.5,
+				// linear interpolation between two samples.
+				RiseTime:  ms(0.8),
+				FallTime:  ms(0.8),
+				Overshoot: 0,
+				Preshoot:  0,
+			},
+			tol: 0.001,
+		},
+		{
+			ch: "triangle",
+			want: map[Measurement]float64{
+				PeakToPeak: 2,
+				Max:        1,
+				Min:        -1,
+				CycleMean:  0,
+				CycleRMS:   math.Sqrt(0.335),
+				// no dominant levels, extremes are used.
+				Top:       1,
+				Base:      -1,
+				Amplitude: 2,
+				Frequency: 25,
+				Period:    ms(40),
+				DutyCycle: 0.5,
+				RiseTime:  ms(16),
+				FallTime:  ms(16),
+				Overshoot: 0,
+				Preshoot:  0,
+			},
+			tol: 0.001,
+		},
+	} {
+		r := Measure(data[tc.ch], interval)
+		for _, m := range All {
+			want, ok := tc.want[m]
+			if !ok {
+				continue
+			}
+			got, ok := r.Value(m)
+			if !ok {
+				t.Errorf("%s: %s not available", tc.ch, m)
+				continue
+			}
+			tol := tc.tol
+			if want != 0 {
+				tol *= math.Abs(want)
+			}
+			if math.Abs(got-want) > tol {
+				t.Errorf("%s: %s: got %s, want %s", tc.ch, m, m.Format(got), m.Format(want))
+			}
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	// a pulse with a ringing overshoot on the rising edge and a preshoot
+	// before the falling edge.
+	var samples []scope.Voltage
+	add := func(v scope.Voltage, n int) {
+		for i := 0; i < n; i++ {
+			samples = append(samples, v)
+		}
+	}
+	add(0, 100)
+	samples = append(samples, 1, 2, 3, 3.5, 3.2, 2.9)
+	add(3, 100)
+	samples = append(samples, 2.5, 0, -0.5)
+	add(0, 100)
+	r := Measure(samples, scope.Microsecond)
+	for _, tc := range []struct {
+		m    Measurement
+		want string
+	}{
+		{PeakToPeak, "4.0000V"},
+		{Top, "3.0000V"},
+		{Base, "0.0000V"},
+		{Amplitude, "3.0000V"},
+		{Overshoot, "16.67%"},
+		{Preshoot, "16.67%"},
+		{RiseTime, "2.4µs"},
+		{FallTime, "1.28µs"},
+		// only one edge of each kind, no full cycle.
+		{Frequency, "n/a"},
+		{DutyCycle, "n/a"},
+	} {
+		if got := r.Format(tc.m); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.m, got, tc.want)
+		}
+	}
+}
+
+func TestFlat(t *testing.T) {
+	r := Measure([]scope.Voltage{0.5, 0.5, 0.5}, scope.Millisecond)
+	for m, want := range map[Measurement]string{
+		Mean:      "0.5000V",
+		RMS:       "0.5000V",
+		Top:       "0.5000V",
+		Amplitude: "0.0000V",
+		Overshoot: "n/a",
+		Period:    "n/a",
+		RiseTime:  "n/a",
+	} {
+		if got := r.Format(m); got != want {
+			t.Errorf("%s: got %s, want %s", m, got, want)
+		}
+	}
+	if r := Measure(nil, scope.Millisecond); r.Valid(Max) {
+		t.Errorf("Measure(nil): Max is valid, want n/a")
+	}
+}
+
+func TestHertz(t *testing.T) {
+	for _, tc := range []struct {
+		f    Hertz
+		want string
+	}{
+		{0, "0Hz"},
+		{30, "30Hz"},
+		{1234.5, "1.234kHz"},
+		{2.5e6, "2.5MHz"},
+	} {
+		if got := tc.f.String(); got != tc.want {
+			t.Errorf("Hertz(%v).String(): got %s, want %s", float64(tc.f), got, tc.want)
+		}
+	}
+}
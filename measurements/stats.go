@@ -0,0 +1,223 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package measurements
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Statistic holds the running statistics of a single measurement.
+type Statistic struct {
+	// Current is the value measured in the most recent sweep for which
+	// the measurement was available.
+	Current float64
+	// Mean, Min, Max and StdDev summarize the values measured in Count sweeps.
+	Mean, Min, Max, StdDev float64
+	Count                  int
+
+	// m2 is the sum of squared differences from the mean.
+	m2 float64
+}
+
+func (s *Statistic) add(v float64) {
+	s.Count++
+	s.Current = v
+	if s.Count == 1 || v < s.Min {
+		s.Min = v
+	}
+	if s.Count == 1 || v > s.Max {
+		s.Max = v
+	}
+	// Welford's online algorithm.
+	d := v - s.Mean
+	s.Mean += d / float64(s.Count)
+	s.m2 += d * (v - s.Mean)
+	s.StdDev = math.Sqrt(s.m2 / float64(s.Count))
+}
+
+// Row is a single row of the statistics table.
+type Row struct {
+	Measurement Measurement
+	Statistic
+}
+
+// Stats measures every sweep passing from the device to the recorder and
+// accumulates the statistics of the measurements. The data is passed
+// to the recorder unchanged.
+// Stats implements both scope.Device interface (used by UI)
+// and scope.DataRecorder interface (used by underlying device).
+//
+// A sweep is the number of samples covering the timebase of the recorder,
+// or every chunk of data received if the timebase is 0.
+type Stats struct {
+	scope.Device
+	ch, ch2 scope.ChanID
+	rec     scope.DataRecorder
+
+	mu     sync.Mutex
+	sweeps int
+	stats  map[Measurement]*Statistic
+}
+
+// NewStats returns Stats of the measurements of channel ch of dev.
+func NewStats(dev scope.Device, ch scope.ChanID) *Stats {
+	s := &Stats{
+		Device: dev,
+		ch:     ch,
+	}
+	s.Clear()
+	return s
+}
+
+// NewDualStats returns Stats of the measurements of channel a of dev,
+// and the measurements comparing channels a and b.
+func NewDualStats(dev scope.Device, a, b scope.ChanID) *Stats {
+	s := NewStats(dev, a)
+	s.ch2 = b
+	return s
+}
+
+// Attach configures the statistics to pass the data to rec.
+func (s *Stats) Attach(rec scope.DataRecorder) {
+	s.rec = rec
+	s.Device.Attach(s)
+}
+
+// TimeBase returns the timebase of the underlying recorder.
+func (s *Stats) TimeBase() scope.Duration {
+	return s.rec.TimeBase()
+}
+
+// Reset initializes the recording. The statistics collected so far
+// are kept, use Clear to discard them.
+func (s *Stats) Reset(i scope.Duration, ch <-chan []scope.ChannelData) {
+	out := make(chan []scope.ChannelData, 2)
+	tbCount := int(s.rec.TimeBase() / i)
+	s.rec.Reset(i, out)
+	go s.run(i, tbCount, ch, out)
+}
+
+// Error passes the error down to the underlying recorder.
+func (s *Stats) Error(err error) {
+	s.rec.Error(err)
+}
+
+// Clear discards the statistics collected so far.
+func (s *Stats) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweeps = 0
+	s.stats = make(map[Measurement]*Statistic)
+}
+
+// Sweeps returns the number of sweeps measured since the last Clear.
+func (s *Stats) Sweeps() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sweeps
+}
+
+func (s *Stats) measurements() []Measurement {
+	if s.ch2 == "" {
+		return All
+	}
+	return append(append([]Measurement(nil), All...), AllDual...)
+}
+
+// Rows returns the statistics of all measurements.
+func (s *Stats) Rows() []Row {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ret []Row
+	for _, m := range s.measurements() {
+		r := Row{Measurement: m}
+		if st, ok := s.stats[m]; ok {
+			r.Statistic = *st
+		}
+		ret = append(ret, r)
+	}
+	return ret
+}
+
+// Write writes the statistics table to w.
+func (s *Stats) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "%d sweeps\tcurrent\tmean\tmin\tmax\tstd dev\tcount\n", s.Sweeps())
+	for _, r := range s.Rows() {
+		if r.Count == 0 {
+			fmt.Fprintf(tw, "%s\tn/a\t\t\t\t\t0\n", r.Measurement)
+			continue
+		}
+		m := r.Measurement
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%d\n", m, m.Format(r.Current), m.Format(r.Mean), m.Format(r.Min), m.Format(r.Max), m.Format(r.StdDev), r.Count)
+	}
+	return tw.Flush()
+}
+
+func (s *Stats) add(a, b []scope.Voltage, interval scope.Duration) {
+	var value func(Measurement) (float64, bool)
+	if s.ch2 == "" {
+		value = Measure(a, interval).Value
+	} else {
+		r := MeasureDual(a, b, interval)
+		value = func(m Measurement) (float64, bool) {
+			if v, ok := r.A.Value(m); ok {
+				return v, true
+			}
+			return r.Value(m)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweeps++
+	for _, m := range s.measurements() {
+		v, ok := value(m)
+		if !ok {
+			continue
+		}
+		st, ok := s.stats[m]
+		if !ok {
+			st = &Statistic{}
+			s.stats[m] = st
+		}
+		st.add(v)
+	}
+}
+
+func (s *Stats) run(interval scope.Duration, tbCount int, in <-chan []scope.ChannelData, out chan<- []scope.ChannelData) {
+	var a, b []scope.Voltage
+	for d := range in {
+		for _, c := range d {
+			switch {
+			case c.ID == s.ch:
+				a = append(a, c.Samples...)
+			case s.ch2 != "" && c.ID == s.ch2:
+				b = append(b, c.Samples...)
+			}
+		}
+		if len(a) > 0 && len(a) >= tbCount {
+			s.add(a, b, interval)
+			a, b = nil, nil
+		}
+		out <- d
+	}
+	close(out)
+}
@@ -0,0 +1,134 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package measurements
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/testutil"
+)
+
+type fakeDev struct{}
+
+func (fakeDev) String() string            { return "fake" }
+func (fakeDev) Channels() []scope.ChanID  { return []scope.ChanID{"a", "b"} }
+func (fakeDev) Attach(scope.DataRecorder) {}
+func (fakeDev) Start()                    {}
+func (fakeDev) Stop()                     {}
+
+func TestStatistic(t *testing.T) {
+	var s Statistic
+	for _, v := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		s.add(v)
+	}
+	want := Statistic{Current: 9, Mean: 5, Min: 2, Max: 9, StdDev: 2, Count: 8}
+	s.m2 = 0
+	if s != want {
+		t.Errorf("Statistic: got %+v, want %+v", s, want)
+	}
+}
+
+func TestStats(t *testing.T) {
+	buf := testutil.NewBufferRecorder(200 * scope.Microsecond)
+	st := NewDualStats(fakeDev{}, "a", "b")
+	st.Attach(buf)
+	in := make(chan []scope.ChannelData)
+	st.Reset(scope.Microsecond, in)
+	// 4 sweeps of 200 samples, sent in chunks of 100 samples.
+	// The amplitude of the sine wave on channel a doubles every sweep,
+	// channel b has half of the amplitude of a.
+	var sent []scope.Voltage
+	for sweep := 0; sweep < 4; sweep++ {
+		a := sine(200, 40, float64(int(1)<<uint(sweep)), 0)
+		b := sine(200, 40, float64(int(1)<<uint(sweep))/2, 0)
+		for i := 0; i < 200; i += 100 {
+			in <- []scope.ChannelData{
+				{ID: "a", Samples: a[i : i+100]},
+				{ID: "b", Samples: b[i : i+100]},
+			}
+		}
+		sent = append(sent, a...)
+	}
+	close(in)
+	sweeps, err := buf.Wait()
+	if err != nil {
+		t.Fatalf("BufferRecorder.Wait: %v", err)
+	}
+	var got []scope.Voltage
+	for _, s := range sweeps {
+		got = append(got, s...)
+	}
+	if len(got) != len(sent) {
+		t.Errorf("recorded %d samples, want %d", len(got), len(sent))
+	}
+
+	if got, want := st.Sweeps(), 4; got != want {
+		t.Errorf("Sweeps(): got %d, want %d", got, want)
+	}
+	rows := make(map[Measurement]Row)
+	for _, r := range st.Rows() {
+		rows[r.Measurement] = r
+	}
+	for _, tc := range []struct {
+		m                           Measurement
+		current, mean, min, max, sd float64
+	}{
+		{m: PeakToPeak, current: 16, mean: 7.5, min: 2, max: 16, sd: math.Sqrt(28.75)},
+		{m: Frequency, current: 25e3, mean: 25e3, min: 25e3, max: 25e3},
+		{m: Gain, current: -6.0206, mean: -6.0206, min: -6.0206, max: -6.0206},
+	} {
+		r := rows[tc.m]
+		if r.Count != 4 {
+			t.Errorf("%s: count %d, want 4", tc.m, r.Count)
+		}
+		for _, v := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"current", r.Current, tc.current},
+			{"mean", r.Mean, tc.mean},
+			{"min", r.Min, tc.min},
+			{"max", r.Max, tc.max},
+			{"std dev", r.StdDev, tc.sd},
+		} {
+			if math.Abs(v.got-v.want) > 1e-3*math.Max(1, math.Abs(v.want)) {
+				t.Errorf("%s: %s: got %v, want %v", tc.m, v.name, v.got, v.want)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	if err := st.Write(&out); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got, want := strings.Count(out.String(), "\n"), 1+len(All)+len(AllDual); got != want {
+		t.Errorf("Write: got %d lines, want %d:\n%s", got, want, out.String())
+	}
+	if !strings.Contains(out.String(), "Freq") || !strings.Contains(out.String(), "25kHz") {
+		t.Errorf("Write: output does not contain the frequency:\n%s", out.String())
+	}
+
+	st.Clear()
+	if got := st.Sweeps(); got != 0 {
+		t.Errorf("Sweeps() after Clear(): got %d, want 0", got)
+	}
+	if got := st.Rows()[0].Count; got != 0 {
+		t.Errorf("Count after Clear(): got %d, want 0", got)
+	}
+}
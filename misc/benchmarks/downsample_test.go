@@ -0,0 +1,51 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package benchmark
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/zagrodzki/goscope/gui"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+func BenchmarkDownsample(b *testing.B) {
+	samples := make([]scope.Voltage, 1000000)
+	for i := range samples {
+		samples[i] = scope.Voltage(math.Sin(float64(i) * 2 * math.Pi / 100000))
+	}
+	samples[300000] = 1.5
+	plot := gui.NewPlot(image.Point{800, 600})
+	for _, bc := range []struct {
+		name string
+		down gui.Downsampler
+	}{
+		{"average", gui.DownsampleAverage},
+		{"minmax", gui.DownsampleMinMax},
+		{"lttb", gui.DownsampleLTTB},
+	} {
+		p := plot.WithDownsampler(bc.down)
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p.Fill(gui.ColorWhite)
+				if err := p.DrawSamples(samples, scope.TraceParams{Zero: 0.5, PerDiv: 0.5}, p.Bounds(), gui.ColorBlack); err != nil {
+					b.Fatalf("DrawSamples: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package spectrum
+
+import "math"
+
+// Averager averages the power of consecutive spectra, reducing
+// the variance of the noise floor.
+type Averager struct {
+	// N is the number of spectra averaged. Once N spectra were added,
+	// every new spectrum contributes 1/N to the average.
+	N int
+
+	count int
+	avg   *Spectrum
+}
+
+// Add adds s to the average and returns the averaged spectrum.
+// The average is reset if the parameters of s differ from the spectra
+// added before, e.g. after the timebase changed.
+func (a *Averager) Add(s *Spectrum) *Spectrum {
+	if a.N <= 1 {
+		return s
+	}
+	if a.avg == nil || len(a.avg.Magnitude) != len(s.Magnitude) || a.avg.BinWidth != s.BinWidth || a.avg.Window != s.Window {
+		a.Reset()
+		a.avg = &Spectrum{
+			Magnitude: make([]float64, len(s.Magnitude)),
+			BinWidth:  s.BinWidth,
+			Window:    s.Window,
+			enbw:      s.enbw,
+		}
+	}
+	if a.count < a.N {
+		a.count++
+	}
+	w := 1 / float64(a.count)
+	for i, m := range s.Magnitude {
+		p := a.avg.Magnitude[i] * a.avg.Magnitude[i]
+		p += w * (m*m - p)
+		a.avg.Magnitude[i] = math.Sqrt(p)
+	}
+	ret := *a.avg
+	ret.Magnitude = append([]float64(nil), a.avg.Magnitude...)
+	return &ret
+}
+
+// Reset discards the spectra added so far.
+func (a *Averager) Reset() {
+	a.count = 0
+	a.avg = nil
+}
@@ -0,0 +1,87 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package spectrum
+
+import "math"
+
+// Distortion holds the harmonic measurements of a periodic signal.
+type Distortion struct {
+	// Harmonics holds the frequency and RMS voltage of the fundamental
+	// (index 0) and the following harmonics.
+	Harmonics []Peak
+	// THD is the total harmonic distortion, the ratio of the RMS voltage
+	// of all harmonics to the RMS voltage of the fundamental.
+	THD float64
+	// SNR is the ratio of the power of the fundamental to the power
+	// of the noise, i.e. everything except DC and the harmonics, in dB.
+	SNR float64
+}
+
+// THDDB returns THD in decibels.
+func (d Distortion) THDDB() float64 {
+	return 20 * math.Log10(d.THD)
+}
+
+// Distortion measures the fundamental, i.e. the strongest component of
+// the spectrum, and n harmonics above it, up to the Nyquist frequency.
+// ok is false if the spectrum has no components.
+func (s *Spectrum) Distortion(n int) (d Distortion, ok bool) {
+	peaks := s.Peaks(1)
+	if len(peaks) == 0 {
+		return d, false
+	}
+	f0 := peaks[0].Frequency
+	// bins already accounted for, starting with DC.
+	used := make([]bool, len(s.Magnitude))
+	mark := func(lo, hi int) {
+		for i := lo; i <= hi; i++ {
+			used[i] = true
+		}
+	}
+	mark(s.lobe(0))
+	var harmPower float64
+	for k := 1; k <= n+1; k++ {
+		c := int(math.Floor(float64(k)*f0/s.BinWidth + 0.5))
+		if c >= len(s.Magnitude) {
+			break
+		}
+		// the harmonic might be off by a bin, pick the strongest bin nearby.
+		for _, b := range []int{c - 1, c + 1} {
+			if b > 0 && b < len(s.Magnitude) && s.Magnitude[b] > s.Magnitude[c] {
+				c = b
+			}
+		}
+		p := s.component(c)
+		d.Harmonics = append(d.Harmonics, p)
+		if k > 1 {
+			harmPower += p.Magnitude * p.Magnitude
+		}
+		mark(s.lobe(c))
+	}
+	fund := d.Harmonics[0].Magnitude
+	d.THD = math.Sqrt(harmPower) / fund
+	var noise float64
+	for i, m := range s.Magnitude {
+		if !used[i] {
+			noise += m * m
+		}
+	}
+	noise /= s.enbw
+	d.SNR = math.Inf(1)
+	if noise > 0 {
+		d.SNR = 10 * math.Log10(fund*fund/noise)
+	}
+	return d, true
+}
@@ -0,0 +1,189 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package spectrum computes the magnitude spectra of sampled signals
+// and measurements based on them, e.g. total harmonic distortion.
+package spectrum
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"github.com/mjibson/go-dsp/fft"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Scale selects how the magnitude is presented.
+type Scale int
+
+const (
+	// Linear scale, in volts RMS.
+	Linear Scale = iota
+	// DBV is the magnitude in decibels relative to 1V RMS.
+	DBV
+)
+
+// minMagnitude limits the dBV values of empty bins.
+const minMagnitude = 1e-12
+
+// Spectrum is the single sided magnitude spectrum of a signal.
+type Spectrum struct {
+	// Magnitude holds the RMS voltage of a sine wave at the frequency of
+	// each bin, from DC up to the Nyquist frequency.
+	Magnitude []float64
+	// BinWidth is the frequency distance between the bins, in Hz.
+	BinWidth float64
+	// Window is the window applied to the samples.
+	Window Window
+	// enbw is the equivalent noise bandwidth of the window, in bins.
+	enbw float64
+}
+
+// Compute returns the spectrum of samples taken every interval,
+// with window w applied.
+func Compute(samples []scope.Voltage, interval scope.Duration, w Window) *Spectrum {
+	n := len(samples)
+	coeffs := w.coefficients(n)
+	in := make([]float64, n)
+	var sum, sumSq float64
+	for i, v := range samples {
+		in[i] = float64(v) * coeffs[i]
+		sum += coeffs[i]
+		sumSq += coeffs[i] * coeffs[i]
+	}
+	s := &Spectrum{
+		Window:   w,
+		BinWidth: float64(scope.Second) / (float64(interval) * float64(n)),
+	}
+	if n == 0 {
+		return s
+	}
+	s.enbw = float64(n) * sumSq / (sum * sum)
+	out := fft.FFTReal(in)
+	s.Magnitude = make([]float64, n/2+1)
+	for i := range s.Magnitude {
+		m := cmplx.Abs(out[i]) / sum
+		if i > 0 && 2*i != n {
+			// energy of the negative frequencies, converted from peak to RMS.
+			m *= math.Sqrt2
+		}
+		s.Magnitude[i] = m
+	}
+	return s
+}
+
+// Frequency returns the frequency of bin i, in Hz.
+func (s *Spectrum) Frequency(i int) float64 {
+	return float64(i) * s.BinWidth
+}
+
+// Values returns the magnitudes in scale sc.
+func (s *Spectrum) Values(sc Scale) []float64 {
+	if sc == Linear {
+		return s.Magnitude
+	}
+	ret := make([]float64, len(s.Magnitude))
+	for i, m := range s.Magnitude {
+		ret[i] = ToDBV(m)
+	}
+	return ret
+}
+
+// ToDBV converts the RMS voltage v to dBV.
+func ToDBV(v float64) float64 {
+	return 20 * math.Log10(math.Max(v, minMagnitude))
+}
+
+// Peak is a local maximum of the spectrum.
+type Peak struct {
+	// Frequency is the estimated frequency of the component, in Hz.
+	Frequency float64
+	// Magnitude is the estimated RMS voltage of the component.
+	Magnitude float64
+}
+
+// lobe returns the range of bins of the main lobe centered at bin c.
+func (s *Spectrum) lobe(c int) (int, int) {
+	hw := windowInfo[s.Window].halfWidth
+	lo, hi := c-hw, c+hw
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > len(s.Magnitude)-1 {
+		hi = len(s.Magnitude) - 1
+	}
+	return lo, hi
+}
+
+// power returns the sum of the squared magnitudes of bins lo..hi,
+// corrected for the noise bandwidth of the window.
+func (s *Spectrum) power(lo, hi int) float64 {
+	var p float64
+	for i := lo; i <= hi; i++ {
+		p += s.Magnitude[i] * s.Magnitude[i]
+	}
+	return p / s.enbw
+}
+
+// component returns the estimated frequency and RMS magnitude
+// of the component with the main lobe centered at bin c.
+func (s *Spectrum) component(c int) Peak {
+	f := float64(c)
+	if c > 0 && c < len(s.Magnitude)-1 {
+		// parabolic interpolation of the logarithmic magnitude.
+		a, b, g := ToDBV(s.Magnitude[c-1]), ToDBV(s.Magnitude[c]), ToDBV(s.Magnitude[c+1])
+		if d := a - 2*b + g; d < 0 {
+			f += 0.5 * (a - g) / d
+		}
+	}
+	return Peak{
+		Frequency: f * s.BinWidth,
+		Magnitude: math.Sqrt(s.power(s.lobe(c))),
+	}
+}
+
+// Peaks returns up to n strongest components of the spectrum,
+// excluding DC and its main lobe, sorted by decreasing magnitude.
+func (s *Spectrum) Peaks(n int) []Peak {
+	var bins []int
+	for i := 1; i < len(s.Magnitude); i++ {
+		m := s.Magnitude[i]
+		if m > s.Magnitude[i-1] && (i == len(s.Magnitude)-1 || m >= s.Magnitude[i+1]) {
+			bins = append(bins, i)
+		}
+	}
+	sort.SliceStable(bins, func(i, j int) bool { return s.Magnitude[bins[i]] > s.Magnitude[bins[j]] })
+	var ret []Peak
+	taken := make([]bool, len(s.Magnitude))
+	_, dcEnd := s.lobe(0)
+	for i := 0; i <= dcEnd; i++ {
+		taken[i] = true
+	}
+	for _, b := range bins {
+		if len(ret) == n {
+			break
+		}
+		// skip local maxima within the main lobe of a stronger peak.
+		if taken[b] {
+			continue
+		}
+		lo, hi := s.lobe(b)
+		for i := lo; i <= hi; i++ {
+			taken[i] = true
+		}
+		ret = append(ret, s.component(b))
+	}
+	return ret
+}
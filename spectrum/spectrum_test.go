@@ -0,0 +1,174 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package spectrum
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// tone is a component of a test signal.
+type tone struct {
+	freq, amplitude float64
+}
+
+// signal returns n samples, taken every microsecond, of the sum of tones,
+// dc and gaussian noise with standard deviation noise.
+func signal(n int, dc, noise float64, tones ...tone) []scope.Voltage {
+	r := rand.New(rand.NewSource(1))
+	ret := make([]scope.Voltage, n)
+	for i := range ret {
+		v := dc + noise*r.NormFloat64()
+		for _, t := range tones {
+			v += t.amplitude * math.Sin(2*math.Pi*t.freq*float64(i)*1e-6)
+		}
+		ret[i] = scope.Voltage(v)
+	}
+	return ret
+}
+
+func TestWindowCoherentGain(t *testing.T) {
+	for _, tc := range []struct {
+		w    Window
+		want float64
+	}{
+		{Rectangular, 1},
+		{Hann, 0.5},
+		{Hamming, 0.54},
+		{BlackmanHarris, 0.35875},
+		{FlatTop, 0.21557895},
+	} {
+		var sum float64
+		for _, c := range tc.w.coefficients(1000) {
+			sum += c
+		}
+		if got := sum / 1000; math.Abs(got-tc.want) > 1e-6 {
+			t.Errorf("%s: coherent gain %v, want %v", tc.w, got, tc.want)
+		}
+		if got, err := ParseWindow(tc.w.String()); err != nil || got != tc.w {
+			t.Errorf("ParseWindow(%q): got %v, %v, want %v", tc.w, got, err, tc.w)
+		}
+	}
+	if _, err := ParseWindow("kaiser"); err == nil {
+		t.Errorf("ParseWindow(kaiser): no error")
+	}
+}
+
+func TestPeaks(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		freq float64
+		// maximum error of the peak magnitude, in dB.
+		tolDB map[Window]float64
+	}{
+		{
+			desc: "aligned with a bin",
+			freq: 10e3,
+			tolDB: map[Window]float64{
+				Rectangular: 0.01, Hann: 0.01, Hamming: 0.01, BlackmanHarris: 0.01, FlatTop: 0.01,
+			},
+		},
+		{
+			desc: "between bins",
+			freq: 10.5e3,
+			tolDB: map[Window]float64{
+				// leakage outside of the main lobe.
+				Rectangular: 1, Hann: 0.1, Hamming: 0.1, BlackmanHarris: 0.01, FlatTop: 0.01,
+			},
+		},
+	} {
+		// 1ms of samples, bins are 1kHz apart.
+		samples := signal(1000, 0.5, 0, tone{tc.freq, 1}, tone{200e3, 0.1})
+		for _, w := range Windows {
+			s := Compute(samples, scope.Microsecond, w)
+			if got, want := s.BinWidth, 1e3; got != want {
+				t.Errorf("%s: BinWidth: got %v, want %v", w, got, want)
+			}
+			// leakage of the tone affects DC, unless it's aligned with a bin.
+			if got, want := s.Magnitude[0], 0.5; tc.freq == 10e3 && math.Abs(got-want) > 1e-3 {
+				t.Errorf("%s: DC: got %v, want %v", w, got, want)
+			}
+			peaks := s.Peaks(2)
+			if len(peaks) != 2 {
+				t.Errorf("%s, %s: got %d peaks, want 2", tc.desc, w, len(peaks))
+				continue
+			}
+			for i, want := range []tone{{tc.freq, 1}, {200e3, 0.1}} {
+				p := peaks[i]
+				if math.Abs(p.Frequency-want.freq) > 0.1e3 {
+					t.Errorf("%s, %s: peak %d: frequency %v, want %v", tc.desc, w, i, p.Frequency, want.freq)
+				}
+				wantDBV := ToDBV(want.amplitude / math.Sqrt2)
+				if got := ToDBV(p.Magnitude); math.Abs(got-wantDBV) > tc.tolDB[w] {
+					t.Errorf("%s, %s: peak %d: %.3fdBV, want %.3fdBV", tc.desc, w, i, got, wantDBV)
+				}
+			}
+		}
+	}
+}
+
+func TestDistortion(t *testing.T) {
+	samples := signal(10000, 0, 0.001, tone{1e3, 1}, tone{2e3, 0.1}, tone{3e3, 0.05}, tone{5e3, 0.02})
+	s := Compute(samples, scope.Microsecond, BlackmanHarris)
+	d, ok := s.Distortion(5)
+	if !ok {
+		t.Fatalf("Distortion: no fundamental found")
+	}
+	if got, want := len(d.Harmonics), 6; got != want {
+		t.Fatalf("Distortion: got %d harmonics, want %d", got, want)
+	}
+	if got, want := d.Harmonics[0].Frequency, 1e3; math.Abs(got-want) > 1 {
+		t.Errorf("fundamental: got %vHz, want %vHz", got, want)
+	}
+	wantTHD := math.Sqrt(0.1*0.1+0.05*0.05+0.02*0.02) / 1
+	if got := d.THD; math.Abs(got-wantTHD) > 1e-3 {
+		t.Errorf("THD: got %v, want %v", got, wantTHD)
+	}
+	// fundamental of 1V amplitude, noise of 1mV RMS.
+	wantSNR := 10 * math.Log10(0.5/1e-6)
+	if got := d.SNR; math.Abs(got-wantSNR) > 0.5 {
+		t.Errorf("SNR: got %.2fdB, want %.2fdB", got, wantSNR)
+	}
+
+	if _, ok := Compute(make([]scope.Voltage, 100), scope.Microsecond, Hann).Distortion(3); ok {
+		t.Errorf("Distortion of a flat signal: got ok, want not ok")
+	}
+}
+
+func TestAverager(t *testing.T) {
+	a := Averager{N: 4}
+	var avg *Spectrum
+	for i := 0; i < 20; i++ {
+		samples := signal(1000, 0, 0.1, tone{10e3, 1})
+		// a different noise sequence for every spectrum.
+		r := rand.New(rand.NewSource(int64(i)))
+		for j := range samples {
+			samples[j] += scope.Voltage(0.1 * r.NormFloat64())
+		}
+		avg = a.Add(Compute(samples, scope.Microsecond, Hann))
+	}
+	if got, want := ToDBV(avg.Peaks(1)[0].Magnitude), ToDBV(1/math.Sqrt2); math.Abs(got-want) > 0.1 {
+		t.Errorf("averaged peak: got %.2fdBV, want %.2fdBV", got, want)
+	}
+	// a spectrum with a different bin width resets the average.
+	s := Compute(signal(500, 0, 0, tone{10e3, 2}), scope.Microsecond, Hann)
+	avg = a.Add(s)
+	if got, want := avg.Magnitude, s.Magnitude; len(got) != len(want) || got[10] != want[10] {
+		t.Errorf("Add() after parameters change: average not reset")
+	}
+}
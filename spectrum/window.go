@@ -0,0 +1,89 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package spectrum
+
+import (
+	"fmt"
+	"math"
+)
+
+// Window is a window function applied to the samples before computing
+// the spectrum, reducing the leakage of the signal between frequency bins.
+type Window int
+
+const (
+	// Rectangular window, i.e. no windowing. Best frequency resolution,
+	// but high leakage for frequencies not aligned with the bins.
+	Rectangular Window = iota
+	// Hann window, a good general purpose window.
+	Hann
+	// Hamming window, similar to Hann with a lower first side lobe.
+	Hamming
+	// BlackmanHarris is the 4-term Blackman-Harris window, with very low
+	// side lobes, for measuring low level components next to strong ones.
+	BlackmanHarris
+	// FlatTop window has the lowest amplitude error, for measuring the
+	// amplitude of the components accurately.
+	FlatTop
+)
+
+var windowInfo = []struct {
+	name string
+	// cosine series coefficients.
+	coeffs []float64
+	// halfWidth is the half width of the main lobe, in bins.
+	halfWidth int
+}{
+	Rectangular:    {"rectangular", []float64{1}, 1},
+	Hann:           {"hann", []float64{0.5, 0.5}, 2},
+	Hamming:        {"hamming", []float64{0.54, 0.46}, 2},
+	BlackmanHarris: {"blackman-harris", []float64{0.35875, 0.48829, 0.14128, 0.01168}, 4},
+	FlatTop:        {"flat-top", []float64{0.21557895, 0.41663158, 0.277263158, 0.083578947, 0.006947368}, 5},
+}
+
+// Windows lists all available windows.
+var Windows = []Window{Rectangular, Hann, Hamming, BlackmanHarris, FlatTop}
+
+func (w Window) String() string {
+	if int(w) < len(windowInfo) && w >= 0 {
+		return windowInfo[w].name
+	}
+	return fmt.Sprintf("Window(%d)", int(w))
+}
+
+// ParseWindow returns the window with name s.
+func ParseWindow(s string) (Window, error) {
+	for _, w := range Windows {
+		if w.String() == s {
+			return w, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown window %q, want one of %v", s, Windows)
+}
+
+// coefficients returns the window of size n.
+func (w Window) coefficients(n int) []float64 {
+	ret := make([]float64, n)
+	for i := range ret {
+		var v float64
+		sign := 1.0
+		for k, a := range windowInfo[w].coeffs {
+			v += sign * a * math.Cos(2*math.Pi*float64(k*i)/float64(n))
+			sign = -sign
+		}
+		ret[i] = v
+	}
+	return ret
+}
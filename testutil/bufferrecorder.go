@@ -73,3 +73,8 @@ func (r *BufferRecorder) Wait() ([][]scope.Voltage, error) {
 	<-r.done
 	return r.sweeps, r.err
 }
+
+// Interval returns the sampling interval passed in the last Reset.
+func (r *BufferRecorder) Interval() scope.Duration {
+	return r.i
+}
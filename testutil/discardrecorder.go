@@ -0,0 +1,57 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package testutil
+
+import (
+	"sync"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// DiscardRecorder implements the scope.DataRecorder interface, reading and
+// discarding all the data. It can be Reset any number of times, users
+// should call Wait() to wait until all the data channels are closed.
+type DiscardRecorder struct {
+	tb scope.Duration
+	wg sync.WaitGroup
+}
+
+// NewDiscardRecorder creates a new discarding recorder with timebase equal to tb.
+func NewDiscardRecorder(tb scope.Duration) *DiscardRecorder {
+	return &DiscardRecorder{tb: tb}
+}
+
+// TimeBase returns the configured timebase (sweep length) of the recorder.
+func (r *DiscardRecorder) TimeBase() scope.Duration {
+	return r.tb
+}
+
+// Reset starts discarding the data read from ch.
+func (r *DiscardRecorder) Reset(_ scope.Duration, ch <-chan []scope.ChannelData) {
+	r.wg.Add(1)
+	go func() {
+		for range ch {
+		}
+		r.wg.Done()
+	}()
+}
+
+// Error ignores the error.
+func (r *DiscardRecorder) Error(error) {}
+
+// Wait waits until the data channels of all Resets are closed.
+func (r *DiscardRecorder) Wait() {
+	r.wg.Wait()
+}
@@ -31,13 +31,23 @@ var autoDelay = 500 * scope.Millisecond
 // and scope.DataRecorder interface (used by underlying device).
 type Trigger struct {
 	scope.Device
-	source   *Source
-	slope    *RisingEdge
-	lvl      *Level
-	rec      scope.DataRecorder
+	source *Source
+	slope  *RisingEdge
+	lvl    *Level
+	rec    scope.DataRecorder
+	mode   *Mode
+}
+
+// runConfig holds the settings of a single run of the trigger.
+// They are copied from the Trigger in Reset, so that the params can
+// be changed for the next run while the previous one is finishing.
+type runConfig struct {
 	interval scope.Duration
 	tbCount  int
-	mode     *Mode
+	slope    RisingEdge
+	mode     Mode
+	lvl      scope.Voltage
+	source   scope.ChanID
 }
 
 // New returns an initialized Trigger.
@@ -69,10 +79,16 @@ func (t *Trigger) Reset(i scope.Duration, ch <-chan []scope.ChannelData) {
 		return
 	}
 	out := make(chan []scope.ChannelData, 2)
-	t.interval = i
-	t.tbCount = int(t.rec.TimeBase() / i)
+	cfg := runConfig{
+		interval: i,
+		tbCount:  int(t.rec.TimeBase() / i),
+		slope:    *t.slope,
+		mode:     *t.mode,
+		lvl:      t.lvl.v,
+		source:   t.source.ch,
+	}
 	t.rec.Reset(i, out)
-	go t.run(ch, out)
+	go run(cfg, ch, out)
 }
 
 // Error passes the error down to the underlying recorder.
@@ -115,20 +131,20 @@ type slice struct {
 	end   int
 }
 
-func (t *Trigger) run(in <-chan []scope.ChannelData, out chan<- []scope.ChannelData) {
+func run(cfg runConfig, in <-chan []scope.ChannelData, out chan<- []scope.ChannelData) {
 	var left, source, ignored int
 	var trg, scanned, found bool
 	var newState, prevState thresholdState
 	var lastTrg time.Time
-	maxIgnored := int(autoDelay / t.interval)
-	slope := *t.slope
-	mode := *t.mode
-	lvl := t.lvl.v
+	maxIgnored := int(autoDelay / cfg.interval)
+	slope := cfg.slope
+	mode := cfg.mode
+	lvl := cfg.lvl
 	for d := range in {
 		if !scanned {
 			scanned = true
 			for i := range d {
-				if d[i].ID == t.source.ch {
+				if d[i].ID == cfg.source {
 					source = i
 					found = true
 					break
@@ -169,7 +185,7 @@ func (t *Trigger) run(in <-chan []scope.ChannelData, out chan<- []scope.ChannelD
 				}
 				if trg {
 					lastTrg = time.Now()
-					left = t.tbCount
+					left = cfg.tbCount
 					curSlice.begin = i
 					ignored = 0
 				}
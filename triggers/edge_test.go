@@ -298,3 +298,17 @@ testCases:
 		}
 	}
 }
+
+func TestLevelParam(t *testing.T) {
+	var p scope.RangeParam = newLevelParam()
+	if got, want := p.Inc(), "0.1000"; got != want {
+		t.Errorf("Inc(): got %q, want %q", got, want)
+	}
+	p.Inc()
+	if got, want := p.Dec(), "0.1000"; got != want {
+		t.Errorf("Dec(): got %q, want %q", got, want)
+	}
+	if got, want := p.Value(), "0.1000"; got != want {
+		t.Errorf("Value(): got %q, want %q", got, want)
+	}
+}
@@ -47,15 +47,17 @@ func (l *Level) Set(v string) error {
 	return nil
 }
 
-// Inc increases the trigger level.
+// Inc raises the trigger level by 0.1V and returns the new value.
 // TODO: when Inc is called multiple times in short succession, the change rate should grow.
-func (l *Level) Inc() {
+func (l *Level) Inc() string {
 	l.v += 0.1
+	return l.Value()
 }
 
-// Dec decreases the trigger level.
-func (l *Level) Dec() {
+// Dec lowers the trigger level by 0.1V and returns the new value.
+func (l *Level) Dec() string {
 	l.v -= 0.1
+	return l.Value()
 }
 
 func newLevelParam() *Level {
@@ -0,0 +1,199 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package tui renders the oscilloscope display in a text terminal,
+// drawing the traces with the Unicode braille characters, each of which
+// holds 2x4 dots, and coloring them with the ANSI escape sequences.
+package tui
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/zagrodzki/goscope/gui"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Color is an ANSI foreground color code.
+type Color int
+
+// Colors of the traces and the graticule.
+const (
+	ColorDefault Color = 0
+	ColorRed     Color = 31
+	ColorGreen   Color = 32
+	ColorYellow  Color = 33
+	ColorBlue    Color = 34
+	ColorMagenta Color = 35
+	ColorCyan    Color = 36
+	ColorGrey    Color = 90
+)
+
+// escape returns the escape sequence selecting the color.
+func (c Color) escape() string {
+	return fmt.Sprintf("\x1b[%dm", c)
+}
+
+// brailleBlank is the braille character without any dots.
+const brailleBlank = 0x2800
+
+// brailleDots are the bits of the dots in a braille character,
+// indexed by the row and the column of the dot within the cell.
+var brailleDots = [4][2]uint8{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// Canvas is a plot made of braille characters, cols characters wide and
+// rows characters high. The dots are addressed like the pixels of
+// an image, with (0, 0) in the top left corner. All the dots of
+// a character have the same color, that of the dot drawn last.
+type Canvas struct {
+	cols, rows int
+	cells      []uint8
+	colors     []Color
+}
+
+// NewCanvas returns an empty Canvas of cols x rows characters.
+func NewCanvas(cols, rows int) *Canvas {
+	return &Canvas{
+		cols:   cols,
+		rows:   rows,
+		cells:  make([]uint8, cols*rows),
+		colors: make([]Color, cols*rows),
+	}
+}
+
+// Bounds returns the rectangle of the dots of the canvas.
+func (c *Canvas) Bounds() image.Rectangle {
+	return image.Rect(0, 0, 2*c.cols, 4*c.rows)
+}
+
+// Clear removes all the dots.
+func (c *Canvas) Clear() {
+	for i := range c.cells {
+		c.cells[i] = 0
+		c.colors[i] = ColorDefault
+	}
+}
+
+// Set draws the dot (x, y) in color col. Dots outside
+// of the canvas are ignored.
+func (c *Canvas) Set(x, y int, col Color) {
+	if !image.Pt(x, y).In(c.Bounds()) {
+		return
+	}
+	i := y/4*c.cols + x/2
+	c.cells[i] |= brailleDots[y%4][x%2]
+	c.colors[i] = col
+}
+
+// DrawLine draws a line from p1 to p2, including both ends.
+func (c *Canvas) DrawLine(p1, p2 image.Point, col Color) {
+	dx, dy := p2.X-p1.X, p2.Y-p1.Y
+	sx, sy := 1, 1
+	if dx < 0 {
+		dx, sx = -dx, -1
+	}
+	if dy < 0 {
+		dy, sy = -dy, -1
+	}
+	err := dx - dy
+	for x, y := p1.X, p1.Y; ; {
+		c.Set(x, y, col)
+		if x == p2.X && y == p2.Y {
+			return
+		}
+		if e2 := 2 * err; e2 > -dy {
+			err -= dy
+			x += sx
+		} else {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+// DrawGrid draws the dotted lines between the divisions,
+// divCols divisions across and divRows divisions down.
+func (c *Canvas) DrawGrid(divCols, divRows int, col Color) {
+	b := c.Bounds()
+	for i := 1; i < divCols; i++ {
+		x := i * b.Dx() / divCols
+		for y := 0; y < b.Dy(); y += 2 {
+			c.Set(x, y, col)
+		}
+	}
+	for i := 1; i < divRows; i++ {
+		y := i * b.Dy() / divRows
+		for x := 0; x < b.Dx(); x += 2 {
+			c.Set(x, y, col)
+		}
+	}
+}
+
+// DrawSamples draws the trace of the samples across the whole width
+// of the canvas, mapping the voltages according to traceParams.
+func (c *Canvas) DrawSamples(samples []scope.Voltage, traceParams scope.TraceParams, col Color) {
+	b := c.Bounds()
+	points := gui.SamplesToPoints(samples, traceParams, b)
+	for i := range points {
+		// the parts of the trace above and below the canvas are drawn
+		// just outside of it, so that the lines leading there are clipped.
+		if points[i].Y < b.Min.Y {
+			points[i].Y = b.Min.Y - 1
+		}
+		if points[i].Y >= b.Max.Y {
+			points[i].Y = b.Max.Y
+		}
+	}
+	for i := range points {
+		if i == 0 {
+			c.Set(points[i].X, points[i].Y, col)
+			continue
+		}
+		c.DrawLine(points[i-1], points[i], col)
+	}
+}
+
+// Lines returns the rows of the canvas, as strings of braille characters
+// and the escape sequences changing the color. The empty characters are
+// spaces and every line ends with the default color.
+func (c *Canvas) Lines() []string {
+	lines := make([]string, c.rows)
+	var b strings.Builder
+	for r := 0; r < c.rows; r++ {
+		b.Reset()
+		cur := ColorDefault
+		for i := r * c.cols; i < (r+1)*c.cols; i++ {
+			if c.cells[i] == 0 {
+				b.WriteByte(' ')
+				continue
+			}
+			if c.colors[i] != cur {
+				cur = c.colors[i]
+				b.WriteString(cur.escape())
+			}
+			b.WriteRune(rune(brailleBlank + int(c.cells[i])))
+		}
+		if cur != ColorDefault {
+			b.WriteString(ColorDefault.escape())
+		}
+		lines[r] = b.String()
+	}
+	return lines
+}
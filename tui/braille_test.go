@@ -0,0 +1,74 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package tui
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/zagrodzki/goscope/scope"
+)
+
+func TestCanvasSet(t *testing.T) {
+	c := NewCanvas(2, 1)
+	if got, want := c.Bounds(), image.Rect(0, 0, 4, 4); got != want {
+		t.Errorf("Bounds(): got %v, want %v", got, want)
+	}
+	// the top left and the bottom right dot of the first character,
+	// a dot outside of the canvas is ignored.
+	c.Set(0, 0, ColorRed)
+	c.Set(1, 3, ColorRed)
+	c.Set(4, 0, ColorRed)
+	if got, want := c.Lines(), []string{"\x1b[31m⢁ \x1b[0m"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines(): got %q, want %q", got, want)
+	}
+	c.Clear()
+	if got, want := c.Lines(), []string{"  "}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() after Clear(): got %q, want %q", got, want)
+	}
+}
+
+func TestCanvasDrawLine(t *testing.T) {
+	c := NewCanvas(2, 1)
+	c.DrawLine(image.Point{0, 1}, image.Point{3, 1}, ColorDefault)
+	// the second row of dots in both characters.
+	if got, want := c.Lines(), []string{"⠒⠒"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines(): got %q, want %q", got, want)
+	}
+}
+
+func TestCanvasDrawSamples(t *testing.T) {
+	c := NewCanvas(3, 2)
+	// 0V in the middle, a rising line from -1V at the bottom
+	// to 1V at the top.
+	c.DrawSamples([]scope.Voltage{-1, 0, 1}, scope.TraceParams{Zero: 0.5, PerDiv: 0.25}, ColorBlue)
+	b := c.Bounds()
+	for _, p := range []image.Point{{0, b.Max.Y - 1}, {b.Max.X - 1, 0}} {
+		i := p.Y/4*3 + p.X/2
+		if c.cells[i]&brailleDots[p.Y%4][p.X%2] == 0 {
+			t.Errorf("dot %v: not set, want set", p)
+		}
+		if c.colors[i] != ColorBlue {
+			t.Errorf("color of dot %v: got %d, want %d", p, c.colors[i], ColorBlue)
+		}
+	}
+	// a trace far above the canvas is clipped.
+	c.Clear()
+	c.DrawSamples([]scope.Voltage{100, 100, 100}, scope.TraceParams{Zero: 0.5, PerDiv: 0.25}, ColorBlue)
+	if got, want := c.Lines(), []string{"   ", "   "}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() of a clipped trace: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,276 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package tui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/zagrodzki/goscope/control"
+	"github.com/zagrodzki/goscope/gui"
+	"github.com/zagrodzki/goscope/measurements"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// Key bindings of Display.HandleKey:
+//
+//	Left/Right       time/div smaller/larger
+//	1-9              select the channel changed by the vertical controls
+//	Down/Up          volts/div of the selected channel smaller/larger
+//	PageDown/PageUp  move the selected channel down/up
+//	[ and ]          trigger level down/up
+//	e, m, s          next trigger edge, mode and source; E, M, S the previous one
+//	Tab              select the next device param
+//	- and =          step the selected device param down/up
+//	Space            run/stop
+//	q, Ctrl-C        quit
+
+// channelColors are the colors of the traces, in the order of the channels.
+var channelColors = []Color{ColorRed, ColorGreen, ColorBlue, ColorMagenta, ColorYellow, ColorCyan}
+
+// shownMeasurements are the measurements displayed below the traces.
+var shownMeasurements = []measurements.Measurement{
+	measurements.PeakToPeak,
+	measurements.RMS,
+	measurements.Mean,
+	measurements.Frequency,
+}
+
+// triggerKeys are the keys changing the trigger params, by param name.
+// The upper case keys change the params in the opposite direction.
+var triggerKeys = map[Key]string{'e': "edge", 'm': "mode", 's': "source"}
+
+// help is shown on the bottom line when there is no other message.
+const help = "←→ time/div ↑↓ V/div 1-9 chan [] level e/m/s trig Tab -= param Space run q quit"
+
+// Display shows the sweeps of a device in a terminal and changes the device
+// params in response to the keys.
+type Display struct {
+	dev scope.Device
+	ctl *control.Controller
+
+	// mu guards the status.
+	mu sync.Mutex
+	// status is the result of the last key, shown on the bottom line.
+	status string
+
+	sweepMu sync.Mutex
+	// sweep is the last complete sweep, sampled every interval,
+	// received at sweepTime.
+	sweep     []scope.ChannelData
+	interval  scope.Duration
+	sweepTime time.Time
+	err       error
+}
+
+// NewDisplay returns a Display of the device dev with the initial
+// time/div and volts/div of all channels. trigger are the trigger params,
+// other are any additional device params.
+func NewDisplay(dev scope.Device, timePerDiv scope.Duration, voltsPerDiv float64, trigger, other []scope.Param) *Display {
+	d := &Display{dev: dev}
+	d.ctl = control.New(dev, d, control.Config{
+		TimePerDiv:  timePerDiv,
+		VoltsPerDiv: voltsPerDiv,
+		Trigger:     trigger,
+		Other:       other,
+	})
+	return d
+}
+
+// TimeBase returns the length of the sweeps.
+func (d *Display) TimeBase() scope.Duration {
+	return d.ctl.TimeBase()
+}
+
+// Reset records the sampling interval of the sweeps of the new run.
+func (d *Display) Reset(r control.Run) {
+	d.sweepMu.Lock()
+	defer d.sweepMu.Unlock()
+	d.interval = r.Interval
+}
+
+// Sweep records the sweep data.
+func (d *Display) Sweep(data []scope.ChannelData, full bool) {
+	if !full {
+		return
+	}
+	sweep := make([]scope.ChannelData, len(data))
+	for i, c := range data {
+		sweep[i] = scope.ChannelData{ID: c.ID, Samples: append([]scope.Voltage(nil), c.Samples...)}
+	}
+	d.sweepMu.Lock()
+	defer d.sweepMu.Unlock()
+	d.sweep = sweep
+	d.sweepTime = time.Now()
+}
+
+// Error records the acquisition error, shown on the bottom line.
+func (d *Display) Error(err error) {
+	d.sweepMu.Lock()
+	defer d.sweepMu.Unlock()
+	d.err = err
+}
+
+// Start starts the device.
+func (d *Display) Start() {
+	d.ctl.Start()
+}
+
+// Stop stops the device, if it's running.
+func (d *Display) Stop() {
+	d.ctl.Stop()
+}
+
+// HandleKey applies the key k to the settings, see the key bindings above.
+// It returns false if k quits the program.
+func (d *Display) HandleKey(k Key) bool {
+	switch {
+	case k == 'q' || k == KeyCtrlC:
+		return false
+	case k == KeyLeft || k == KeyRight:
+		d.setStatus("time/div: %s", d.ctl.StepTimeBase(k == KeyRight))
+	case k == KeyDown || k == KeyUp:
+		_, v := d.ctl.StepVoltsPerDiv(k == KeyUp)
+		d.setStatus("volts/div: %s", v)
+	case k == KeyPageDown || k == KeyPageUp:
+		_, v := d.ctl.StepOffset(k == KeyPageUp)
+		d.setStatus("offset: %s", v)
+	case k >= '1' && k <= '9':
+		if d.ctl.SelectChannel(int(k - '1')) {
+			d.setStatus("selected channel %s", d.ctl.SelectedChannel())
+		}
+	case k == '[' || k == ']':
+		d.report(d.ctl.StepTrigger("level", k == ']'))
+	case triggerKeys[Key(unicode.ToLower(rune(k)))] != "":
+		d.report(d.ctl.StepTrigger(triggerKeys[Key(unicode.ToLower(rune(k)))], unicode.IsLower(rune(k))))
+	case k == KeyTab:
+		if p := d.ctl.SelectParam(); p != nil {
+			d.setStatus("selected %s: %s", p.Name(), d.ctl.Value(p.Name()))
+		}
+	case k == '-' || k == '=':
+		d.report(d.ctl.StepParam(k == '='))
+	case k == ' ':
+		d.ctl.RunStop()
+	}
+	return true
+}
+
+// setStatus sets the message shown on the bottom line.
+func (d *Display) setStatus(format string, a ...interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status = fmt.Sprintf(format, a...)
+}
+
+// report shows the new value v of the param p, or the error.
+// A nil p means that the param doesn't exist, the status doesn't change.
+func (d *Display) report(p scope.Param, v string, err error) {
+	switch {
+	case p == nil:
+	case err != nil:
+		d.setStatus("%s: %v", p.Name(), err)
+	default:
+		d.setStatus("%s: %s", p.Name(), v)
+	}
+}
+
+// triggerStatus returns the trigger settings and whether the sweeps
+// are arriving.
+func (d *Display) triggerStatus(last time.Time) string {
+	value := d.ctl.Value
+	mode := value("mode")
+	if mode == "" || mode == "none" {
+		return "Trig: off"
+	}
+	state := "Trig'd"
+	// a sweep should arrive at least every timebase,
+	// allow for some processing delay.
+	timeout := 2*time.Duration(d.ctl.TimeBase()/scope.Nanosecond) + 500*time.Millisecond
+	if d.ctl.Running() && time.Since(last) > timeout {
+		state = "Waiting"
+	}
+	return fmt.Sprintf("Trig: %s %s %sV %s %s", mode, value("edge"), value("level"), value("source"), state)
+}
+
+// truncate cuts s to at most width characters.
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) > width {
+		r = r[:width]
+	}
+	return string(r)
+}
+
+// Render returns the lines of the display for a terminal of width x height
+// characters: the state of the device, the traces, the measurements
+// of every channel and the result of the last key.
+func (d *Display) Render(width, height int) []string {
+	d.sweepMu.Lock()
+	sweep, interval, last, acqErr := d.sweep, d.interval, d.sweepTime, d.err
+	d.sweepMu.Unlock()
+
+	chans := d.ctl.Channels()
+	lines := []string{truncate(fmt.Sprintf("%s  %s  %s/div  %s", d.dev, d.ctl.State(), d.ctl.Value("time/div"), d.triggerStatus(last)), width)}
+
+	rows := height - 2 - len(chans)
+	if rows < 1 {
+		rows = 1
+	}
+	c := NewCanvas(width, rows)
+	c.DrawGrid(gui.DivCols, gui.DivRows, ColorGrey)
+	colors := make(map[scope.ChanID]Color)
+	for i, ch := range chans {
+		colors[ch] = channelColors[i%len(channelColors)]
+	}
+	for _, s := range sweep {
+		if _, ok := colors[s.ID]; !ok {
+			continue
+		}
+		c.DrawSamples(s.Samples, d.ctl.TraceParams(s.ID), colors[s.ID])
+	}
+	lines = append(lines, c.Lines()...)
+
+	selChan := d.ctl.SelectedChannel()
+	for _, ch := range chans {
+		sel := " "
+		if ch == selChan {
+			sel = "*"
+		}
+		text := fmt.Sprintf("%s%s %s/div", sel, ch, d.ctl.VoltsPerDiv(ch))
+		for _, s := range sweep {
+			if s.ID != ch {
+				continue
+			}
+			r := measurements.Measure(s.Samples, interval)
+			for _, m := range shownMeasurements {
+				text += fmt.Sprintf("  %s=%s", m, r.Format(m))
+			}
+		}
+		lines = append(lines, colors[ch].escape()+truncate(text, width)+ColorDefault.escape())
+	}
+
+	d.mu.Lock()
+	status := d.status
+	d.mu.Unlock()
+	switch {
+	case acqErr != nil:
+		status = fmt.Sprintf("error: %v", acqErr)
+	case status == "":
+		status = help
+	}
+	return append(lines, truncate(status, width))
+}
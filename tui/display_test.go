@@ -0,0 +1,119 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zagrodzki/goscope/dummy"
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/triggers"
+)
+
+func newTestDisplay(t *testing.T) *Display {
+	t.Helper()
+	dev, err := dummy.Open("sin,square")
+	if err != nil {
+		t.Fatalf("dummy.Open: %v", err)
+	}
+	return NewDisplay(dev, scope.Millisecond, 1, dev.(*triggers.Trigger).TriggerParams(), nil)
+}
+
+// waitForSweep waits until the display received a sweep.
+func waitForSweep(t *testing.T, d *Display) {
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(time.Millisecond) {
+		d.sweepMu.Lock()
+		n := len(d.sweep)
+		d.sweepMu.Unlock()
+		if n > 0 {
+			return
+		}
+	}
+	t.Fatalf("no sweep received")
+}
+
+func TestDisplayRender(t *testing.T) {
+	d := newTestDisplay(t)
+	d.Start()
+	defer d.Stop()
+	waitForSweep(t, d)
+
+	lines := d.Render(80, 24)
+	if len(lines) != 24 {
+		t.Fatalf("Render(80, 24): got %d lines, want 24", len(lines))
+	}
+	for _, want := range []string{"dummy device", "Run", "1ms/div", "Trig: off"} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("header %q: want %q", lines[0], want)
+		}
+	}
+	traces := strings.Join(lines[1:21], "")
+	for _, col := range []Color{ColorRed, ColorGreen} {
+		if !strings.Contains(traces, col.escape()) {
+			t.Errorf("traces: want color %d", col)
+		}
+	}
+	for i, want := range []string{"*sin 1V/div  Vpp=", " square 1V/div  Vpp="} {
+		if !strings.Contains(lines[21+i], want) {
+			t.Errorf("measurements %q: want %q", lines[21+i], want)
+		}
+	}
+	if lines[23] != help {
+		t.Errorf("status: got %q, want the help", lines[23])
+	}
+}
+
+func TestDisplayHandleKey(t *testing.T) {
+	d := newTestDisplay(t)
+	d.Start()
+	defer d.Stop()
+	for _, tc := range []struct {
+		keys   []Key
+		status string
+	}{
+		{[]Key{KeyRight}, "time/div: 2ms"},
+		{[]Key{'2', KeyUp}, "volts/div: 2V"},
+		{[]Key{KeyPageUp}, "offset: 0.2div"},
+		{[]Key{'m', 'm'}, "mode: normal"},
+		{[]Key{'M'}, "mode: single"},
+		{[]Key{']'}, "level: 0.1000"},
+		// there's no channel 9, the status doesn't change.
+		{[]Key{'9'}, "level: 0.1000"},
+	} {
+		for _, k := range tc.keys {
+			if !d.HandleKey(k) {
+				t.Fatalf("HandleKey(%q): got false, want true", k)
+			}
+		}
+		if d.status != tc.status {
+			t.Errorf("status after %q: got %q, want %q", tc.keys, d.status, tc.status)
+		}
+	}
+	if got, want := d.TimeBase(), 20*scope.Millisecond; got != want {
+		t.Errorf("TimeBase(): got %v, want %v", got, want)
+	}
+	if got, want := d.ctl.VoltsPerDiv("square"), "2V"; got != want {
+		t.Errorf("square volts/div: got %v, want %v", got, want)
+	}
+	if !d.HandleKey(' ') || d.ctl.Running() {
+		t.Errorf("HandleKey(' '): want the device stopped")
+	}
+	if d.HandleKey('q') {
+		t.Errorf("HandleKey('q'): got true, want false")
+	}
+}
@@ -0,0 +1,87 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package tui
+
+// Key is a key pressed in the terminal, either the character typed
+// or one of the special keys.
+type Key rune
+
+// Special keys, sent by the terminal as escape sequences.
+const (
+	KeyUp Key = -1 - iota
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyPageUp
+	KeyPageDown
+	// KeyUnknown is an escape sequence not recognized by ParseKeys.
+	KeyUnknown
+)
+
+// Control characters.
+const (
+	KeyCtrlC  Key = 0x03
+	KeyTab    Key = '\t'
+	KeyEscape Key = 0x1b
+)
+
+// escapeKeys maps the escape sequences, without the leading ESC,
+// to the special keys. Both the normal and the application cursor
+// mode sequences are recognized.
+var escapeKeys = map[string]Key{
+	"[A":  KeyUp,
+	"[B":  KeyDown,
+	"[C":  KeyRight,
+	"[D":  KeyLeft,
+	"OA":  KeyUp,
+	"OB":  KeyDown,
+	"OC":  KeyRight,
+	"OD":  KeyLeft,
+	"[5~": KeyPageUp,
+	"[6~": KeyPageDown,
+}
+
+// ParseKeys returns the keys of the input read from a terminal
+// in raw mode. The escape sequences are expected to be read whole,
+// an ESC not followed by '[' or 'O' is the Escape key.
+func ParseKeys(b []byte) []Key {
+	var keys []Key
+	s := []rune(string(b))
+	for i := 0; i < len(s); i++ {
+		if s[i] != rune(KeyEscape) || i+1 >= len(s) || (s[i+1] != '[' && s[i+1] != 'O') {
+			keys = append(keys, Key(s[i]))
+			continue
+		}
+		// the sequence ends with a letter or '~'.
+		j := i + 2
+		for j < len(s) && !isFinal(s[j]) {
+			j++
+		}
+		if j == len(s) {
+			j--
+		}
+		k, ok := escapeKeys[string(s[i+1:j+1])]
+		if !ok {
+			k = KeyUnknown
+		}
+		keys = append(keys, k)
+		i = j
+	}
+	return keys
+}
+
+func isFinal(r rune) bool {
+	return r == '~' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z'
+}
@@ -0,0 +1,41 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []Key
+	}{
+		{"q", []Key{'q'}},
+		{"\x1b[A\x1b[B\x1b[C\x1b[D", []Key{KeyUp, KeyDown, KeyRight, KeyLeft}},
+		{"\x1bOA", []Key{KeyUp}},
+		{"\x1b[5~\x1b[6~", []Key{KeyPageUp, KeyPageDown}},
+		{"\x1b[15~x", []Key{KeyUnknown, 'x'}},
+		{"\x1b", []Key{KeyEscape}},
+		{"\x1bq", []Key{KeyEscape, 'q'}},
+		{"\t\x03", []Key{KeyTab, KeyCtrlC}},
+		{"µ", []Key{'µ'}},
+	} {
+		if got := ParseKeys([]byte(tc.in)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseKeys(%q): got %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Command termscope shows the traces of an oscilloscope in a text terminal,
+// e.g. over SSH. See the package tui for the key bindings.
+// The log messages are written to stderr, redirect it to keep
+// the display clean.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/zagrodzki/goscope/acquisition"
+	"github.com/zagrodzki/goscope/devices"
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/triggers"
+	"github.com/zagrodzki/goscope/tui"
+	"golang.org/x/term"
+)
+
+var (
+	device      = flag.String("device", "", "Device to use, autodetect if empty")
+	list        = flag.Bool("list", false, "If set, only list available devices")
+	timePerDiv  = flag.Duration("time_per_div", time.Millisecond, "initial time duration of one div on X axis")
+	voltsPerDiv = flag.Float64("volts_per_div", 2, "initial difference in volts across one div on Y axis")
+	refreshRate = flag.Float64("refresh_rate", 10, "refresh rate of the display, in frames per second")
+)
+
+// Escape sequences controlling the terminal.
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l"
+	exitScreen  = "\x1b[?25h\x1b[?1049l"
+	home        = "\x1b[H"
+	clearLine   = "\x1b[K"
+	clearBelow  = "\x1b[J"
+)
+
+// readKeys sends the keys read from the terminal to keys.
+func readKeys(keys chan<- tui.Key) {
+	buf := make([]byte, 64)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		for _, k := range tui.ParseKeys(buf[:n]) {
+			keys <- k
+		}
+	}
+}
+
+func main() {
+	flag.Parse()
+	if *list {
+		fmt.Println("Devices found:")
+		for _, d := range devices.List() {
+			fmt.Println(d)
+		}
+		return
+	}
+	osc, err := devices.Open(*device)
+	if err != nil {
+		log.Fatalf("Open: %+v", err)
+	}
+	if *refreshRate <= 0 {
+		log.Fatalf("Invalid value of flag refresh_rate: %v, must be positive", *refreshRate)
+	}
+
+	var trigger []scope.Param
+	if tr, ok := osc.(*triggers.Trigger); ok {
+		trigger = tr.TriggerParams()
+	}
+	ad := acquisition.New(osc)
+	d := tui.NewDisplay(ad, scope.DurationFromNano(*timePerDiv), *voltsPerDiv, trigger, ad.AcquisitionParams())
+
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		log.Fatalf("termscope must be run in a terminal")
+	}
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		log.Fatalf("MakeRaw: %v", err)
+	}
+	defer term.Restore(in, state)
+	fmt.Print(enterScreen)
+	defer fmt.Print(exitScreen)
+
+	d.Start()
+	defer d.Stop()
+	keys := make(chan tui.Key)
+	go readKeys(keys)
+	tick := time.NewTicker(time.Duration(float64(time.Second) / *refreshRate))
+	defer tick.Stop()
+	for {
+		select {
+		case k, ok := <-keys:
+			if !ok || !d.HandleKey(k) {
+				return
+			}
+		case <-tick.C:
+		}
+		w, h, err := term.GetSize(out)
+		if err != nil {
+			w, h = 80, 24
+		}
+		// in raw mode the lines must end with CR LF.
+		fmt.Print(home + strings.Join(d.Render(w, h), clearLine+"\r\n") + clearLine + clearBelow)
+	}
+}
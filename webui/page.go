@@ -0,0 +1,197 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package webui
+
+// page is the HTML page of the UI. It draws the decimated sweeps
+// received from /ws on a canvas, over a graticule of 10x8 divisions,
+// and shows a control for every param from /params.
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>goscope</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+canvas { border: 1px solid #000; background: #fff; }
+#controls { margin-top: 0.5em; }
+#controls div { display: inline-block; margin: 0.2em 1em 0.2em 0; }
+#status { color: #a00; }
+</style>
+</head>
+<body>
+<canvas id="screen" width="800" height="480"></canvas>
+<div id="controls">
+<div><button id="run">Stop</button> <span id="status"></span></div>
+<div id="channels"></div>
+<div id="params"></div>
+</div>
+<script>
+"use strict";
+const divCols = 10, divRows = 8;
+const colors = ["#f00", "#080", "#00f", "#808", "#cc0"];
+const voltsPerDiv = [0.001, 0.002, 0.005, 0.01, 0.02, 0.05, 0.1, 0.2, 0.5, 1, 2, 5, 10];
+const canvas = document.getElementById("screen");
+const ctx = canvas.getContext("2d");
+let running = true;
+// display settings of the channels, by channel ID.
+const chans = {};
+
+function grid() {
+  ctx.fillStyle = "#fff";
+  ctx.fillRect(0, 0, canvas.width, canvas.height);
+  ctx.strokeStyle = "#ccc";
+  ctx.beginPath();
+  for (let i = 1; i < divCols; i++) {
+    const x = Math.round(i * canvas.width / divCols) + 0.5;
+    ctx.moveTo(x, 0);
+    ctx.lineTo(x, canvas.height);
+  }
+  for (let i = 1; i < divRows; i++) {
+    const y = Math.round(i * canvas.height / divRows) + 0.5;
+    ctx.moveTo(0, y);
+    ctx.lineTo(canvas.width, y);
+  }
+  ctx.stroke();
+}
+
+function addChannel(id, i) {
+  const c = {color: colors[i % colors.length], perDiv: 1, zero: 0.5};
+  chans[id] = c;
+  const div = document.createElement("div");
+  div.style.color = c.color;
+  div.append(id + " V/div: ");
+  const sel = document.createElement("select");
+  for (const v of voltsPerDiv) {
+    const o = new Option(v, v, false, v === c.perDiv);
+    sel.add(o);
+  }
+  sel.onchange = () => { c.perDiv = parseFloat(sel.value); };
+  div.append(sel);
+  document.getElementById("channels").append(div);
+}
+
+function draw(f) {
+  grid();
+  f.channels.forEach((ch, i) => {
+    if (!(ch.id in chans)) {
+      addChannel(ch.id, i);
+    }
+    const c = chans[ch.id];
+    const y = v => canvas.height * (1 - c.zero - v / (c.perDiv * divRows));
+    const n = ch.min.length;
+    ctx.strokeStyle = c.color;
+    ctx.beginPath();
+    for (let x = 0; x < n; x++) {
+      // the samples that are not finite are sent as null.
+      if (ch.min[x] === null || ch.max[x] === null) {
+        continue;
+      }
+      const px = n > 1 ? x * (canvas.width - 1) / (n - 1) + 0.5 : 0.5;
+      ctx.moveTo(px, y(ch.max[x]));
+      ctx.lineTo(px, y(ch.min[x]) + 1);
+      if (x + 1 < n && ch.min[x + 1] !== null) {
+        ctx.lineTo(px + (canvas.width - 1) / (n - 1), y(ch.min[x + 1]));
+      }
+    }
+    ctx.stroke();
+  });
+  ctx.fillStyle = "#000";
+  ctx.fillText(formatSI(f.timeBase / divCols, "s") + "/div", 5, canvas.height - 5);
+}
+
+function formatSI(v, unit) {
+  const prefixes = [[1e-9, "n"], [1e-6, "µ"], [1e-3, "m"], [1, ""], [1e3, "k"], [1e6, "M"]];
+  let p = prefixes[0];
+  for (const q of prefixes) {
+    if (Math.abs(v) >= q[0]) {
+      p = q;
+    }
+  }
+  return parseFloat((v / p[0]).toPrecision(3)) + p[1] + unit;
+}
+
+function connect() {
+  const ws = new WebSocket((location.protocol === "https:" ? "wss://" : "ws://") + location.host + "/ws");
+  ws.onmessage = e => draw(JSON.parse(e.data));
+  ws.onopen = () => { status(""); };
+  ws.onclose = () => {
+    status("disconnected, reconnecting...");
+    setTimeout(connect, 1000);
+  };
+}
+
+function status(text) {
+  document.getElementById("status").textContent = text;
+}
+
+async function post(path, body) {
+  const resp = await fetch(path, {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify(body),
+  });
+  if (!resp.ok) {
+    status(await resp.text());
+  }
+  loadState();
+}
+
+function showState(st) {
+  running = st.running;
+  document.getElementById("run").textContent = running ? "Stop" : "Run";
+  const div = document.getElementById("params");
+  div.replaceChildren();
+  for (const p of st.params) {
+    const d = document.createElement("div");
+    d.append(p.name + ": ");
+    if (p.values) {
+      const sel = document.createElement("select");
+      for (const v of p.values) {
+        sel.add(new Option(v, v, false, v === p.value));
+      }
+      sel.onchange = () => post("/params", {name: p.name, value: sel.value});
+      d.append(sel);
+    } else {
+      const inp = document.createElement("input");
+      inp.size = 8;
+      inp.value = p.value;
+      inp.onchange = () => post("/params", {name: p.name, value: inp.value});
+      d.append(inp);
+    }
+    if (p.range) {
+      for (const [label, step] of [["-", "dec"], ["+", "inc"]]) {
+        const b = document.createElement("button");
+        b.textContent = label;
+        b.onclick = () => post("/params", {name: p.name, step: step});
+        d.append(b);
+      }
+    }
+    div.append(d);
+  }
+}
+
+async function loadState() {
+  const resp = await fetch("/params");
+  showState(await resp.json());
+}
+
+document.getElementById("run").onclick = () => post("/run", {running: !running});
+grid();
+loadState();
+connect();
+</script>
+</body>
+</html>
+`
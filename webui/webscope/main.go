@@ -0,0 +1,67 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Command webscope serves a browser based UI of an oscilloscope,
+// for machines without a display. Open http://host:port/ in a browser
+// to see the waveforms and change the settings.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/zagrodzki/goscope/acquisition"
+	"github.com/zagrodzki/goscope/devices"
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/triggers"
+	"github.com/zagrodzki/goscope/webui"
+)
+
+var (
+	device     = flag.String("device", "", "Device to use, autodetect if empty")
+	list       = flag.Bool("list", false, "If set, only list available devices")
+	addr       = flag.String("addr", ":8080", "address to listen on, host:port")
+	timePerDiv = flag.Duration("time_per_div", time.Millisecond, "initial time duration of one div on X axis")
+	columns    = flag.Int("columns", webui.DefaultColumns, "number of columns the sweeps are decimated to, at least the width of the plot in pixels")
+)
+
+func main() {
+	flag.Parse()
+	if *list {
+		fmt.Println("Devices found:")
+		for _, d := range devices.List() {
+			fmt.Println(d)
+		}
+		return
+	}
+	osc, err := devices.Open(*device)
+	if err != nil {
+		log.Fatalf("Open: %+v", err)
+	}
+
+	var params []scope.Param
+	if tr, ok := osc.(*triggers.Trigger); ok {
+		params = append(params, tr.TriggerParams()...)
+	}
+	ad := acquisition.New(osc)
+	params = append(params, ad.AcquisitionParams()...)
+
+	srv := webui.New(ad, scope.DurationFromNano(*timePerDiv), *columns, params...)
+	srv.Start()
+	log.Printf("Serving %s on %s", osc, *addr)
+	log.Fatal(http.ListenAndServe(*addr, srv))
+}
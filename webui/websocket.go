@@ -0,0 +1,214 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package webui
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// This file implements the server side of the WebSocket protocol
+// (RFC 6455), as much as needed to stream the sweeps to the browser:
+// the server sends unfragmented messages and reads the messages of the
+// client only to answer pings and the closing handshake.
+
+// wsGUID is appended to the key of the client to compute the accept key.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxWSPayload limits the size of the frames accepted from the client.
+const maxWSPayload = 1 << 16
+
+// WebSocket frame opcodes.
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xa
+)
+
+// wsConn is a server side WebSocket connection.
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	// wmu serializes the writes of the frames.
+	wmu sync.Mutex
+}
+
+// wsAccept returns the value of the Sec-WebSocket-Accept header
+// for the Sec-WebSocket-Key of the client.
+func wsAccept(key string) string {
+	h := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// headerContains returns true if the comma separated list of tokens
+// in header h of r contains token, ignoring the case.
+func headerContains(r *http.Request, h, token string) bool {
+	for _, v := range r.Header.Values(h) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// upgrade performs the opening handshake and takes over the connection
+// of the request. If the request is not a valid WebSocket handshake,
+// upgrade replies with an error and returns a non-nil error.
+func upgrade(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	switch {
+	case r.Method != http.MethodGet:
+		http.Error(w, "WebSocket handshake must use GET", http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("handshake method %s, want GET", r.Method)
+	case !headerContains(r, "Connection", "upgrade") || !headerContains(r, "Upgrade", "websocket"):
+		http.Error(w, "not a WebSocket handshake", http.StatusBadRequest)
+		return nil, errors.New("not a WebSocket handshake")
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("WebSocket version %q, want 13", r.Header.Get("Sec-WebSocket-Version"))
+	case key == "":
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection can't be upgraded", http.StatusInternalServerError)
+		return nil, errors.New("http.ResponseWriter does not implement http.Hijacker")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", wsAccept(key))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, rw: rw}, nil
+}
+
+// writeFrame sends a single, final frame with given opcode and payload.
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	var hdr [10]byte
+	hdr[0] = 0x80 | op
+	n := 2
+	switch l := len(payload); {
+	case l < 126:
+		hdr[1] = byte(l)
+	case l <= 0xffff:
+		hdr[1] = 126
+		binary.BigEndian.PutUint16(hdr[2:], uint16(l))
+		n = 4
+	default:
+		hdr[1] = 127
+		binary.BigEndian.PutUint64(hdr[2:], uint64(l))
+		n = 10
+	}
+	if _, err := c.rw.Write(hdr[:n]); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// readFrame reads a single frame and returns its opcode and the unmasked
+// payload.
+func readFrame(r io.Reader) (op byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	op = hdr[0] & 0x0f
+	masked := hdr[1]&0x80 != 0
+	l := uint64(hdr[1] & 0x7f)
+	switch l {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		l = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		l = binary.BigEndian.Uint64(ext[:])
+	}
+	if l > maxWSPayload {
+		return 0, nil, fmt.Errorf("WebSocket frame of %d bytes, want at most %d", l, maxWSPayload)
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload = make([]byte, l)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return op, payload, nil
+}
+
+// readLoop reads the frames of the client until the connection is closed,
+// answering the pings and the close frame. The data frames are discarded.
+func (c *wsConn) readLoop() error {
+	for {
+		op, payload, err := readFrame(c.rw)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return err
+			}
+		case opClose:
+			// echo the status code, if any, to complete the closing handshake.
+			if len(payload) > 2 {
+				payload = payload[:2]
+			}
+			return c.writeFrame(opClose, payload)
+		}
+	}
+}
+
+// Close closes the underlying connection.
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
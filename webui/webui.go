@@ -0,0 +1,424 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package webui serves a browser based oscilloscope UI. The sweeps of
+// a scope.Device are streamed to the page over a WebSocket, decimated to
+// the minimum and maximum of every pixel column, and the device params
+// can be changed from the page, so that the scope can be used from
+// another machine on the network.
+package webui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/zagrodzki/goscope/control"
+	"github.com/zagrodzki/goscope/scope"
+)
+
+// DefaultColumns is the default number of the pixel columns
+// the sweeps are decimated to.
+const DefaultColumns = 800
+
+// Samples are the voltages sent to the page. JSON can't represent
+// NaN and infinities, they are sent as null.
+type Samples []scope.Voltage
+
+// MarshalJSON encodes the samples as an array of numbers,
+// with null in place of the values that are not finite.
+func (s Samples) MarshalJSON() ([]byte, error) {
+	b := make([]byte, 0, 2+8*len(s))
+	b = append(b, '[')
+	for i, v := range s {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		if f := float64(v); math.IsNaN(f) || math.IsInf(f, 0) {
+			b = append(b, "null"...)
+		} else {
+			b = strconv.AppendFloat(b, f, 'g', -1, 64)
+		}
+	}
+	return append(b, ']'), nil
+}
+
+// ChannelFrame is the decimated sweep of a single channel.
+// Min[i] and Max[i] are the extremes of the samples in column i.
+type ChannelFrame struct {
+	ID  scope.ChanID `json:"id"`
+	Min Samples      `json:"min"`
+	Max Samples      `json:"max"`
+}
+
+// Frame is a single sweep, as sent to the page.
+type Frame struct {
+	// TimeBase is the length of the sweep, in seconds.
+	TimeBase float64 `json:"timeBase"`
+	// Interval is the sampling interval, in seconds.
+	Interval float64        `json:"interval"`
+	Channels []ChannelFrame `json:"channels"`
+}
+
+// Decimate reduces samples to the minimum and the maximum of each of
+// the columns, splitting the samples evenly between the columns.
+// If there are fewer samples than columns, each sample is a column.
+func Decimate(samples []scope.Voltage, columns int) (mins, maxs []scope.Voltage) {
+	n := len(samples)
+	if columns > n || columns <= 0 {
+		columns = n
+	}
+	mins = make([]scope.Voltage, columns)
+	maxs = make([]scope.Voltage, columns)
+	for c := 0; c < columns; c++ {
+		col := samples[c*n/columns : (c+1)*n/columns]
+		mins[c], maxs[c] = col[0], col[0]
+		for _, v := range col[1:] {
+			if v < mins[c] {
+				mins[c] = v
+			}
+			if v > maxs[c] {
+				maxs[c] = v
+			}
+		}
+	}
+	return mins, maxs
+}
+
+// NewFrame returns the frame of the sweep data, sampled every interval,
+// decimated to columns.
+func NewFrame(data []scope.ChannelData, interval, timeBase scope.Duration, columns int) Frame {
+	f := Frame{
+		TimeBase: float64(timeBase) / float64(scope.Second),
+		Interval: float64(interval) / float64(scope.Second),
+	}
+	for _, d := range data {
+		mins, maxs := Decimate(d.Samples, columns)
+		f.Channels = append(f.Channels, ChannelFrame{ID: d.ID, Min: mins, Max: maxs})
+	}
+	return f
+}
+
+// ParamState describes a param for the page.
+type ParamState struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Values are the available values of a scope.SelectParam.
+	Values []string `json:"values,omitempty"`
+	// Range is true for a scope.RangeParam, which can be stepped up and down.
+	Range bool `json:"range,omitempty"`
+}
+
+// State is the state of the server reported to the page.
+type State struct {
+	Running bool         `json:"running"`
+	Params  []ParamState `json:"params"`
+}
+
+// ParamChange is a change of a param requested by the page. Either
+// the Value is set, or the param is stepped up or down if Step is
+// "inc" or "dec".
+type ParamChange struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+	Step  string `json:"step,omitempty"`
+}
+
+// Server serves the UI of a device.
+// Server implements http.Handler.
+type Server struct {
+	ctl     *control.Controller
+	columns int
+	mux     *http.ServeMux
+
+	// mu serializes the param changes with the state sent in the response.
+	mu sync.Mutex
+
+	clientsMu sync.Mutex
+	// clients receive the encoded frames, the channels hold
+	// only the most recent frame not sent yet.
+	clients map[chan []byte]bool
+	// last is the most recent frame, sent to the new clients.
+	last []byte
+	// run are the settings of the current run of the device.
+	run control.Run
+}
+
+// New returns a Server of the device dev, with the sweeps of timePerDiv
+// per division decimated to columns. params are the device params
+// changeable from the page, in addition to the time/div.
+func New(dev scope.Device, timePerDiv scope.Duration, columns int, params ...scope.Param) *Server {
+	s := &Server{
+		columns: columns,
+		mux:     http.NewServeMux(),
+		clients: make(map[chan []byte]bool),
+	}
+	s.ctl = control.New(dev, s, control.Config{TimePerDiv: timePerDiv, VoltsPerDiv: 1, Other: params})
+	s.mux.HandleFunc("/", s.handlePage)
+	s.mux.HandleFunc("/ws", s.handleWebSocket)
+	s.mux.HandleFunc("/params", s.handleParams)
+	s.mux.HandleFunc("/run", s.handleRun)
+	return s
+}
+
+// ServeHTTP serves the page, the WebSocket stream of the sweeps at /ws,
+// the params at /params and the run/stop control at /run.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Start starts the device.
+func (s *Server) Start() {
+	s.ctl.Start()
+}
+
+// Stop stops the device, if it's running.
+func (s *Server) Stop() {
+	s.ctl.Stop()
+}
+
+// TimeBase returns the length of the sweeps.
+func (s *Server) TimeBase() scope.Duration {
+	return s.ctl.TimeBase()
+}
+
+// Reset records the settings of the new run.
+func (s *Server) Reset(r control.Run) {
+	s.run = r
+}
+
+// Sweep sends the decimated sweep data to the clients.
+func (s *Server) Sweep(data []scope.ChannelData, full bool) {
+	if full && s.hasClients() {
+		s.broadcast(NewFrame(data, s.run.Interval, s.run.TimeBase, s.columns))
+	}
+}
+
+// Error reports the acquisition error.
+func (s *Server) Error(err error) {
+	log.Printf("acquisition error: %v", err)
+}
+
+// hasClients returns true if any page is receiving the sweeps.
+func (s *Server) hasClients() bool {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	return len(s.clients) > 0
+}
+
+// broadcast sends the frame to all clients. A client that didn't
+// receive the previous frame yet gets only the new one.
+func (s *Server) broadcast(f Frame) {
+	b, err := json.Marshal(f)
+	if err != nil {
+		log.Printf("json.Marshal(frame): %v", err)
+		return
+	}
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	s.last = b
+	for c := range s.clients {
+		select {
+		case <-c:
+		default:
+		}
+		c <- b
+	}
+}
+
+// subscribe registers a new client, which receives the most recent frame
+// right away, if any frame was sent yet.
+func (s *Server) subscribe() chan []byte {
+	c := make(chan []byte, 1)
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	if s.last != nil {
+		c <- s.last
+	}
+	s.clients[c] = true
+	return c
+}
+
+func (s *Server) unsubscribe(c chan []byte) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	delete(s.clients, c)
+}
+
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, page)
+}
+
+// sameOrigin returns true if the request comes from the page served
+// by the server itself, or not from a browser page at all. Browsers
+// send the Origin header with the cross-origin requests and with
+// all WebSocket handshakes.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// checkChange rejects the state changing requests coming from other
+// sites or without a JSON body, which a page of another site could
+// send as a simple request, and returns true if the request is allowed.
+func checkChange(w http.ResponseWriter, r *http.Request) bool {
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin request", http.StatusForbidden)
+		return false
+	}
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+		http.Error(w, "want Content-Type application/json", http.StatusUnsupportedMediaType)
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin request", http.StatusForbidden)
+		return
+	}
+	conn, err := upgrade(w, r)
+	if err != nil {
+		log.Printf("WebSocket from %s: %v", r.RemoteAddr, err)
+		return
+	}
+	defer conn.Close()
+	frames := s.subscribe()
+	defer s.unsubscribe(frames)
+	closed := make(chan struct{})
+	go func() {
+		conn.readLoop()
+		close(closed)
+	}()
+	for {
+		select {
+		case <-closed:
+			return
+		case b := <-frames:
+			if err := conn.writeFrame(opText, b); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// state returns the state of the device and its params.
+// Must be called with s.mu held.
+func (s *Server) state() State {
+	st := State{Running: s.ctl.Running()}
+	for _, p := range s.ctl.Params() {
+		ps := ParamState{Name: p.Name(), Value: p.Value()}
+		if sp, ok := p.(scope.SelectParam); ok {
+			ps.Values = sp.Values()
+		}
+		_, ps.Range = p.(scope.RangeParam)
+		st.Params = append(st.Params, ps)
+	}
+	return st
+}
+
+// change applies the param change.
+// Must be called with s.mu held.
+func (s *Server) change(c ParamChange) (int, error) {
+	p := s.ctl.Param(c.Name)
+	if p == nil {
+		return http.StatusNotFound, fmt.Errorf("unknown param %q", c.Name)
+	}
+	var err error
+	switch c.Step {
+	case "":
+		err = s.ctl.Set(p, c.Value)
+	case "inc", "dec":
+		_, err = s.ctl.Step(p, c.Step == "inc")
+	default:
+		return http.StatusBadRequest, fmt.Errorf("invalid step %q, want inc or dec", c.Step)
+	}
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	return http.StatusOK, nil
+}
+
+func (s *Server) handleParams(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !checkChange(w, r) {
+			return
+		}
+		var c ParamChange
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+			http.Error(w, fmt.Sprintf("invalid param change: %v", err), http.StatusBadRequest)
+			return
+		}
+		if code, err := s.change(c); err != nil {
+			http.Error(w, err.Error(), code)
+			return
+		}
+	default:
+		http.Error(w, "want GET or POST", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, s.state())
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "want POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if !checkChange(w, r) {
+		return
+	}
+	var req struct {
+		Running bool `json:"running"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid run state: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Running {
+		s.Start()
+	} else {
+		s.Stop()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, s.state())
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("writing the response: %v", err)
+	}
+}
@@ -0,0 +1,350 @@
+//  Copyright 2026 The goscope Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package webui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zagrodzki/goscope/dummy"
+	"github.com/zagrodzki/goscope/scope"
+	"github.com/zagrodzki/goscope/triggers"
+)
+
+func TestDecimate(t *testing.T) {
+	for _, tc := range []struct {
+		samples  []scope.Voltage
+		columns  int
+		min, max []scope.Voltage
+	}{
+		{[]scope.Voltage{1, 3, 2, 0, 5, 4}, 3, []scope.Voltage{1, 0, 4}, []scope.Voltage{3, 2, 5}},
+		{[]scope.Voltage{1, 3, 2, 0, 5}, 2, []scope.Voltage{1, 0}, []scope.Voltage{3, 5}},
+		{[]scope.Voltage{1, 2}, 4, []scope.Voltage{1, 2}, []scope.Voltage{1, 2}},
+		{nil, 4, []scope.Voltage{}, []scope.Voltage{}},
+	} {
+		min, max := Decimate(tc.samples, tc.columns)
+		if !reflect.DeepEqual(min, tc.min) || !reflect.DeepEqual(max, tc.max) {
+			t.Errorf("Decimate(%v, %d): got %v, %v, want %v, %v", tc.samples, tc.columns, min, max, tc.min, tc.max)
+		}
+	}
+}
+
+// dialWebSocket opens a WebSocket connection to the path of the test server.
+func dialWebSocket(t *testing.T, ts *httptest.Server, path string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n\r\n", path, ts.Listener.Addr(), key)
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatalf("reading the handshake response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status: got %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	// the example from RFC 6455.
+	if got, want := resp.Header.Get("Sec-WebSocket-Accept"), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Fatalf("Sec-WebSocket-Accept: got %q, want %q", got, want)
+	}
+	return conn, r
+}
+
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+	dev, err := dummy.Open("sin,square")
+	if err != nil {
+		t.Fatalf("dummy.Open: %v", err)
+	}
+	srv := New(dev, scope.Millisecond, 100, dev.(*triggers.Trigger).TriggerParams()...)
+	return srv, httptest.NewServer(srv)
+}
+
+func TestWebSocket(t *testing.T) {
+	srv, ts := newTestServer(t)
+	defer ts.Close()
+	srv.Start()
+	defer srv.Stop()
+
+	conn, r := dialWebSocket(t, ts, "/ws")
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	op, payload, err := readFrame(r)
+	if err != nil {
+		t.Fatalf("readFrame: %v", err)
+	}
+	if op != opText {
+		t.Fatalf("frame opcode: got %d, want %d", op, opText)
+	}
+	var f Frame
+	if err := json.Unmarshal(payload, &f); err != nil {
+		t.Fatalf("json.Unmarshal(frame): %v", err)
+	}
+	if got, want := f.TimeBase, 0.01; got != want {
+		t.Errorf("frame TimeBase: got %v, want %v", got, want)
+	}
+	if len(f.Channels) != 2 {
+		t.Fatalf("frame channels: got %d, want 2", len(f.Channels))
+	}
+	for i, id := range []scope.ChanID{"sin", "square"} {
+		ch := f.Channels[i]
+		if ch.ID != id || len(ch.Min) != 10 || len(ch.Max) != 10 {
+			t.Errorf("channel %d: got %s with %d/%d columns, want %s with 10 columns", i, ch.ID, len(ch.Min), len(ch.Max), id)
+		}
+	}
+
+	// a masked close frame with status 1000, the mask is all zeros.
+	conn.Write([]byte{0x88, 0x82, 0, 0, 0, 0, 0x03, 0xe8})
+	for {
+		op, payload, err := readFrame(r)
+		if err != nil {
+			t.Fatalf("readFrame waiting for close: %v", err)
+		}
+		if op == opClose {
+			if got, want := payload, []byte{0x03, 0xe8}; !reflect.DeepEqual(got, want) {
+				t.Errorf("close frame payload: got %v, want %v", got, want)
+			}
+			break
+		}
+	}
+}
+
+func TestWebSocketHandshakeErrors(t *testing.T) {
+	_, ts := newTestServer(t)
+	defer ts.Close()
+	for _, tc := range []struct {
+		desc   string
+		header map[string]string
+		want   int
+	}{
+		{"plain GET", nil, http.StatusBadRequest},
+		{"old version", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Key": "x", "Sec-WebSocket-Version": "8"}, http.StatusUpgradeRequired},
+		{"no key", map[string]string{"Connection": "keep-alive, Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13"}, http.StatusBadRequest},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/ws", nil)
+		for k, v := range tc.header {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("%s: %v", tc.desc, err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("%s: got status %d, want %d", tc.desc, resp.StatusCode, tc.want)
+		}
+	}
+}
+
+func getState(t *testing.T, resp *http.Response) State {
+	t.Helper()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var st State
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		t.Fatalf("decoding the state: %v", err)
+	}
+	return st
+}
+
+func paramValue(st State, name string) string {
+	for _, p := range st.Params {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+func TestParams(t *testing.T) {
+	srv, ts := newTestServer(t)
+	defer ts.Close()
+	srv.Start()
+	defer srv.Stop()
+
+	resp, err := http.Get(ts.URL + "/params")
+	if err != nil {
+		t.Fatalf("GET /params: %v", err)
+	}
+	st := getState(t, resp)
+	if !st.Running {
+		t.Errorf("Running: got false, want true")
+	}
+	var names []string
+	for _, p := range st.Params {
+		names = append(names, p.Name)
+	}
+	if got, want := strings.Join(names, ","), "time/div,edge,mode,level,source"; got != want {
+		t.Errorf("params: got %s, want %s", got, want)
+	}
+	if got, want := paramValue(st, "time/div"), "1ms"; got != want {
+		t.Errorf("time/div: got %s, want %s", got, want)
+	}
+
+	for _, tc := range []struct {
+		body  string
+		param string
+		want  string
+	}{
+		{`{"name": "time/div", "step": "inc"}`, "time/div", "2ms"},
+		{`{"name": "mode", "value": "auto"}`, "mode", "auto"},
+		{`{"name": "level", "value": "0.5"}`, "level", "0.5000"},
+	} {
+		resp, err := http.Post(ts.URL+"/params", "application/json", strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatalf("POST /params %s: %v", tc.body, err)
+		}
+		if got := paramValue(getState(t, resp), tc.param); got != tc.want {
+			t.Errorf("POST /params %s: %s got %s, want %s", tc.body, tc.param, got, tc.want)
+		}
+	}
+	if got, want := srv.TimeBase(), 20*scope.Millisecond; got != want {
+		t.Errorf("TimeBase() after changing time/div: got %v, want %v", got, want)
+	}
+
+	for _, tc := range []struct {
+		body string
+		want int
+	}{
+		{`{"name": "nonexistent", "value": "1"}`, http.StatusNotFound},
+		{`{"name": "mode", "value": "sideways"}`, http.StatusBadRequest},
+		{`{"name": "mode", "step": "up"}`, http.StatusBadRequest},
+		{`not json`, http.StatusBadRequest},
+	} {
+		resp, err := http.Post(ts.URL+"/params", "application/json", strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatalf("POST /params %s: %v", tc.body, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("POST /params %s: got status %d, want %d", tc.body, resp.StatusCode, tc.want)
+		}
+	}
+
+	resp, err = http.Post(ts.URL+"/run", "application/json", strings.NewReader(`{"running": false}`))
+	if err != nil {
+		t.Fatalf("POST /run: %v", err)
+	}
+	if getState(t, resp).Running {
+		t.Errorf("Running after stop: got true, want false")
+	}
+}
+
+func TestPage(t *testing.T) {
+	_, ts := newTestServer(t)
+	defer ts.Close()
+	resp, err := http.Get(ts.URL + "/")
+	if err != nil {
+		t.Fatalf("GET /: %v", err)
+	}
+	resp.Body.Close()
+	if got, want := resp.Header.Get("Content-Type"), "text/html; charset=utf-8"; got != want {
+		t.Errorf("Content-Type: got %q, want %q", got, want)
+	}
+	resp, err = http.Get(ts.URL + "/nonexistent")
+	if err != nil {
+		t.Fatalf("GET /nonexistent: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /nonexistent: got status %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestSamplesJSON(t *testing.T) {
+	b, err := json.Marshal(ChannelFrame{
+		ID:  "a",
+		Min: Samples{-1.5, scope.Voltage(math.NaN()), 0},
+		Max: Samples{2, scope.Voltage(math.Inf(1)), scope.Voltage(math.Inf(-1))},
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	if got, want := string(b), `{"id":"a","min":[-1.5,null,0],"max":[2,null,null]}`; got != want {
+		t.Errorf("json.Marshal: got %s, want %s", got, want)
+	}
+}
+
+func TestRejectedChanges(t *testing.T) {
+	_, ts := newTestServer(t)
+	defer ts.Close()
+	for _, tc := range []struct {
+		desc        string
+		path        string
+		origin      string
+		contentType string
+		want        int
+	}{
+		{"other site", "/params", "http://example.com", "application/json", http.StatusForbidden},
+		{"invalid origin", "/run", "::", "application/json", http.StatusForbidden},
+		{"form", "/params", "", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"plain text", "/run", ts.URL, "text/plain", http.StatusUnsupportedMediaType},
+		{"same site", "/run", ts.URL, "application/json; charset=utf-8", http.StatusOK},
+	} {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+tc.path, strings.NewReader(`{"running": false}`))
+		if err != nil {
+			t.Fatalf("%s: NewRequest: %v", tc.desc, err)
+		}
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		req.Header.Set("Content-Type", tc.contentType)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: POST %s: %v", tc.desc, tc.path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("%s: POST %s: got status %d, want %d", tc.desc, tc.path, resp.StatusCode, tc.want)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/ws", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	for k, v := range map[string]string{
+		"Origin":                "http://example.com",
+		"Upgrade":               "websocket",
+		"Connection":            "Upgrade",
+		"Sec-WebSocket-Key":     "dGhlIHNhbXBsZSBub25jZQ==",
+		"Sec-WebSocket-Version": "13",
+	} {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /ws: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("WebSocket from another site: got status %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+}